@@ -3,6 +3,7 @@ package app
 import (
 	"bufio"
 	"fmt"
+	"io/fs"
 	"jz/graph"
 	"jz/model"
 	"jz/scan"
@@ -19,19 +20,27 @@ type Diagnostic struct {
 	HasLiberty       bool // Liberty runtime detected (e.g., server.xml)
 	AnyManifestFound bool // Any MANIFEST.MF found (OSGi or otherwise)
 	HasLibertyWAR    bool // Liberty WAR application modeled as a single service (Phase F2)
+	HasEAR           bool // EAR application.xml detected; web modules modeled as services
 }
 
 // Analyze performs static analysis on the given root directory.
-func Analyze(rootDir string) ([]model.Service, model.SystemGraph, Diagnostic) {
-	diag := Diagnostic{}
-
+func Analyze(rootDir string, opts scan.TreeOptions) ([]model.Service, model.SystemGraph, Diagnostic) {
 	if _, err := os.Stat(rootDir); os.IsNotExist(err) {
 		fmt.Fprintf(os.Stderr, "Error: directory '%s' does not exist\n", rootDir)
 		os.Exit(1)
 	}
+	return AnalyzeFS(os.DirFS(rootDir), rootDir, opts)
+}
+
+// AnalyzeFS performs static analysis on the files of fsys, reported under rootDir. JAR, WAR
+// and EAR archives in fsys are analyzed like the directories they would unpack to;
+// dependency and build-output archives only when opts includes them.
+func AnalyzeFS(fsys fs.FS, rootDir string, opts scan.TreeOptions) ([]model.Service, model.SystemGraph, Diagnostic) {
+	diag := Diagnostic{}
+	tree := scan.NewTree(fsys, rootDir, opts)
 
 	// 1. Discover OSGi Bundles
-	bundles, err := scan.ScanOSGi(rootDir)
+	bundles, err := scan.ScanOSGi(tree, rootDir)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error scanning OSGi bundles: %v\n", err)
 		os.Exit(1)
@@ -41,7 +50,7 @@ func Analyze(rootDir string) ([]model.Service, model.SystemGraph, Diagnostic) {
 	}
 
 	// Check for any MANIFEST.MF (even if not a valid OSGi bundle)
-	filepath.Walk(rootDir, func(path string, info os.FileInfo, err error) error {
+	tree.Walk(rootDir, func(path string, info os.FileInfo, err error) error {
 		if err != nil {
 			return nil
 		}
@@ -53,7 +62,7 @@ func Analyze(rootDir string) ([]model.Service, model.SystemGraph, Diagnostic) {
 	})
 
 	// 2. Extract REST Entry Points (global)
-	entryPoints, err := scan.Scan(rootDir)
+	entryPoints, err := scan.Scan(tree, rootDir)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error scanning JAX-RS entry points: %v\n", err)
 		os.Exit(1)
@@ -65,17 +74,24 @@ func Analyze(rootDir string) ([]model.Service, model.SystemGraph, Diagnostic) {
 		}
 	}
 
+	// 2b. Extract Servlet, SOAP and other protocol artifacts (global); assigned to services by root path
+	artifacts, err := scanArtifacts(tree, rootDir)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error scanning source artifacts: %v\n", err)
+		os.Exit(1)
+	}
+
 	// 3. Find and Parse Liberty Server (if exists)
 	var libertyServer model.LibertyServer
 	var hasLiberty bool
 
 	// Simple search for server.xml
-	filepath.Walk(rootDir, func(path string, info os.FileInfo, err error) error {
+	tree.Walk(rootDir, func(path string, info os.FileInfo, err error) error {
 		if err != nil {
 			return nil // ignore walk errors
 		}
 		if !info.IsDir() && info.Name() == "server.xml" {
-			srv, err := scan.ScanLiberty(path)
+			srv, err := scan.ScanLiberty(tree, path)
 			if err == nil {
 				libertyServer = srv
 				hasLiberty = true
@@ -98,11 +114,12 @@ func Analyze(rootDir string) ([]model.Service, model.SystemGraph, Diagnostic) {
 		svc := model.Service{
 			Name:     bundle.SymbolicName,
 			RootPath: serviceRoot,
+			Tree:     tree,
 		}
 
 		// Attach Entry Points
 		for _, ep := range entryPoints {
-			if strings.HasPrefix(ep.SourceFile, serviceRoot) {
+			if underRoot(ep.SourceFile, serviceRoot) {
 				svc.EntryPoints = append(svc.EntryPoints, ep)
 			}
 		}
@@ -111,14 +128,14 @@ func Analyze(rootDir string) ([]model.Service, model.SystemGraph, Diagnostic) {
 		var compPaths []string
 		for _, sc := range bundle.ServiceComponents {
 			fullPattern := filepath.Join(serviceRoot, sc)
-			matches, err := filepath.Glob(fullPattern)
+			matches, err := tree.Glob(fullPattern)
 			if err == nil {
 				compPaths = append(compPaths, matches...)
 			}
 		}
 
 		if len(compPaths) > 0 {
-			comps, err := scan.ScanDSComponents(compPaths)
+			comps, err := scan.ScanDSComponents(tree, compPaths)
 			if err == nil {
 				svc.Components = comps
 			}
@@ -137,28 +154,15 @@ func Analyze(rootDir string) ([]model.Service, model.SystemGraph, Diagnostic) {
 			}
 		}
 
-		// Group REST Resources
-		svc.RESTResources = groupRESTResources(svc.EntryPoints)
+		// Group REST Resources (WABs may carry a web.xml)
+		svc.WebApp, _ = findWebDescriptor(tree, serviceRoot)
+		attachServletEntryPoints(&svc, artifacts.servlets)
+		svc.RESTResources = groupRESTResources(tree, svc.EntryPoints, svc.WebApp.JAXRSPrefix)
+		applyWebSecurity(&svc)
 
 		// Phase F4: Detect Outbound Calls
-		// Deduplicate outbound REST calls within a single service
-		callMap := make(map[string]bool)
-		for _, res := range svc.RESTResources {
-			for _, ep := range res.EntryPoints {
-				parts := strings.Split(ep.Handler, ".")
-				if len(parts) > 1 {
-					methodName := parts[1]
-					calls := scanOutboundCalls(ep.SourceFile, methodName, svc.Name, res.Name)
-					for _, call := range calls {
-						key := restCallKey(methodName, call)
-						if !callMap[key] {
-							svc.RESTCalls = append(svc.RESTCalls, call)
-							callMap[key] = true
-						}
-					}
-				}
-			}
-		}
+		detectOutboundCalls(&svc)
+		attachArtifacts(&svc, artifacts)
 
 		// Phase F4: Boundary Detection (simplistic package-based)
 		pkgMap := make(map[string]bool)
@@ -184,7 +188,84 @@ func Analyze(rootDir string) ([]model.Service, model.SystemGraph, Diagnostic) {
 		services = append(services, svc)
 	}
 
-	// 4b. Phase F2: Liberty WAR Service Support
+	// 4b. EAR Packaging Support
+	// Every web module declared in META-INF/application.xml becomes its own service,
+	// regardless of whether OSGi bundles were found.
+	osgiRoots := make(map[string]bool)
+	for _, svc := range services {
+		osgiRoots[svc.RootPath] = true
+	}
+
+	ears := findEnterpriseApps(tree, rootDir)
+	if len(ears) > 0 {
+		diag.HasEAR = true
+	}
+	for _, ear := range ears {
+		var earApp model.LibertyApp
+		if hasLiberty {
+			earApp = matchEnterpriseApp(libertyServer.DeployedApps, ear, len(ears))
+		}
+
+		for _, mod := range ear.Modules {
+			if mod.Type != "web" {
+				continue
+			}
+			// A WAB that is already modeled as an OSGi bundle is not duplicated
+			if mod.Path != "" && osgiRoots[mod.Path] {
+				continue
+			}
+
+			svc := model.Service{
+				Name:          strings.TrimSuffix(filepath.Base(mod.URI), filepath.Ext(mod.URI)),
+				RootPath:      mod.Path,
+				Tree:          tree,
+				EnterpriseApp: ear.Name,
+				Application: model.LibertyApp{
+					ID:          earApp.ID,
+					Location:    earApp.Location,
+					Type:        "enterpriseApplication",
+					ContextRoot: normalizePath(mod.ContextRoot),
+				},
+			}
+			if hasLiberty {
+				svc.ServerName = libertyServer.Name
+				svc.Features = libertyServer.EnabledFeatures
+			}
+
+			// Attach Entry Points (only when the module directory was resolved)
+			if mod.Path != "" {
+				for _, ep := range entryPoints {
+					if underRoot(ep.SourceFile, mod.Path) {
+						svc.EntryPoints = append(svc.EntryPoints, ep)
+					}
+				}
+			}
+
+			if mod.Path != "" {
+				svc.WebApp, _ = findWebDescriptor(tree, mod.Path)
+				attachServletEntryPoints(&svc, artifacts.servlets)
+			}
+			svc.RESTResources = groupRESTResources(tree, svc.EntryPoints, svc.WebApp.JAXRSPrefix)
+			applyWebSecurity(&svc)
+
+			// Phase F4: Detect Outbound Calls
+			detectOutboundCalls(&svc)
+			if mod.Path != "" {
+				attachArtifacts(&svc, artifacts)
+			}
+
+			svc.Boundaries = append(svc.Boundaries, model.ServiceBoundary{
+				ServiceName:  svc.Name,
+				BoundaryType: "resource-group",
+				Identifier:   "rest-api",
+				Evidence:     fmt.Sprintf("EAR web module %s declared in %s", mod.URI, ear.ApplicationXML),
+			})
+
+			services = append(services, svc)
+		}
+	}
+
+	// 4c. Phase F2: Liberty WAR Service Support
 	// Detect when no OSGi bundles are present and Liberty is used.
 	if len(services) == 0 && hasLiberty {
 		var hasWebApp bool
@@ -200,17 +281,9 @@ func Analyze(rootDir string) ([]model.Service, model.SystemGraph, Diagnostic) {
 		}
 
 		// Check for WEB-INF/web.xml if not already found via server.xml
-		if !hasWebApp {
-			filepath.Walk(rootDir, func(path string, info os.FileInfo, err error) error {
-				if err != nil {
-					return nil
-				}
-				if !info.IsDir() && info.Name() == "web.xml" && filepath.Base(filepath.Dir(path)) == "WEB-INF" {
-					hasWebApp = true
-					return filepath.SkipDir
-				}
-				return nil
-			})
+		webApp, hasWebXML := findWebDescriptor(tree, rootDir)
+		if hasWebXML {
+			hasWebApp = true
 		}
 
 		if hasWebApp {
@@ -224,32 +297,20 @@ func Analyze(rootDir string) ([]model.Service, model.SystemGraph, Diagnostic) {
 			svc := model.Service{
 				Name:        name,
 				RootPath:    rootDir,
+				Tree:        tree,
 				EntryPoints: entryPoints, // All entry points in repo
 				ServerName:  libertyServer.Name,
 				Features:    libertyServer.EnabledFeatures,
 				Application: libertyApp,
+				WebApp:      webApp,
 			}
-			svc.RESTResources = groupRESTResources(svc.EntryPoints)
+			attachServletEntryPoints(&svc, artifacts.servlets)
+			svc.RESTResources = groupRESTResources(tree, svc.EntryPoints, svc.WebApp.JAXRSPrefix)
+			applyWebSecurity(&svc)
 
 			// Phase F4: Detect Outbound Calls
-			// Deduplicate outbound REST calls within a single service
-			callMap := make(map[string]bool)
-			for _, res := range svc.RESTResources {
-				for _, ep := range res.EntryPoints {
-					parts := strings.Split(ep.Handler, ".")
-					if len(parts) > 1 {
-						methodName := parts[1]
-						calls := scanOutboundCalls(ep.SourceFile, methodName, svc.Name, res.Name)
-						for _, call := range calls {
-							key := restCallKey(methodName, call)
-							if !callMap[key] {
-								svc.RESTCalls = append(svc.RESTCalls, call)
-								callMap[key] = true
-							}
-						}
-					}
-				}
-			}
+			detectOutboundCalls(&svc)
+			attachArtifacts(&svc, artifacts)
 
 			// Phase F4: Boundary Detection
 			svc.Boundaries = append(svc.Boundaries, model.ServiceBoundary{
@@ -264,7 +325,12 @@ func Analyze(rootDir string) ([]model.Service, model.SystemGraph, Diagnostic) {
 	}
 
 	// 5. Link Calls and Deterministic Sorting
+	uniqueServiceNames(services, rootDir)
 	linkCallsToResources(services)
+	linkSOAPCalls(services)
+	resolveMessageEndpoints(services, libertyServer)
+	resolvePersistenceUnits(services, libertyServer)
+	inventoryResponseStatuses(services)
 
 	// 6. Build System Graph
 	sysGraph := graph.BuildSystemGraph(services)
@@ -272,7 +338,36 @@ func Analyze(rootDir string) ([]model.Service, model.SystemGraph, Diagnostic) {
 	return services, sysGraph, diag
 }
 
-func groupRESTResources(eps []model.EntryPoint) []model.RESTResource {
+// uniqueServiceNames tells apart services sharing a name (the same bundle packaged twice,
+// for instance) by appending their root path, relative to rootDir, to the name.
+func uniqueServiceNames(services []model.Service, rootDir string) {
+	count := make(map[string]int)
+	for _, svc := range services {
+		count[svc.Name]++
+	}
+	for i := range services {
+		svc := &services[i]
+		if count[svc.Name] < 2 {
+			continue
+		}
+		root := svc.RootPath
+		if rel, err := filepath.Rel(rootDir, root); err == nil {
+			root = filepath.ToSlash(rel)
+		}
+		name := fmt.Sprintf("%s (%s)", svc.Name, root)
+		for j := range svc.Boundaries {
+			svc.Boundaries[j].ServiceName = name
+		}
+		for j := range svc.RESTCalls {
+			svc.RESTCalls[j].FromService = name
+		}
+		svc.Name = name
+	}
+}
+
+// groupRESTResources groups entry points by resource class.
+// The servlet prefix (from the web.xml JAX-RS servlet mapping) is prepended to every FullPath.
+func groupRESTResources(tree *model.SourceTree, eps []model.EntryPoint, servletPrefix string) []model.RESTResource {
 	groups := make(map[string][]model.EntryPoint)
 	for _, ep := range eps {
 		groups[ep.Resource] = append(groups[ep.Resource], ep)
@@ -288,11 +383,17 @@ func groupRESTResources(eps []model.EntryPoint) []model.RESTResource {
 	for _, name := range names {
 		groupEps := groups[name]
 		sourceFile := groupEps[0].SourceFile
-		meta := scanResourceMetadata(sourceFile, name)
+		meta := scanResourceMetadata(tree, sourceFile, name)
+
+		kind := groupEps[0].Kind
+		if kind == "" {
+			kind = model.EntryPointJAXRS
+		}
 
 		res := model.RESTResource{
 			Name:            name,
 			SourceFile:      sourceFile,
+			Kind:            kind,
 			BasePath:        meta.basePath,
 			AuthAnnotations: meta.auth,
 			Consumes:        meta.consumes,
@@ -305,18 +406,36 @@ func groupRESTResources(eps []model.EntryPoint) []model.RESTResource {
 		// Phase F3.3: Correct SubPath and FullPath computation
 		paramMap := make(map[string]bool)
 		for _, ep := range groupEps {
+			// Servlet url-patterns are context-relative and kept verbatim
+			if kind == model.EntryPointServlet {
+				res.Methods = append(res.Methods, model.RESTMethod{
+					HTTPMethod: ep.Method,
+					SubPath:    ep.Path,
+					FullPath:   ep.Path,
+					Handler:    ep.Handler,
+					SourceFile: ep.SourceFile,
+				})
+				res.HTTPMethods[ep.Method]++
+				continue
+			}
+
 			subPath := ep.Path
 			if meta.basePath != "" && strings.HasPrefix(ep.Path, meta.basePath) {
 				subPath = strings.TrimPrefix(ep.Path, meta.basePath)
 			}
 
-			full := joinPaths(meta.basePath, subPath)
+			full := joinPaths(servletPrefix, joinPaths(meta.basePath, subPath))
 			method := model.RESTMethod{
 				HTTPMethod: ep.Method,
 				SubPath:    normalizePath(subPath),
 				FullPath:   full,
 				Handler:    ep.Handler,
 				SourceFile: ep.SourceFile,
+				Params:     ep.Params,
+				Consumes:   ep.Consumes,
+				Produces:   ep.Produces,
+				Auth:       ep.Auth,
+				Roles:      ep.Roles,
 			}
 			res.Methods = append(res.Methods, method)
 			res.HTTPMethods[ep.Method]++
@@ -337,6 +456,230 @@ func groupRESTResources(eps []model.EntryPoint) []model.RESTResource {
 	return resources
 }
 
+// attachServletEntryPoints turns Servlet API classes under the service root into entry points.
+// URL patterns come from @WebServlet and from web.xml servlet mappings whose servlet-class
+// names the class. Unmapped servlets are skipped. Annotated filters are merged into the
+// service's web descriptor.
+func attachServletEntryPoints(svc *model.Service, classes []scan.ServletClass) {
+	if svc.RootPath == "" {
+		return
+	}
+
+	for _, sc := range classes {
+		if !underRoot(sc.SourceFile, svc.RootPath) {
+			continue
+		}
+
+		if sc.Filter {
+			name := sc.ServletName
+			if name == "" {
+				name = sc.QualifiedName
+			}
+			svc.WebApp.Filters = append(svc.WebApp.Filters, model.ServletFilter{
+				Name:         name,
+				Class:        sc.QualifiedName,
+				URLPatterns:  sc.URLPatterns,
+				ServletNames: sc.ServletNames,
+			})
+			continue
+		}
+
+		var patterns []string
+		seen := make(map[string]bool)
+		for _, p := range sc.URLPatterns {
+			if !seen[p] {
+				patterns = append(patterns, p)
+				seen[p] = true
+			}
+		}
+		declared := false
+		for _, ws := range svc.WebApp.Servlets {
+			if ws.Class != sc.QualifiedName {
+				continue
+			}
+			declared = true
+			for _, p := range ws.URLPatterns {
+				if !seen[p] {
+					patterns = append(patterns, p)
+					seen[p] = true
+				}
+			}
+		}
+
+		if sc.Annotated && !declared {
+			name := sc.ServletName
+			if name == "" {
+				name = sc.QualifiedName
+			}
+			svc.WebApp.Servlets = append(svc.WebApp.Servlets, model.Servlet{
+				Name:        name,
+				Class:       sc.QualifiedName,
+				URLPatterns: sc.URLPatterns,
+			})
+		}
+
+		for _, p := range patterns {
+			for _, h := range sc.Handlers {
+				svc.EntryPoints = append(svc.EntryPoints, model.EntryPoint{
+					Method:     servletHTTPMethod(h),
+					Path:       p,
+					Handler:    sc.Name + "." + h,
+					SourceFile: sc.SourceFile,
+					Resource:   sc.Name,
+					Kind:       model.EntryPointServlet,
+				})
+			}
+		}
+	}
+}
+
+// servletHTTPMethod maps an HttpServlet handler name to the HTTP method it serves.
+func servletHTTPMethod(handler string) string {
+	if handler == "service" {
+		return model.HTTPMethodAny
+	}
+	return strings.ToUpper(strings.TrimPrefix(handler, "do"))
+}
+
+// findWebDescriptor locates and parses the first WEB-INF/web.xml under root.
+func findWebDescriptor(tree *model.SourceTree, root string) (model.WebDescriptor, bool) {
+	var desc model.WebDescriptor
+	var found bool
+
+	tree.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil || found {
+			return nil
+		}
+		if !info.IsDir() && info.Name() == "web.xml" && filepath.Base(filepath.Dir(path)) == "WEB-INF" {
+			d, err := scan.ScanWebXML(tree, path)
+			if err == nil {
+				desc = d
+				found = true
+			}
+		}
+		return nil
+	})
+
+	return desc, found
+}
+
+// applyWebSecurity records which REST methods are covered by web.xml security constraints.
+// Per the Servlet specification, only the constraints of the best-matching url-pattern
+// apply to a request (exact match, then the longest path prefix, then extension, then
+// default), among constraints whose HTTP methods cover the method. For that pattern, an
+// auth-constraint without roles denies all access; otherwise a constraint without
+// auth-constraint leaves the method unconstrained, and the roles of the others combine.
+func applyWebSecurity(svc *model.Service) {
+	if len(svc.WebApp.SecurityConstraints) == 0 {
+		return
+	}
+
+	for r := range svc.RESTResources {
+		for m := range svc.RESTResources[r].Methods {
+			method := &svc.RESTResources[r].Methods[m]
+
+			// Select the best-matching pattern, then the constraints declaring it
+			best := -1
+			var applicable []model.SecurityConstraint
+			for _, c := range svc.WebApp.SecurityConstraints {
+				if !constraintAppliesToMethod(c, method.HTTPMethod) {
+					continue
+				}
+				score := -1
+				for _, p := range c.URLPatterns {
+					if s := urlPatternScore(p, method.FullPath); s > score {
+						score = s
+					}
+				}
+				switch {
+				case score < 0 || score < best:
+					continue
+				case score > best:
+					best, applicable = score, nil
+				}
+				applicable = append(applicable, c)
+			}
+
+			roleSet := make(map[string]bool)
+			denyAll, unconstrained := false, len(applicable) == 0
+			for _, c := range applicable {
+				switch {
+				case !c.AuthConstraint:
+					unconstrained = true
+				case len(c.Roles) == 0:
+					denyAll = true
+				}
+				for _, role := range c.Roles {
+					roleSet[role] = true
+				}
+			}
+			if denyAll {
+				method.WebConstrained = true
+				continue
+			}
+			if unconstrained {
+				continue
+			}
+			method.WebConstrained = true
+			for role := range roleSet {
+				method.WebRoles = append(method.WebRoles, role)
+			}
+			sort.Strings(method.WebRoles)
+		}
+	}
+}
+
+// urlPatternScore ranks how specifically a url-pattern matches a path: exact matches
+// rank above path prefixes (longer first), then extensions, then the default pattern.
+// It returns -1 when the pattern does not match.
+func urlPatternScore(pattern, path string) int {
+	if !matchURLPattern(pattern, path) {
+		return -1
+	}
+	switch {
+	case pattern == "/":
+		return 0
+	case strings.HasPrefix(pattern, "*."):
+		return 1
+	case strings.HasSuffix(pattern, "/*"):
+		return 2 + len(pattern)
+	default:
+		return 1 << 30 // Exact
+	}
+}
+
+func constraintAppliesToMethod(c model.SecurityConstraint, httpMethod string) bool {
+	if len(c.HTTPMethods) > 0 {
+		for _, m := range c.HTTPMethods {
+			if m == httpMethod {
+				return true
+			}
+		}
+		return false
+	}
+	for _, m := range c.HTTPMethodOmissions {
+		if m == httpMethod {
+			return false
+		}
+	}
+	return true
+}
+
+// matchURLPattern implements Servlet url-pattern matching (exact, path-prefix, extension, default).
+func matchURLPattern(pattern, path string) bool {
+	switch {
+	case pattern == "/" || pattern == "/*":
+		return true
+	case strings.HasSuffix(pattern, "/*"):
+		prefix := strings.TrimSuffix(pattern, "/*")
+		return path == prefix || strings.HasPrefix(path, prefix+"/")
+	case strings.HasPrefix(pattern, "*."):
+		return strings.HasSuffix(path, pattern[1:])
+	default:
+		return pattern == path
+	}
+}
+
 type resourceMeta struct {
 	basePath string
 	auth     []string
@@ -355,8 +698,8 @@ type resourceMeta struct {
 // - No constant evaluation: Constants (e.g., MediaType.APPLICATION_JSON) are not resolved.
 // - False negatives preferred: Items are skipped if parsing is ambiguous (favors safety over completeness).
 // - Media-type parsing (@Consumes, @Produces) only supports literal string values.
-func scanResourceMetadata(javaFilePath string, className string) resourceMeta {
-	f, err := os.Open(javaFilePath)
+func scanResourceMetadata(tree *model.SourceTree, javaFilePath string, className string) resourceMeta {
+	f, err := tree.Open(javaFilePath)
 	if err != nil {
 		return resourceMeta{}
 	}
@@ -371,8 +714,6 @@ func scanResourceMetadata(javaFilePath string, className string) resourceMeta {
 	consumesMap := make(map[string]bool)
 	producesMap := make(map[string]bool)
 
-	authPrefixes := []string{"@RolesAllowed", "@PermitAll", "@DenyAll", "@Authenticated", "@RequiresRole", "@Secured"}
-
 	for scanner.Scan() {
 		line := strings.TrimSpace(scanner.Text())
 		if line == "" {
@@ -388,7 +729,7 @@ func scanResourceMetadata(javaFilePath string, className string) resourceMeta {
 		}
 
 		// 2. Detect Auth
-		for _, pref := range authPrefixes {
+		for _, pref := range model.AuthAnnotations {
 			if strings.HasPrefix(line, pref) {
 				authMap[pref] = true
 			}
@@ -502,8 +843,8 @@ func joinPaths(base, sub string) string {
 // - No control-flow analysis: All detected calls are recorded regardless of execution path.
 // - No constant evaluation: Parameterized or constant-based URLs are skipped (Confidence: Low).
 // - False negatives preferred: Ambiguous or complex call patterns are intentionally ignored.
-func scanOutboundCalls(sourceFile, methodName, fromService, fromResource string) []model.RESTCall {
-	f, err := os.Open(sourceFile)
+func scanOutboundCalls(tree *model.SourceTree, sourceFile, methodName, fromService, fromResource string) []model.RESTCall {
+	f, err := tree.Open(sourceFile)
 	if err != nil {
 		return nil
 	}
@@ -588,6 +929,41 @@ type targetResource struct {
 	resourceName string
 }
 
+type servletTarget struct {
+	targetResource
+	httpMethod string
+	pattern    string
+}
+
+// matchServletTargets returns the servlet targets whose url-pattern and method accept the call.
+// Exact patterns only match service() handlers here (exact method matches use the registry).
+// Catch-all patterns ("/" and "/*") are never matched to avoid speculative links.
+// If serviceName is set, only that service's servlets are considered.
+func matchServletTargets(targets []servletTarget, httpMethod, path, serviceName string) []targetResource {
+	var matches []targetResource
+	seen := make(map[targetResource]bool)
+	for _, t := range targets {
+		if serviceName != "" && t.serviceName != serviceName {
+			continue
+		}
+		if t.pattern == "/" || t.pattern == "/*" {
+			continue
+		}
+		if t.httpMethod != model.HTTPMethodAny && t.httpMethod != httpMethod {
+			continue
+		}
+		isWildcard := strings.Contains(t.pattern, "*")
+		if !isWildcard && t.httpMethod != model.HTTPMethodAny {
+			continue
+		}
+		if matchURLPattern(t.pattern, path) && !seen[t.targetResource] {
+			matches = append(matches, t.targetResource)
+			seen[t.targetResource] = true
+		}
+	}
+	return matches
+}
+
 // linkCallsToResources attempts to link detected calls to known resources.
 // It prioritizes same-service links, then attempts cross-service resolution
 // if a unique match exists globally (AST-lite conservative matching).
@@ -606,6 +982,23 @@ func linkCallsToResources(services []model.Service) {
 		}
 	}
 
+	// 1b. Servlet targets: wildcard url-patterns and service() handlers need pattern matching
+	var servletTargets []servletTarget
+	for _, svc := range services {
+		for _, res := range svc.RESTResources {
+			if res.Kind != model.EntryPointServlet {
+				continue
+			}
+			for _, m := range res.Methods {
+				servletTargets = append(servletTargets, servletTarget{
+					targetResource: targetResource{serviceName: svc.Name, resourceName: res.Name},
+					httpMethod:     m.HTTPMethod,
+					pattern:        m.FullPath,
+				})
+			}
+		}
+	}
+
 	for i := range services {
 		// 2. Build map of paths for the same service for fast priority lookup
 		sameServiceMap := make(map[string][]string) // path -> []resourceName
@@ -634,6 +1027,11 @@ func linkCallsToResources(services []model.Service) {
 					call.ResolutionScope = model.ResolutionSameService
 					call.ResolutionEvidence = "exact path+method match (internal)"
 				}
+			} else if matches := matchServletTargets(servletTargets, call.HTTPMethod, call.TargetPath, services[i].Name); len(matches) == 1 {
+				call.TargetService = services[i].Name
+				call.TargetResource = matches[0].resourceName
+				call.ResolutionScope = model.ResolutionSameService
+				call.ResolutionEvidence = "servlet url-pattern match (internal)"
 			}
 
 			// 2b. Attempt cross-service resolution (Priority 2)
@@ -649,6 +1047,11 @@ func linkCallsToResources(services []model.Service) {
 						call.ResolutionScope = model.ResolutionCrossService
 						call.ResolutionEvidence = "exact path+method match (global)"
 					}
+				} else if matches := matchServletTargets(servletTargets, call.HTTPMethod, call.TargetPath, ""); len(matches) == 1 {
+					call.TargetService = matches[0].serviceName
+					call.TargetResource = matches[0].resourceName
+					call.ResolutionScope = model.ResolutionCrossService
+					call.ResolutionEvidence = "servlet url-pattern match (global)"
 				}
 			}
 
@@ -698,6 +1101,114 @@ func linkCallsToResources(services []model.Service) {
 	}
 }
 
+// detectOutboundCalls scans every handler of the service for outbound REST calls.
+// Calls are deduplicated within the service by handler, method and target path.
+func detectOutboundCalls(svc *model.Service) {
+	callMap := make(map[string]bool)
+	for _, res := range svc.RESTResources {
+		for _, ep := range res.EntryPoints {
+			parts := strings.Split(ep.Handler, ".")
+			if len(parts) > 1 {
+				methodName := parts[1]
+				calls := scanOutboundCalls(svc.Tree, ep.SourceFile, methodName, svc.Name, res.Name)
+				for _, call := range calls {
+					key := restCallKey(methodName, call)
+					if !callMap[key] {
+						svc.RESTCalls = append(svc.RESTCalls, call)
+						callMap[key] = true
+					}
+				}
+			}
+		}
+	}
+}
+
+// findEnterpriseApps locates META-INF/application.xml descriptors under rootDir
+// and resolves the directory of every declared module.
+//
+// Module resolution is conservative:
+//  1. An exploded module directory next to META-INF (e.g. <ear>/orders-web.war) wins.
+//  2. Otherwise a directory named after the module URI, with or without its extension,
+//     is used only if exactly one such directory exists under rootDir.
+//
+// Unresolved modules keep an empty Path.
+func findEnterpriseApps(tree *model.SourceTree, rootDir string) []model.EnterpriseApp {
+	var ears []model.EnterpriseApp
+	dirsByName := make(map[string][]string)
+
+	tree.Walk(rootDir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return nil
+		}
+		if info.IsDir() {
+			dirsByName[info.Name()] = append(dirsByName[info.Name()], path)
+			return nil
+		}
+		if info.Name() == "application.xml" && filepath.Base(filepath.Dir(path)) == "META-INF" {
+			ear, err := scan.ScanApplicationXML(tree, path)
+			if err == nil {
+				ears = append(ears, ear)
+			}
+		}
+		return nil
+	})
+
+	for i := range ears {
+		for j := range ears[i].Modules {
+			mod := &ears[i].Modules[j]
+
+			exploded := filepath.Join(ears[i].RootPath, mod.URI)
+			if info, err := tree.Stat(exploded); err == nil && info.IsDir() {
+				mod.Path = exploded
+				continue
+			}
+
+			base := filepath.Base(mod.URI)
+			candidates := dirsByName[base]
+			if len(candidates) == 0 {
+				candidates = dirsByName[strings.TrimSuffix(base, filepath.Ext(base))]
+			}
+			if len(candidates) == 1 {
+				mod.Path = candidates[0]
+			}
+		}
+	}
+
+	sort.Slice(ears, func(i, j int) bool {
+		return ears[i].ApplicationXML < ears[j].ApplicationXML
+	})
+	return ears
+}
+
+// matchEnterpriseApp links an EAR to its server.xml deployment.
+// An enterpriseApplication (or an application with an .ear location) matches when its
+// id or location name equals the EAR name or directory. If the server deploys exactly
+// one EAR and exactly one application.xml was found, the two are linked.
+func matchEnterpriseApp(apps []model.LibertyApp, ear model.EnterpriseApp, earCount int) model.LibertyApp {
+	var earApps []model.LibertyApp
+	for _, a := range apps {
+		if a.Type == "enterpriseApplication" || strings.HasSuffix(strings.ToLower(a.Location), ".ear") {
+			earApps = append(earApps, a)
+		}
+	}
+
+	names := map[string]bool{
+		ear.Name:                    true,
+		filepath.Base(ear.RootPath): true,
+	}
+	for _, a := range earApps {
+		locName := strings.TrimSuffix(filepath.Base(a.Location), filepath.Ext(a.Location))
+		if names[a.ID] || names[locName] {
+			return a
+		}
+	}
+
+	if len(earApps) == 1 && earCount == 1 {
+		return earApps[0]
+	}
+	return model.LibertyApp{}
+}
+
 // restCallKey generates a unique key for deduplicating outbound calls within a service.
 func restCallKey(methodName string, call model.RESTCall) string {
 	return fmt.Sprintf("%s|%s|%s", methodName, call.HTTPMethod, call.TargetPath)
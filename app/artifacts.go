@@ -0,0 +1,123 @@
+package app
+
+import (
+	"jz/model"
+	"jz/scan"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// sourceArtifacts holds repository-wide scan results that are assigned to
+// services by root path once the services have been assembled.
+type sourceArtifacts struct {
+	servlets      []scan.ServletClass
+	soapEndpoints []model.SOAPEndpoint
+	soapCalls     []model.SOAPCall
+	wsdls         []model.WSDLDefinition
+	messaging     []model.MessageEndpoint
+	channelConfig []model.ChannelConfig
+	beans         scan.BeanInventory
+	mappers       []model.ExceptionMapper
+	dataAccesses  []model.DataAccess
+	namedQueries  []model.NamedQuery
+	entities      []model.Entity
+	units         []model.PersistenceUnit
+}
+
+// scanArtifacts runs the protocol scanners that are not tied to a single service model.
+func scanArtifacts(tree *model.SourceTree, rootDir string) (sourceArtifacts, error) {
+	var a sourceArtifacts
+	var err error
+
+	if a.servlets, err = scan.ScanServlets(tree, rootDir); err != nil {
+		return a, err
+	}
+	if a.soapEndpoints, err = scan.ScanSOAPEndpoints(tree, rootDir); err != nil {
+		return a, err
+	}
+	if a.soapCalls, err = scan.ScanSOAPClients(tree, rootDir); err != nil {
+		return a, err
+	}
+	if a.messaging, err = scan.ScanJMS(tree, rootDir); err != nil {
+		return a, err
+	}
+	reactive, err := scan.ScanReactiveMessaging(tree, rootDir)
+	if err != nil {
+		return a, err
+	}
+	a.messaging = append(a.messaging, reactive...)
+	if a.beans, err = scan.ScanBeans(tree, rootDir); err != nil {
+		return a, err
+	}
+	if a.mappers, err = scan.ScanExceptionMappers(tree, rootDir); err != nil {
+		return a, err
+	}
+	if a.dataAccesses, err = scan.ScanDataAccess(tree, rootDir); err != nil {
+		return a, err
+	}
+	if a.namedQueries, err = scan.ScanNamedQueries(tree, rootDir); err != nil {
+		return a, err
+	}
+	if a.entities, err = scan.ScanEntities(tree, rootDir); err != nil {
+		return a, err
+	}
+
+	tree.Walk(rootDir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return nil
+		}
+		if info.IsDir() {
+			return nil
+		}
+		switch {
+		case strings.EqualFold(filepath.Ext(path), ".wsdl"):
+			def, err := scan.ScanWSDL(tree, path)
+			if err == nil {
+				a.wsdls = append(a.wsdls, def)
+			}
+		case info.Name() == "persistence.xml":
+			units, err := scan.ScanPersistenceXML(tree, path)
+			if err == nil {
+				a.units = append(a.units, units...)
+			}
+		case info.Name() == "microprofile-config.properties" || info.Name() == "application.properties":
+			configs, err := scan.ScanMessagingConfig(tree, path)
+			if err == nil {
+				a.channelConfig = append(a.channelConfig, configs...)
+			}
+		}
+		return nil
+	})
+
+	return a, nil
+}
+
+// attachArtifacts assigns protocol artifacts located under the service root to the service.
+func attachArtifacts(svc *model.Service, a sourceArtifacts) {
+	if svc.RootPath == "" {
+		return
+	}
+	attachSOAP(svc, a)
+	attachMessaging(svc, a)
+	attachBeans(svc, a.beans)
+	attachPersistence(svc, a)
+	for _, m := range a.mappers {
+		if underRoot(m.SourceFile, svc.RootPath) {
+			svc.ExceptionMappers = append(svc.ExceptionMappers, m)
+		}
+	}
+}
+
+// underRoot reports whether path lies within the service root. Sibling roots sharing a
+// name prefix (com.acme.orders and com.acme.orders.impl) do not contain each other.
+func underRoot(path, root string) bool {
+	if root == "" {
+		return false
+	}
+	if root == "." {
+		return !filepath.IsAbs(path) && path != ".." && !strings.HasPrefix(path, "../")
+	}
+	root = strings.TrimSuffix(root, "/")
+	return path == root || strings.HasPrefix(path, root+"/")
+}
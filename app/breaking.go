@@ -0,0 +1,71 @@
+package app
+
+import (
+	"fmt"
+	"jz/model"
+	"slices"
+)
+
+// classifyEndpointChange records whether an endpoint change breaks existing clients, and why.
+// Breaking changes are:
+//   - a removed endpoint, or a changed path or HTTP method
+//   - a removed @Produces media type (clients may rely on it)
+//   - a removed @Consumes media type, or @Consumes declared where any type was accepted
+//     (media types are only compared when every value on both sides is resolved)
+//   - an added auth restriction (any auth annotation but @PermitAll), a role no longer
+//     listed by @RolesAllowed, or a new web.xml security-constraint
+//
+// Added endpoints and every other change are non-breaking.
+func classifyEndpointChange(c *model.EndpointChange) {
+	var reasons []string
+	switch c.Kind {
+	case model.ChangeRemoved:
+		reasons = append(reasons, "endpoint removed")
+	case model.ChangeChanged:
+		b, a := c.Before, c.After
+		if b.HTTPMethod != a.HTTPMethod {
+			reasons = append(reasons, fmt.Sprintf("HTTP method changed (%s -> %s)", b.HTTPMethod, a.HTTPMethod))
+		}
+		if b.Path != a.Path {
+			reasons = append(reasons, fmt.Sprintf("path changed (%s -> %s)", b.Path, a.Path))
+		}
+		// Media types the analysis could not resolve may name any type: not compared
+		if model.MediaTypesKnown(b.Produces) && model.MediaTypesKnown(a.Produces) {
+			for _, mt := range b.Produces {
+				if !slices.Contains(a.Produces, mt) {
+					reasons = append(reasons, fmt.Sprintf("@Produces %s removed", mt))
+				}
+			}
+		}
+		if model.MediaTypesKnown(b.Consumes) && model.MediaTypesKnown(a.Consumes) {
+			if len(b.Consumes) == 0 && len(a.Consumes) > 0 {
+				for _, mt := range a.Consumes {
+					reasons = append(reasons, fmt.Sprintf("@Consumes %s newly required", mt))
+				}
+			}
+			for _, mt := range b.Consumes {
+				if !slices.Contains(a.Consumes, mt) {
+					reasons = append(reasons, fmt.Sprintf("@Consumes %s no longer accepted", mt))
+				}
+			}
+		}
+		for _, ann := range a.Auth {
+			if ann != model.AuthPermitAll && !slices.Contains(b.Auth, ann) {
+				reasons = append(reasons, fmt.Sprintf("%s restriction added", ann))
+			}
+		}
+		// Roles are only known when all are literals: no roles is not compared
+		if len(b.Roles) > 0 && len(a.Roles) > 0 {
+			for _, role := range b.Roles {
+				if !slices.Contains(a.Roles, role) {
+					reasons = append(reasons, fmt.Sprintf("@RolesAllowed role %s no longer allowed", role))
+				}
+			}
+		}
+		if a.WebSecured && !b.WebSecured {
+			reasons = append(reasons, "web.xml security-constraint added")
+		}
+	}
+	c.Breaking = len(reasons) > 0
+	c.BreakingReasons = reasons
+}
@@ -0,0 +1,210 @@
+package app
+
+import (
+	"jz/model"
+	"jz/scan"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// attachBeans assigns the CDI/EJB beans and injection points under the service root to the
+// service, resolves every injection point and, for services without DS components, builds the
+// internal dependency graph from the resolved injections.
+func attachBeans(svc *model.Service, inv scan.BeanInventory) {
+	mode := beanDiscoveryMode(svc.Tree, svc.RootPath)
+	if mode == "none" {
+		return
+	}
+
+	for _, b := range inv.Beans {
+		if underRoot(b.SourceFile, svc.RootPath) {
+			svc.Beans = append(svc.Beans, b)
+		}
+	}
+	// In an explicit bean archive every concrete class is a @Dependent bean
+	if mode == "all" {
+		for _, b := range inv.PlainClasses {
+			if underRoot(b.SourceFile, svc.RootPath) {
+				b.Scope = "Dependent"
+				svc.Beans = append(svc.Beans, b)
+			}
+		}
+	}
+	for _, ip := range inv.InjectionPoints {
+		if underRoot(ip.SourceFile, svc.RootPath) {
+			svc.InjectionPoints = append(svc.InjectionPoints, ip)
+		}
+	}
+
+	for i := range svc.InjectionPoints {
+		resolveInjectionPoint(&svc.InjectionPoints[i], svc.Beans, inv.DeclaredTypes)
+	}
+
+	if len(svc.Components) == 0 && len(svc.InjectionPoints) > 0 {
+		svc.InternalGraph = buildBeanGraph(svc.Beans, svc.InjectionPoints)
+	}
+}
+
+// beanDiscoveryMode returns the discovery mode of the first beans.xml under
+// WEB-INF or META-INF in the service root. Without beans.xml only annotated beans are discovered.
+func beanDiscoveryMode(tree *model.SourceTree, root string) string {
+	mode := "annotated"
+	tree.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return nil
+		}
+		if info.IsDir() || info.Name() != "beans.xml" {
+			return nil
+		}
+		parent := filepath.Base(filepath.Dir(path))
+		if parent != "WEB-INF" && parent != "META-INF" {
+			return nil
+		}
+		if m, err := scan.ScanBeansXML(tree, path); err == nil {
+			mode = m
+			return filepath.SkipAll
+		}
+		return nil
+	})
+	return mode
+}
+
+// resolveInjectionPoint applies CDI typesafe resolution: a bean matches when it has the
+// required type and every required qualifier. An injection point without qualifiers
+// requires @Default (a bean with no qualifiers other than @Named). @EJB injection
+// matches by type and optional beanName. Enabled alternatives win over other candidates.
+func resolveInjectionPoint(ip *model.InjectionPoint, beans []model.Bean, declared map[string]bool) {
+	var required []string
+	anyQualifier := false
+	for _, q := range ip.Qualifiers {
+		switch q {
+		case "Default":
+		case "Any":
+			anyQualifier = true
+		default:
+			required = append(required, q)
+		}
+	}
+
+	var candidates []model.Bean
+	for _, b := range beans {
+		if !slices.Contains(b.Types, ip.Type) {
+			continue
+		}
+		if ip.Annotation == "EJB" {
+			if ip.BeanName != "" && b.Name != ip.BeanName && b.Class != ip.BeanName {
+				continue
+			}
+			candidates = append(candidates, b)
+			continue
+		}
+		if !anyQualifier && !qualifiersMatch(required, b.Qualifiers) {
+			continue
+		}
+		candidates = append(candidates, b)
+	}
+
+	if len(candidates) > 1 {
+		var alternatives []model.Bean
+		for _, b := range candidates {
+			if b.Alternative {
+				alternatives = append(alternatives, b)
+			}
+		}
+		if len(alternatives) > 0 {
+			candidates = alternatives
+		}
+	}
+
+	qualifierLabel := "@Default"
+	if len(required) > 0 {
+		qualifierLabel = "@" + strings.Join(required, ", @")
+	} else if anyQualifier {
+		qualifierLabel = "@Any"
+	}
+
+	switch {
+	case len(candidates) == 1:
+		ip.Status = model.InjectionResolved
+		ip.Bean = candidates[0].Name
+		ip.Explanation = "unique bean of type " + ip.Type + " with " + qualifierLabel + " (" + candidates[0].Evidence + ")"
+	case len(candidates) > 1:
+		ip.Status = model.InjectionAmbiguous
+		for _, b := range candidates {
+			ip.Candidates = append(ip.Candidates, b.Name)
+		}
+		sort.Strings(ip.Candidates)
+		ip.Explanation = "multiple beans of type " + ip.Type + " with " + qualifierLabel
+	case declared[ip.Type]:
+		ip.Status = model.InjectionUnsatisfied
+		ip.Explanation = "no bean of type " + ip.Type + " with " + qualifierLabel + " in this service"
+	default:
+		ip.Status = model.InjectionExternal
+		ip.Explanation = "type " + ip.Type + " is not declared in the scanned sources"
+	}
+}
+
+// qualifiersMatch reports whether the bean carries every required qualifier. With no
+// required qualifiers the bean must be a @Default bean.
+func qualifiersMatch(required, beanQualifiers []string) bool {
+	if len(required) == 0 {
+		for _, q := range beanQualifiers {
+			if q != "Default" && !strings.HasPrefix(q, "Named(") {
+				return false
+			}
+		}
+		return true
+	}
+	for _, q := range required {
+		if !slices.Contains(beanQualifiers, q) {
+			return false
+		}
+	}
+	return true
+}
+
+// buildBeanGraph creates a component graph with one node per bean (and per injecting class
+// that is not itself a bean) and one edge per resolved injection point.
+func buildBeanGraph(beans []model.Bean, ips []model.InjectionPoint) model.DependencyGraph {
+	g := model.DependencyGraph{
+		Nodes: make([]model.ComponentNode, 0),
+		Edges: make([]model.DependencyEdge, 0),
+	}
+
+	nodeByClass := make(map[string]string)
+	seenNodes := make(map[string]bool)
+	for _, b := range beans {
+		if b.Kind != model.BeanProducer {
+			nodeByClass[b.Class] = b.Name
+		}
+		if !seenNodes[b.Name] {
+			g.Nodes = append(g.Nodes, model.ComponentNode{Name: b.Name, ImplementationClass: b.QualifiedName})
+			seenNodes[b.Name] = true
+		}
+	}
+
+	seenEdges := make(map[model.DependencyEdge]bool)
+	for _, ip := range ips {
+		from, ok := nodeByClass[ip.Class]
+		if !ok {
+			from = ip.Class
+			if !seenNodes[from] {
+				g.Nodes = append(g.Nodes, model.ComponentNode{Name: from, ImplementationClass: ip.Class})
+				seenNodes[from] = true
+			}
+		}
+		if ip.Status != model.InjectionResolved || ip.Bean == from {
+			continue
+		}
+		edge := model.DependencyEdge{FromComponent: from, ToComponent: ip.Bean, Interface: ip.Type}
+		if !seenEdges[edge] {
+			g.Edges = append(g.Edges, edge)
+			seenEdges[edge] = true
+		}
+	}
+
+	return g
+}
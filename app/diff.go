@@ -2,10 +2,13 @@ package app
 
 import (
 	"jz/model"
+	"path/filepath"
+	"strings"
 )
 
-// DiffFlows compares two sets of execution flows for the same resource.
-func DiffFlows(flowsA, flowsB []model.ExecutionFlow) []model.FlowDiff {
+// DiffFlows compares two sets of execution flows for the same resource. Steps are aligned
+// on their longest common subsequence; strict compares them index by index instead.
+func DiffFlows(flowsA, flowsB []model.ExecutionFlow, strict bool) []model.FlowDiff {
 	diffs := []model.FlowDiff{}
 
 	// Map flows by EntryPoint for lookup
@@ -64,7 +67,11 @@ func DiffFlows(flowsA, flowsB []model.ExecutionFlow) []model.FlowDiff {
 			}
 		} else {
 			// Both exist, diff steps
-			diff.StepDiffs = diffSteps(flowA.Steps, flowB.Steps)
+			if strict {
+				diff.StepDiffs = diffStepsByIndex(flowA.Steps, flowB.Steps)
+			} else {
+				diff.StepDiffs = diffSteps(flowA.Steps, flowB.Steps)
+			}
 			diff.Status = "UNCHANGED"
 			for _, sd := range diff.StepDiffs {
 				if sd.Kind != model.StepUnchanged {
@@ -79,10 +86,112 @@ func DiffFlows(flowsA, flowsB []model.ExecutionFlow) []model.FlowDiff {
 	return diffs
 }
 
-// diffSteps performs a sequence-based diff of flow steps.
-// Since the requirement says "NO reordering" and "ordered comparison",
-// we compare them index by index.
+// diffSteps aligns the steps of two flows on their longest common subsequence of equal
+// steps. Between two aligned steps, a removed and an added step of the same kind in the
+// same method and file are paired as a modification.
 func diffSteps(stepsA, stepsB []model.FlowStep) []model.StepDiff {
+	// lcs[i][j] is the length of the common subsequence of stepsA[i:] and stepsB[j:]
+	lcs := make([][]int, len(stepsA)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(stepsB)+1)
+	}
+	for i := len(stepsA) - 1; i >= 0; i-- {
+		for j := len(stepsB) - 1; j >= 0; j-- {
+			if stepsEqual(stepsA[i], stepsB[j]) {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	diffs := []model.StepDiff{}
+	var removed, added []model.FlowStep
+	flush := func() {
+		diffs = append(diffs, pairModifications(removed, added)...)
+		removed, added = nil, nil
+	}
+	i, j := 0, 0
+	for i < len(stepsA) || j < len(stepsB) {
+		switch {
+		case i < len(stepsA) && j < len(stepsB) && stepsEqual(stepsA[i], stepsB[j]):
+			flush()
+			sA, sB := stepsA[i], stepsB[j]
+			diffs = append(diffs, model.StepDiff{Kind: model.StepUnchanged, Before: &sA, After: &sB})
+			i++
+			j++
+		case j == len(stepsB) || (i < len(stepsA) && lcs[i+1][j] >= lcs[i][j+1]):
+			removed = append(removed, stepsA[i])
+			i++
+		default:
+			added = append(added, stepsB[j])
+			j++
+		}
+	}
+	flush()
+	return diffs
+}
+
+// pairModifications turns the steps removed and added between two aligned steps into
+// step diffs. Each removed step is paired with the first unpaired added step of the same
+// kind, method and file; unpaired steps stay removals and additions, in order.
+func pairModifications(removed, added []model.FlowStep) []model.StepDiff {
+	pairedWith := make([]int, len(removed))
+	used := make([]bool, len(added))
+	for r := range removed {
+		pairedWith[r] = -1
+		for a := range added {
+			if !used[a] && sameLocation(removed[r], added[a]) {
+				pairedWith[r] = a
+				used[a] = true
+				break
+			}
+		}
+	}
+
+	var diffs []model.StepDiff
+	a := 0
+	emitAdded := func(limit int) {
+		for ; a < limit; a++ {
+			if !used[a] {
+				sB := added[a]
+				diffs = append(diffs, model.StepDiff{Kind: model.StepAdded, After: &sB})
+			}
+		}
+	}
+	for r := range removed {
+		sA := removed[r]
+		if pairedWith[r] < 0 {
+			diffs = append(diffs, model.StepDiff{Kind: model.StepRemoved, Before: &sA})
+			continue
+		}
+		// Additions placed before the modified step keep their position
+		emitAdded(pairedWith[r])
+		sB := added[pairedWith[r]]
+		diffs = append(diffs, model.StepDiff{Kind: model.StepModified, Before: &sA, After: &sB})
+	}
+	emitAdded(len(added))
+	return diffs
+}
+
+// sameLocation reports whether two steps have the same kind and come from the same method
+// and source file. Line numbers and the directories of the compared trees are ignored.
+func sameLocation(a, b model.FlowStep) bool {
+	return a.Kind == b.Kind && a.FromMethod == b.FromMethod && evidenceFile(a.Evidence) == evidenceFile(b.Evidence)
+}
+
+// evidenceFile returns the file name of a "file:line" or "file (start)" evidence.
+func evidenceFile(evidence string) string {
+	evidence = strings.TrimSuffix(evidence, " (start)")
+	if i := strings.LastIndex(evidence, ":"); i >= 0 && !strings.ContainsAny(evidence[i+1:], "/\\") {
+		evidence = evidence[:i]
+	}
+	return filepath.Base(evidence)
+}
+
+// diffStepsByIndex compares the steps of two flows index by index (strict mode): any
+// insertion marks every later step modified.
+func diffStepsByIndex(stepsA, stepsB []model.FlowStep) []model.StepDiff {
 	diffs := []model.StepDiff{}
 	maxLen := len(stepsA)
 	if len(stepsB) > maxLen {
@@ -0,0 +1,308 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+	"jz/graph"
+	"jz/model"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"go.yaml.in/yaml/v3"
+)
+
+// LoadExternalSystems reads the external systems registered in each path: a directory of
+// OpenAPI 3 documents (*.yaml, *.yml, *.json; other documents are skipped, but documents
+// that do not parse are errors), a single OpenAPI 3 document, or a YAML catalog mapping
+// host names to system names:
+//
+//	payments.example.com: Payment Gateway
+//	partner-api:8443: Partner API
+//
+// A spec system is named after its info.title (else its file name) and registers the
+// hosts of its server URLs. Systems with the same name are merged.
+func LoadExternalSystems(paths []string) ([]model.ExternalSystem, error) {
+	var systems []model.ExternalSystem
+	add := func(sys model.ExternalSystem) {
+		for i := range systems {
+			if systems[i].Name == sys.Name {
+				for _, h := range sys.Hosts {
+					if !slices.Contains(systems[i].Hosts, h) {
+						systems[i].Hosts = append(systems[i].Hosts, h)
+					}
+				}
+				systems[i].Operations = append(systems[i].Operations, sys.Operations...)
+				if systems[i].BasePath == "" {
+					systems[i].BasePath = sys.BasePath
+				}
+				return
+			}
+		}
+		systems = append(systems, sys)
+	}
+
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, err
+		}
+		if !info.IsDir() {
+			root, err := readYAMLMapping(path)
+			if err != nil {
+				return nil, err
+			}
+			if mappingValue(root, "openapi") == nil {
+				catalog, err := externalCatalog(root, path)
+				if err != nil {
+					return nil, err
+				}
+				for _, sys := range catalog {
+					add(sys)
+				}
+				continue
+			}
+			sys, err := specSystem(root, path)
+			if err != nil {
+				return nil, err
+			}
+			add(sys)
+			continue
+		}
+
+		err = filepath.WalkDir(path, func(p string, d os.DirEntry, err error) error {
+			if err != nil || d.IsDir() {
+				return err
+			}
+			switch strings.ToLower(filepath.Ext(p)) {
+			case ".yaml", ".yml", ".json":
+			default:
+				return nil
+			}
+			// Other YAML and JSON documents are skipped; unreadable ones are errors
+			root, err := readYAMLMapping(p)
+			if errors.Is(err, errNotMapping) {
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+			if mappingValue(root, "openapi") == nil {
+				return nil
+			}
+			sys, err := specSystem(root, p)
+			if err != nil {
+				return err
+			}
+			add(sys)
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return systems, nil
+}
+
+// externalCatalog reads a host -> system name catalog.
+func externalCatalog(root *yaml.Node, path string) ([]model.ExternalSystem, error) {
+	var systems []model.ExternalSystem
+	for i := 0; i+1 < len(root.Content); i += 2 {
+		host, name := root.Content[i], root.Content[i+1]
+		if name.Kind != yaml.ScalarNode || strings.TrimSpace(name.Value) == "" {
+			return nil, fmt.Errorf("%s:%d: expected 'host: system name'", path, host.Line)
+		}
+		systems = append(systems, model.ExternalSystem{
+			Name:   strings.TrimSpace(name.Value),
+			Hosts:  []string{strings.ToLower(strings.TrimSpace(host.Value))},
+			Source: path,
+		})
+	}
+	return systems, nil
+}
+
+// specSystem registers the system an OpenAPI document describes.
+func specSystem(root *yaml.Node, path string) (model.ExternalSystem, error) {
+	spec, err := openAPISpec(root, path)
+	if err != nil {
+		return model.ExternalSystem{}, err
+	}
+	sys := model.ExternalSystem{
+		Name:       spec.Title,
+		BasePath:   spec.BasePath,
+		Operations: spec.Operations,
+		Source:     path,
+	}
+	if sys.Name == "" {
+		sys.Name = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	}
+	for _, server := range spec.Servers {
+		if host, _ := splitCallURL(server); host != "" && !strings.Contains(host, "{") && !slices.Contains(sys.Hosts, host) {
+			sys.Hosts = append(sys.Hosts, host)
+		}
+	}
+	return sys, nil
+}
+
+// LinkExternalSystems resolves the outbound REST calls left unresolved by the analysis
+// against registered external systems, and returns the system graph rebuilt with the
+// external dependencies. A call resolves to the external scope when its URL host belongs
+// to exactly one system (operations break ties between systems sharing a host), or, for a
+// call without host, when the operations of exactly one system accept its method and path.
+func LinkExternalSystems(services []model.Service, systems []model.ExternalSystem) model.SystemGraph {
+	for i := range services {
+		svc := &services[i]
+		for j := range svc.RESTCalls {
+			resolveExternalCall(&svc.RESTCalls[j], systems)
+		}
+		for r := range svc.RESTResources {
+			for j := range svc.RESTResources[r].OutboundCalls {
+				resolveExternalCall(&svc.RESTResources[r].OutboundCalls[j], systems)
+			}
+		}
+	}
+	return graph.BuildSystemGraph(services)
+}
+
+func resolveExternalCall(call *model.RESTCall, systems []model.ExternalSystem) {
+	if call.ResolutionScope != model.ResolutionUnresolved || call.TargetPath == "" {
+		return
+	}
+	host, path := splitCallURL(call.TargetPath)
+
+	if host != "" {
+		var byHost []model.ExternalSystem
+		for _, sys := range systems {
+			if hostMatches(sys.Hosts, host) {
+				byHost = append(byHost, sys)
+			}
+		}
+		var chosen *model.ExternalSystem
+		var op *model.SpecOperation
+		switch {
+		case len(byHost) == 1:
+			chosen = &byHost[0]
+			op = matchSpecOperation(*chosen, call.HTTPMethod, path)
+		case len(byHost) > 1:
+			for k := range byHost {
+				if o := matchSpecOperation(byHost[k], call.HTTPMethod, path); o != nil {
+					if chosen != nil {
+						return // Ambiguous
+					}
+					chosen, op = &byHost[k], o
+				}
+			}
+		}
+		if chosen == nil {
+			return
+		}
+		evidence := fmt.Sprintf("host %s (%s)", host, chosen.Source)
+		if op != nil {
+			evidence = fmt.Sprintf("host %s and operation %s %s (%s)", host, op.HTTPMethod, op.Path, op.Evidence)
+		} else if len(chosen.Operations) > 0 {
+			evidence += "; no matching operation in the spec"
+		}
+		setExternal(call, chosen.Name, evidence)
+		return
+	}
+
+	// Paths without host only match spec operations, with the same care as cross-service links
+	if call.Confidence != model.ConfidenceHigh && call.Confidence != model.ConfidenceMedium {
+		return
+	}
+	var chosen string
+	var op *model.SpecOperation
+	for _, sys := range systems {
+		if o := matchSpecOperation(sys, call.HTTPMethod, path); o != nil {
+			if chosen != "" && chosen != sys.Name {
+				return // Ambiguous
+			}
+			chosen, op = sys.Name, o
+		}
+	}
+	if op != nil {
+		setExternal(call, chosen, fmt.Sprintf("operation %s %s (%s)", op.HTTPMethod, op.Path, op.Evidence))
+	}
+}
+
+func setExternal(call *model.RESTCall, system, evidence string) {
+	call.TargetSystem = system
+	call.ResolutionScope = model.ResolutionExternal
+	call.ResolutionEvidence = evidence
+}
+
+// splitCallURL splits an absolute URL into its host (lowercased, with port) and path.
+// Relative paths have no host. Query strings and fragments are dropped.
+func splitCallURL(raw string) (string, string) {
+	host, path := "", raw
+	if i := strings.Index(raw, "://"); i >= 0 {
+		rest := raw[i+3:]
+		host, path = rest, "/"
+		if j := strings.Index(rest, "/"); j >= 0 {
+			host, path = rest[:j], rest[j:]
+		}
+		if u, err := url.Parse(raw[:i+3] + host); err != nil || u.Host == "" {
+			return "", ""
+		}
+		host = strings.ToLower(host)
+	}
+	if i := strings.IndexAny(path, "?#\""); i >= 0 {
+		path = path[:i]
+	}
+	return host, path
+}
+
+// hostMatches reports whether a call host is registered; a registered host without port
+// accepts any port.
+func hostMatches(hosts []string, host string) bool {
+	name := host
+	if i := strings.LastIndex(host, ":"); i >= 0 && !strings.Contains(host[i:], "]") {
+		name = host[:i]
+	}
+	for _, h := range hosts {
+		if h == host || h == name {
+			return true
+		}
+	}
+	return false
+}
+
+// matchSpecOperation returns the spec operation accepting a call, trying the operation
+// path with and without the server base path. Template parameters match one segment.
+func matchSpecOperation(sys model.ExternalSystem, httpMethod, path string) *model.SpecOperation {
+	if path == "" || httpMethod == "" {
+		return nil
+	}
+	for i := range sys.Operations {
+		op := &sys.Operations[i]
+		if op.HTTPMethod != httpMethod {
+			continue
+		}
+		if templateMatches(sys.BasePath+op.Path, path) || (sys.BasePath != "" && templateMatches(op.Path, path)) {
+			return op
+		}
+	}
+	return nil
+}
+
+func templateMatches(template, path string) bool {
+	t := strings.Split(strings.Trim(templateShape(template), "/"), "/")
+	p := strings.Split(strings.Trim(path, "/"), "/")
+	if len(t) != len(p) {
+		return false
+	}
+	for i := range t {
+		if t[i] == "{}" {
+			if p[i] == "" {
+				return false
+			}
+			continue
+		}
+		if t[i] != p[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,121 @@
+package app
+
+import (
+	"jz/model"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestHostMatches(t *testing.T) {
+	tests := []struct {
+		hosts []string
+		host  string
+		want  bool
+	}{
+		{[]string{"payments.example.com"}, "payments.example.com", true},
+		{[]string{"payments.example.com"}, "payments.example.com:8443", true},
+		{[]string{"payments.example.com:8443"}, "payments.example.com:8443", true},
+		{[]string{"payments.example.com:8443"}, "payments.example.com:9443", false},
+		{[]string{"payments.example.com:8443"}, "payments.example.com", false},
+		{[]string{"payments.example.com"}, "api.payments.example.com", false},
+		{[]string{"[::1]"}, "[::1]", true},
+		{[]string{"[::1]"}, "[::1]:8080", true},
+		{[]string{"[::1]:8080"}, "[::1]:9090", false},
+		{[]string{"[::1]"}, "[::2]", false},
+	}
+	for _, tt := range tests {
+		if got := hostMatches(tt.hosts, tt.host); got != tt.want {
+			t.Errorf("hostMatches(%v, %q) = %t, want %t", tt.hosts, tt.host, got, tt.want)
+		}
+	}
+}
+
+func TestTemplateMatches(t *testing.T) {
+	tests := []struct {
+		template, path string
+		want           bool
+	}{
+		{"/v1/charges", "/v1/charges", true},
+		{"/v1/charges/", "/v1/charges", true},
+		{"/v1/charges/{id}", "/v1/charges/42", true},
+		{"/v1/charges/{id}/refunds", "/v1/charges/42/refunds", true},
+		{"/v1/charges/{id}", "/v1/charges", false},
+		{"/v1/charges/{id}", "/v1/charges/42/refunds", false},
+		{"/v1/charges/{id}", "/v1/charges//", false},
+		{"/v1/charges", "/v1/refunds", false},
+	}
+	for _, tt := range tests {
+		if got := templateMatches(tt.template, tt.path); got != tt.want {
+			t.Errorf("templateMatches(%q, %q) = %t, want %t", tt.template, tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestResolveExternalCallSharedHost(t *testing.T) {
+	system := func(name string, ops ...string) model.ExternalSystem {
+		sys := model.ExternalSystem{Name: name, Hosts: []string{"api.example.com"}, Source: name + ".yaml"}
+		for _, op := range ops {
+			method, path, _ := strings.Cut(op, " ")
+			sys.Operations = append(sys.Operations, model.SpecOperation{HTTPMethod: method, Path: path})
+		}
+		return sys
+	}
+	systems := []model.ExternalSystem{
+		system("Payments", "POST /v1/charges", "GET /v1/status"),
+		system("Shipping", "POST /v1/shipments", "GET /v1/status"),
+	}
+	tests := []struct {
+		method, url string
+		want        string // Empty when the call stays unresolved
+	}{
+		{"POST", "https://api.example.com/v1/charges", "Payments"},
+		{"POST", "https://api.example.com:8443/v1/shipments", "Shipping"},
+		{"GET", "https://api.example.com/v1/status", ""},  // Both systems accept it
+		{"GET", "https://api.example.com/v1/unknown", ""}, // Neither does
+		{"POST", "https://other.example.com/v1/charges", ""},
+	}
+	for _, tt := range tests {
+		call := model.RESTCall{HTTPMethod: tt.method, TargetPath: tt.url, ResolutionScope: model.ResolutionUnresolved}
+		resolveExternalCall(&call, systems)
+		if call.TargetSystem != tt.want {
+			t.Errorf("%s %s resolved to %q, want %q", tt.method, tt.url, call.TargetSystem, tt.want)
+		}
+		if tt.want == "" && call.ResolutionScope != model.ResolutionUnresolved {
+			t.Errorf("%s %s: scope %q, want unresolved", tt.method, tt.url, call.ResolutionScope)
+		}
+	}
+
+	// A host registered by one system resolves even without a matching operation
+	call := model.RESTCall{HTTPMethod: "GET", TargetPath: "https://api.example.com/v2/charges", ResolutionScope: model.ResolutionUnresolved}
+	resolveExternalCall(&call, systems[:1])
+	if call.TargetSystem != "Payments" || !strings.Contains(call.ResolutionEvidence, "no matching operation") {
+		t.Errorf("single host owner: system %q, evidence %q", call.TargetSystem, call.ResolutionEvidence)
+	}
+}
+
+func TestLoadExternalSystemsDirectory(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("payments.yaml", "openapi: 3.0.3\ninfo:\n  title: Payments\nservers:\n  - url: https://payments.example.com\npaths: {}\n")
+	write("values.yaml", "replicas: 2\n")
+	write("list.json", "[1, 2]\n")
+
+	systems, err := LoadExternalSystems([]string{dir})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(systems) != 1 || systems[0].Name != "Payments" {
+		t.Errorf("systems %+v, want only Payments", systems)
+	}
+
+	write("broken.yaml", "openapi: 3.0.3\npaths: [\n")
+	if _, err := LoadExternalSystems([]string{dir}); err == nil || !strings.Contains(err.Error(), "broken.yaml") {
+		t.Errorf("error %v, want a parse error naming broken.yaml", err)
+	}
+}
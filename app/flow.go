@@ -4,37 +4,71 @@ import (
 	"bufio"
 	"fmt"
 	"jz/model"
-	"os"
+	"jz/scan"
+	"regexp"
 	"strings"
 )
 
-// ExtractFlow coordinates the extraction of execution flows for a specific resource.
-func ExtractFlow(services []model.Service, resourceName string, methodFilter string, pathFilter string, maxDepth int) ([]model.ExecutionFlow, error) {
-	var targetRes *model.RESTResource
-	var targetSvc *model.Service
+// throwNewRegex matches the exception type of a "throw new X(...)" expression.
+var throwNewRegex = regexp.MustCompile(`^new\s+([\w.]+)\s*\(`)
 
+// ExtractFlow coordinates the extraction of execution flows for a specific resource.
+// The resource is a simple class name or a service-qualified "service/Resource" name; a
+// simple name declared by several services is rejected as ambiguous.
+// A positive followDepth continues the flows into the handlers of resolved cross-service
+// calls, up to that many services away from the resource.
+func ExtractFlow(services []model.Service, resourceName string, methodFilter string, pathFilter string, maxDepth int, followDepth int) ([]model.ExecutionFlow, error) {
 	// 1. Locate the target resource
+	targetSvc, targetRes, err := findResource(services, resourceName)
+	if err != nil {
+		return nil, err
+	}
+
+	index := buildTypeIndex(targetSvc)
+	return extractResourceFlows(services, targetSvc, targetRes, index, methodFilter, pathFilter, maxDepth, followDepth), nil
+}
+
+// findResource locates a resource by simple or service-qualified name.
+func findResource(services []model.Service, resourceName string) (*model.Service, *model.RESTResource, error) {
+	serviceName, name := "", resourceName
+	if i := strings.LastIndex(resourceName, "/"); i >= 0 {
+		serviceName, name = resourceName[:i], resourceName[i+1:]
+	}
+
+	var targetSvc *model.Service
+	var targetRes *model.RESTResource
+	var owners []string
 	for i := range services {
+		if serviceName != "" && services[i].Name != serviceName {
+			continue
+		}
 		for j := range services[i].RESTResources {
-			if services[i].RESTResources[j].Name == resourceName {
-				targetRes = &services[i].RESTResources[j]
-				targetSvc = &services[i]
+			if services[i].RESTResources[j].Name == name {
+				if targetRes == nil {
+					targetRes = &services[i].RESTResources[j]
+					targetSvc = &services[i]
+				}
+				owners = append(owners, services[i].Name)
 				break
 			}
 		}
-		if targetRes != nil {
-			break
-		}
 	}
 
-	if targetRes == nil {
-		return nil, fmt.Errorf("resource '%s' not found", resourceName)
+	switch {
+	case targetRes == nil:
+		return nil, nil, fmt.Errorf("resource '%s' not found", resourceName)
+	case len(owners) > 1:
+		return nil, nil, fmt.Errorf("resource '%s' is ambiguous: declared by %s; use service/Resource", resourceName, strings.Join(owners, ", "))
 	}
+	return targetSvc, targetRes, nil
+}
 
+// extractResourceFlows builds the flows of the methods of a resource that pass the filters.
+func extractResourceFlows(services []model.Service, svc *model.Service, res *model.RESTResource, index *typeIndex, methodFilter, pathFilter string, maxDepth, followDepth int) []model.ExecutionFlow {
 	var flows []model.ExecutionFlow
 
 	// 2. Process each entry point (Method)
-	for _, m := range targetRes.Methods {
+	for _, m := range res.Methods {
 		// Apply filters
 		if methodFilter != "" && !strings.EqualFold(m.HTTPMethod, methodFilter) {
 			continue
@@ -43,214 +77,993 @@ func ExtractFlow(services []model.Service, resourceName string, methodFilter str
 			continue
 		}
 
-		flow := model.ExecutionFlow{
-			ResourceName: resourceName,
-			EntryPoint:   fmt.Sprintf("%s %s", m.HTTPMethod, m.FullPath),
+		if flow, ok := extractMethodFlow(svc, res, m, index, maxDepth, newCallFollower(services, followDepth)); ok {
+			flows = append(flows, flow)
 		}
+	}
 
-		parts := strings.Split(m.Handler, ".")
-		if len(parts) < 2 {
-			continue
-		}
-		handlerMethod := parts[1]
+	return flows
+}
 
-		visited := make(map[string]bool)
-		flow.Steps = scanMethodFlow(m.SourceFile, targetRes.Name, handlerMethod, targetSvc, 0, maxDepth, visited)
+// flowState is shared by the methods expanded into one flow.
+type flowState struct {
+	visited  map[string]bool
+	statuses []model.ResponseStatus // Statuses set by Response builders in the expanded methods
+	follower *callFollower          // Nil unless cross-service calls are followed
+}
 
-		// Re-index steps
-		for i := range flow.Steps {
-			flow.Steps[i].Index = i + 1
-		}
+// extractMethodFlow builds the flow of a single REST method. ok is false when the handler
+// name cannot be split into class and method. A non-nil follower continues the flow into
+// the targets of resolved cross-service calls.
+func extractMethodFlow(svc *model.Service, res *model.RESTResource, m model.RESTMethod, index *typeIndex, maxDepth int, follower *callFollower) (model.ExecutionFlow, bool) {
+	flow := model.ExecutionFlow{
+		ResourceName: res.Name,
+		EntryPoint:   fmt.Sprintf("%s %s", m.HTTPMethod, m.FullPath),
+	}
+
+	parts := strings.Split(m.Handler, ".")
+	if len(parts) < 2 {
+		return flow, false
+	}
+	handlerMethod := parts[1]
+
+	state := &flowState{visited: make(map[string]bool), follower: follower}
+	if follower != nil {
+		follower.path = append(follower.path, fmt.Sprintf("%s:%s", svc.Name, m.Handler))
+		defer func() { follower.path = follower.path[:len(follower.path)-1] }()
+	}
+	flow.Blocks = scanMethodFlow(m.SourceFile, res.Name, handlerMethod, svc, index, 0, maxDepth, state)
+	flow.ResponseStatuses = state.statuses
+
+	// Map uncaught throws to HTTP statuses (before flattening, so steps carry the result)
+	flow.ErrorStatuses = mapFlowExceptions(flow.Blocks, svc, index)
+
+	// Index steps in pre-order and record their nesting depth
+	numberFlow(flow.Blocks, 0, &flow.Steps)
+
+	return flow, true
+}
 
-		flows = append(flows, flow)
+// numberFlow assigns pre-order indexes and nesting depths to the nodes and appends
+// the flattened steps.
+func numberFlow(nodes []model.FlowNode, depth int, steps *[]model.FlowStep) {
+	for i := range nodes {
+		nodes[i].Step.Index = len(*steps) + 1
+		nodes[i].Step.Depth = depth
+		*steps = append(*steps, nodes[i].Step)
+		numberFlow(nodes[i].Children, depth+1, steps)
+		numberFlow(nodes[i].Else, depth+1, steps)
 	}
+}
+
+// methodFlowParser builds the block structure of a single method body.
+type methodFlowParser struct {
+	lines       []string
+	sourceFile  string
+	className   string
+	methodName  string
+	fullHandler string
+	service     *model.Service
+	index       *typeIndex
+	depth       int
+	maxDepth    int
+	state       *flowState
 
-	return flows, nil
+	entityManagers []string // EntityManager variables declared in the file
 }
 
-func scanMethodFlow(sourceFile, className, methodName string, service *model.Service, depth, maxDepth int, visited map[string]bool) []model.FlowStep {
+func scanMethodFlow(sourceFile, className, methodName string, service *model.Service, index *typeIndex, depth, maxDepth int, state *flowState) []model.FlowNode {
 	fullHandler := fmt.Sprintf("%s.%s", className, methodName)
-	visited[fullHandler] = true
+	state.visited[fullHandler] = true
 
-	f, err := os.Open(sourceFile)
+	lines, err := readSourceLines(service.Tree, sourceFile)
 	if err != nil {
 		return nil
 	}
-	defer f.Close()
-
-	var steps []model.FlowStep
-	scanner := bufio.NewScanner(f)
-
-	inMethod := false
-	braceCount := 0
-	lineNum := 0
 
 	// Entry Step
-	steps = append(steps, model.FlowStep{
+	nodes := []model.FlowNode{{Step: model.FlowStep{
 		Kind:        model.FlowStepEntry,
 		Description: fmt.Sprintf("Enter: %s", methodName),
 		FromMethod:  fullHandler,
 		Confidence:  model.ConfidenceHigh,
 		Evidence:    fmt.Sprintf("%s (start)", sourceFile),
-	})
+	}}}
 
-	for scanner.Scan() {
-		lineNum++
-		line := scanner.Text()
+	// Detect start of method
+	for i, line := range lines {
 		trimmed := strings.TrimSpace(line)
+		if !(strings.Contains(line, methodName) && strings.Contains(line, "(") && (strings.Contains(line, "public") || strings.Contains(line, "private") || strings.Contains(line, "protected") || strings.Contains(line, "void "))) {
+			continue
+		}
+		// Basic check to ensure it's a method declaration, not a call
+		if strings.HasSuffix(trimmed, ";") {
+			continue
+		}
+
+		// The body opens on the declaration line or shortly after it
+		open := i
+		for open < len(lines) && !strings.Contains(lines[open], "{") {
+			open++
+		}
+		if open == len(lines) {
+			break
+		}
 
-		// Detect start of method
-		if !inMethod && strings.Contains(line, methodName) && strings.Contains(line, "(") && (strings.Contains(line, "public") || strings.Contains(line, "private") || strings.Contains(line, "protected") || strings.Contains(line, "void ")) {
-			// Basic check to ensure it's a method declaration, not a call
-			if !strings.HasSuffix(trimmed, ";") {
-				inMethod = true
-				braceCount = strings.Count(line, "{") - strings.Count(line, "}")
+		p := &methodFlowParser{
+			lines:       lines,
+			sourceFile:  sourceFile,
+			className:   className,
+			methodName:  methodName,
+			fullHandler: fullHandler,
+			service:     service,
+			index:       index,
+			depth:       depth,
+			maxDepth:    maxDepth,
+			state:       state,
+
+			entityManagers: scan.EntityManagerNames(lines),
+		}
+		text := lines[open][strings.Index(lines[open], "{"):]
+		end, inner, _ := findBlockEnd(lines, open, text)
+		nodes = append(nodes, p.parseBlock(open, end, inner)...)
+		break
+	}
+
+	return nodes
+}
+
+// parseRange parses the statements on lines [start, end).
+func (p *methodFlowParser) parseRange(start, end int) []model.FlowNode {
+	var nodes []model.FlowNode
+	for i := start; i < end; i++ {
+		trimmed := strings.TrimSpace(p.lines[i])
+		var node model.FlowNode
+		var next int
+		switch {
+		case isIfStatement(trimmed):
+			node, next = p.parseIf(trimmed, i, end)
+		case isTryStatement(trimmed):
+			node, next = p.parseTry(trimmed, i, end)
+		case isLoopStatement(trimmed):
+			node, next = p.parseLoop(trimmed, i, end)
+		case isSwitchStatement(trimmed):
+			node, next = p.parseSwitch(trimmed, i)
+		case isBlockLambdaStatement(trimmed):
+			var lambdaNodes []model.FlowNode
+			lambdaNodes, next = p.parseBlockLambda(trimmed, i)
+			nodes = append(nodes, lambdaNodes...)
+			i = next - 1
+			continue
+		default:
+			nodes = append(nodes, p.parseStatement(trimmed, i)...)
+			continue
+		}
+		nodes = append(nodes, node)
+		i = next - 1
+	}
+	return nodes
+}
+
+// parseText parses a single statement that may itself be a (braceless) if, a try or a loop.
+func (p *methodFlowParser) parseText(text string, line int) []model.FlowNode {
+	text = strings.TrimSpace(text)
+	if isIfStatement(text) {
+		node, _ := p.parseIf(text, line, line+1)
+		return []model.FlowNode{node}
+	}
+	if isTryStatement(text) {
+		node, _ := p.parseTry(text, line, line+1)
+		return []model.FlowNode{node}
+	}
+	if isLoopStatement(text) {
+		node, _ := p.parseLoop(text, line, line+1)
+		return []model.FlowNode{node}
+	}
+	return p.parseStatement(text, line)
+}
+
+// parseBlock parses the content of a braced block opened on line and closed on closeLine;
+// inner is the content when the block opens and closes on the same line.
+func (p *methodFlowParser) parseBlock(line, closeLine int, inner string) []model.FlowNode {
+	if closeLine == line {
+		return p.parseText(inner, line)
+	}
+	return p.parseRange(line+1, closeLine)
+}
+
+// parseBody parses the statement or block following a control header whose remaining text
+// on line is rest. It returns the body, the next line to parse and the text following a
+// closing brace on the same line (e.g. "else {" in "} else {").
+func (p *methodFlowParser) parseBody(rest string, line int) ([]model.FlowNode, int, string) {
+	switch {
+	case strings.HasPrefix(rest, "{"):
+		closeLine, inner, tail := findBlockEnd(p.lines, line, rest)
+		return p.parseBlock(line, closeLine, inner), closeLine + 1, strings.TrimSpace(tail)
+	case rest == ";":
+		return nil, line + 1, ""
+	case rest != "":
+		return p.parseText(rest, line), line + 1, ""
+	case line+1 < len(p.lines):
+		return p.parseText(p.lines[line+1], line+1), line + 2, ""
+	default:
+		return nil, line + 1, ""
+	}
+}
+
+// parseLoop parses a for, while or do-while loop whose header starts on line and returns
+// the loop node holding the body and the next line to parse.
+func (p *methodFlowParser) parseLoop(text string, line, end int) (model.FlowNode, int) {
+	node := model.FlowNode{Step: model.FlowStep{
+		Kind:       model.FlowStepLoop,
+		FromMethod: p.fullHandler,
+		Confidence: model.ConfidenceMedium,
+		Evidence:   fmt.Sprintf("%s:%d", p.sourceFile, line+1),
+	}}
+
+	if isDoStatement(text) {
+		// do { ... } while (cond); with the while on the closing line or the next line
+		var next int
+		var after string
+		node.Children, next, after = p.parseBody(strings.TrimSpace(strings.TrimPrefix(text, "do")), line)
+		if after == "" && next < end && strings.HasPrefix(strings.TrimSpace(p.lines[next]), "while") {
+			after = strings.TrimSpace(p.lines[next])
+			next++
+		}
+		cond := "unknown condition"
+		if strings.HasPrefix(after, "while") {
+			cond, _ = splitCondition(after)
+		}
+		node.Step.Description = fmt.Sprintf("Loop: do ... while (%s)", cond)
+		return node, next
+	}
+
+	keyword := "while"
+	if strings.HasPrefix(text, "for") {
+		keyword = "for"
+	}
+	header, rest := splitCondition(text)
+	node.Step.Description = fmt.Sprintf("Loop: %s (%s)", keyword, header)
+	var next int
+	node.Children, next, _ = p.parseBody(rest, line)
+	return node, next
+}
+
+// parseSwitch parses a switch statement whose header starts on line. Each case label
+// (classic "case A:" or arrow "case A ->") becomes a case node holding the statements up
+// to the next label; consecutive labels without statements are merged into one case.
+func (p *methodFlowParser) parseSwitch(text string, line int) (model.FlowNode, int) {
+	expr, rest := splitCondition(text)
+	node := model.FlowNode{Step: model.FlowStep{
+		Kind:        model.FlowStepSwitch,
+		Description: fmt.Sprintf("Switch: %s", expr),
+		FromMethod:  p.fullHandler,
+		Confidence:  model.ConfidenceMedium,
+		Evidence:    fmt.Sprintf("%s:%d", p.sourceFile, line+1),
+	}}
+	if !strings.HasPrefix(rest, "{") {
+		return node, line + 1
+	}
+	closeLine, _, _ := findBlockEnd(p.lines, line, rest)
+	if closeLine == line {
+		// One-line switch bodies are not split into cases
+		return node, line + 1
+	}
+
+	// Case labels at the top level of the switch body
+	type caseLabel struct {
+		line   int
+		labels []string
+		rest   string
+	}
+	var cases []caseLabel
+	depth := 0
+	for i := line + 1; i < closeLine; i++ {
+		t := strings.TrimSpace(p.lines[i])
+		if depth == 0 {
+			if label, after, ok := splitCaseLabel(t); ok {
+				n := len(cases)
+				if n > 0 && cases[n-1].rest == "" && cases[n-1].line+len(cases[n-1].labels) == i {
+					// "case A:" directly followed by "case B:" shares its statements
+					cases[n-1].labels = append(cases[n-1].labels, label)
+					cases[n-1].rest = after
+				} else {
+					cases = append(cases, caseLabel{line: i, labels: []string{label}, rest: after})
+				}
+				depth += braceDelta(after)
 				continue
 			}
 		}
+		depth += braceDelta(t)
+	}
 
-		if inMethod {
-			braceCount += strings.Count(line, "{") - strings.Count(line, "}")
-			if braceCount <= 0 && strings.Contains(line, "}") {
-				inMethod = false
-				break
+	for k, c := range cases {
+		// Statements run from the last merged label up to the next case
+		first := c.line + len(c.labels) - 1
+		last := closeLine
+		if k+1 < len(cases) {
+			last = cases[k+1].line
+		}
+		desc := fmt.Sprintf("Case: %s", strings.Join(c.labels, ", "))
+		if len(c.labels) == 1 && c.labels[0] == "default" {
+			desc = "Default"
+		}
+		caseNode := model.FlowNode{Step: model.FlowStep{
+			Kind:        model.FlowStepCase,
+			Description: desc,
+			FromMethod:  p.fullHandler,
+			Confidence:  model.ConfidenceMedium,
+			Evidence:    fmt.Sprintf("%s:%d", p.sourceFile, c.line+1),
+		}}
+		if c.rest != "" {
+			caseNode.Children = p.parseText(c.rest, first)
+		}
+		caseNode.Children = append(caseNode.Children, p.parseRange(first+1, last)...)
+		node.Children = append(node.Children, caseNode)
+	}
+	return node, closeLine + 1
+}
+
+// parseTernaryReturn splits "return cond ? a : b;" into a condition whose branches return
+// a and b. ok is false when the returned expression is not a top-level conditional.
+func (p *methodFlowParser) parseTernaryReturn(trimmed string, line int) (model.FlowNode, bool) {
+	expr := strings.TrimSuffix(strings.TrimSpace(strings.TrimPrefix(trimmed, "return")), ";")
+	cond, a, b, ok := splitTernary(expr)
+	if !ok {
+		return model.FlowNode{}, false
+	}
+	return model.FlowNode{
+		Step: model.FlowStep{
+			Kind:        model.FlowStepCondition,
+			Description: fmt.Sprintf("Check: %s", cond),
+			FromMethod:  p.fullHandler,
+			Confidence:  model.ConfidenceMedium,
+			Evidence:    fmt.Sprintf("%s:%d", p.sourceFile, line+1),
+		},
+		Children: p.parseStatement("return "+a+";", line),
+		Else:     p.parseStatement("return "+b+";", line),
+	}, true
+}
+
+// parseIf parses an if statement whose header text starts on line, including any
+// else-if/else continuation, and returns the condition node and the next line to parse.
+func (p *methodFlowParser) parseIf(text string, line, end int) (model.FlowNode, int) {
+	cond, rest := splitCondition(text)
+	node := model.FlowNode{Step: model.FlowStep{
+		Kind:        model.FlowStepCondition,
+		Description: fmt.Sprintf("Check: %s", cond),
+		FromMethod:  p.fullHandler,
+		Confidence:  model.ConfidenceMedium,
+		Evidence:    fmt.Sprintf("%s:%d", p.sourceFile, line+1),
+	}}
+
+	// Then-branch
+	var next int
+	var after string
+	node.Children, next, after = p.parseBody(rest, line)
+
+	// Else-branch: on the closing line ("} else {") or on the next line
+	elseLine := next - 1
+	if after == "" && next < end && strings.HasPrefix(strings.TrimSpace(p.lines[next]), "else") {
+		after = strings.TrimSpace(p.lines[next])
+		elseLine = next
+		next++
+	}
+	if !strings.HasPrefix(after, "else") {
+		return node, next
+	}
+
+	elseText := strings.TrimSpace(strings.TrimPrefix(after, "else"))
+	switch {
+	case isIfStatement(elseText):
+		elseIf, n := p.parseIf(elseText, elseLine, end)
+		node.Else = []model.FlowNode{elseIf}
+		next = n
+	case strings.HasPrefix(elseText, "{"):
+		closeLine, inner, _ := findBlockEnd(p.lines, elseLine, elseText)
+		node.Else = p.parseBlock(elseLine, closeLine, inner)
+		next = closeLine + 1
+	case elseText != "":
+		node.Else = p.parseText(elseText, elseLine)
+	case elseLine+1 < len(p.lines):
+		node.Else = p.parseText(p.lines[elseLine+1], elseLine+1)
+		next = elseLine + 2
+	}
+	return node, next
+}
+
+// parseTry parses a try statement (with or without resources) whose header starts on line,
+// followed by its catch and finally handlers, and returns the try node and the next line to parse.
+func (p *methodFlowParser) parseTry(text string, line, end int) (model.FlowNode, int) {
+	rest := strings.TrimSpace(strings.TrimPrefix(text, "try"))
+	desc := "Try"
+	if strings.HasPrefix(rest, "(") {
+		var resources string
+		resources, rest = splitCondition(rest)
+		desc = fmt.Sprintf("Try with resources: %s", strings.TrimSpace(resources))
+	}
+	node := model.FlowNode{Step: model.FlowStep{
+		Kind:        model.FlowStepTry,
+		Description: desc,
+		FromMethod:  p.fullHandler,
+		Confidence:  model.ConfidenceHigh,
+		Evidence:    fmt.Sprintf("%s:%d", p.sourceFile, line+1),
+	}}
+	if !strings.HasPrefix(rest, "{") {
+		// Resource list or block opening continues on the next line(s); not followed
+		return node, line + 1
+	}
+
+	closeLine, inner, tail := findBlockEnd(p.lines, line, rest)
+	node.Children = p.parseBlock(line, closeLine, inner)
+	next, after := closeLine+1, strings.TrimSpace(tail)
+
+	// Handlers: on the closing line ("} catch (...) {") or on the next line
+	for {
+		handlerLine := next - 1
+		if after == "" && next < end {
+			if t := strings.TrimSpace(p.lines[next]); strings.HasPrefix(t, "catch") || strings.HasPrefix(t, "finally") {
+				after, handlerLine = t, next
+				next++
 			}
+		}
+
+		handler := model.FlowNode{Step: model.FlowStep{
+			FromMethod: p.fullHandler,
+			Confidence: model.ConfidenceHigh,
+			Evidence:   fmt.Sprintf("%s:%d", p.sourceFile, handlerLine+1),
+		}}
+		var body string
+		switch {
+		case strings.HasPrefix(after, "catch"):
+			var param string
+			param, body = splitCondition(after)
+			handler.Step.Kind = model.FlowStepCatch
+			handler.Step.Exceptions = catchTypes(param)
+			handler.Step.Description = fmt.Sprintf("Catch: %s", strings.Join(handler.Step.Exceptions, " | "))
+		case strings.HasPrefix(after, "finally"):
+			body = strings.TrimSpace(strings.TrimPrefix(after, "finally"))
+			handler.Step.Kind = model.FlowStepFinally
+			handler.Step.Description = "Finally"
+		default:
+			return node, next
+		}
+		if !strings.HasPrefix(body, "{") {
+			node.Else = append(node.Else, handler)
+			return node, next
+		}
+
+		closeLine, inner, tail = findBlockEnd(p.lines, handlerLine, body)
+		handler.Children = p.parseBlock(handlerLine, closeLine, inner)
+		node.Else = append(node.Else, handler)
+		next, after = closeLine+1, strings.TrimSpace(tail)
+	}
+}
+
+// parseThrow builds the throw step of a throw statement. The thrown type is known for
+// "throw new X(...)"; a status passed to the constructor is kept for the status mapping.
+func (p *methodFlowParser) parseThrow(trimmed string, line int) model.FlowNode {
+	expr := strings.TrimSpace(strings.TrimPrefix(trimmed, "throw"))
+	// Constructor arguments may continue on the following lines
+	for k := line + 1; !strings.Contains(expr, ";") && k < len(p.lines) && k <= line+5; k++ {
+		expr += " " + strings.TrimSpace(p.lines[k])
+	}
+	if idx := strings.LastIndex(expr, ";"); idx >= 0 {
+		expr = expr[:idx]
+	}
+
+	step := model.FlowStep{
+		Kind:       model.FlowStepThrow,
+		FromMethod: p.fullHandler,
+		Confidence: model.ConfidenceHigh,
+		Evidence:   fmt.Sprintf("%s:%d", p.sourceFile, line+1),
+	}
+	if m := throwNewRegex.FindStringSubmatch(expr); m != nil {
+		exception := simpleName(m[1])
+		args, _ := splitCondition(expr)
+		step.Description = fmt.Sprintf("Throw: %s", exception)
+		step.Exceptions = []string{exception}
+		step.HTTPStatus = scan.ArgumentStatus(args)
+	} else {
+		step.Description = fmt.Sprintf("Throw: %s", expr)
+		step.Confidence = model.ConfidenceMedium
+	}
+	return model.FlowNode{Step: step}
+}
+
+// parseStatement detects returns, outbound calls and internal or cross-class calls on a line.
+func (p *methodFlowParser) parseStatement(trimmed string, line int) []model.FlowNode {
+	var nodes []model.FlowNode
+	sourceFile, fullHandler, methodName := p.sourceFile, p.fullHandler, p.methodName
+	lineNum := line + 1
+
+	if isThrowStatement(trimmed) {
+		return []model.FlowNode{p.parseThrow(trimmed, line)}
+	}
+	if strings.Contains(trimmed, "->") || strings.Contains(trimmed, "::") {
+		if scopes := findLambdaScopes(trimmed); len(scopes) > 0 {
+			return p.parseLambdaStatement(trimmed, line, scopes)
+		}
+	}
+	if strings.HasPrefix(trimmed, "return ") {
+		if node, ok := p.parseTernaryReturn(trimmed, line); ok {
+			return []model.FlowNode{node}
+		}
+	}
 
-			// 1. Detect Conditionals
-			if strings.HasPrefix(trimmed, "if") || strings.HasPrefix(trimmed, "} else if") {
-				cond := extractCondition(trimmed)
-				steps = append(steps, model.FlowStep{
-					Kind:        model.FlowStepCondition,
-					Description: fmt.Sprintf("Check: %s", cond),
-					FromMethod:  fullHandler,
-					Confidence:  model.ConfidenceMedium,
-					Evidence:    fmt.Sprintf("%s:%d", sourceFile, lineNum),
-				})
-			} else if strings.HasPrefix(trimmed, "else") || strings.HasPrefix(trimmed, "} else") {
-				steps = append(steps, model.FlowStep{
-					Kind:        model.FlowStepCondition,
-					Description: "Otherwise",
-					FromMethod:  fullHandler,
-					Confidence:  model.ConfidenceMedium,
-					Evidence:    fmt.Sprintf("%s:%d", sourceFile, lineNum),
-				})
+	// Statuses set through Response builders (comparisons with Status constants are not)
+	if !strings.Contains(trimmed, "==") && !strings.Contains(trimmed, "!=") && !strings.Contains(trimmed, ".equals(") {
+		for _, status := range scan.ResponseStatuses(trimmed) {
+			p.state.statuses = append(p.state.statuses, model.ResponseStatus{
+				Status:   status,
+				Source:   model.StatusSourceResponse,
+				Detail:   strings.TrimSuffix(strings.TrimPrefix(trimmed, "return "), ";"),
+				Evidence: fmt.Sprintf("%s:%d", sourceFile, lineNum),
+			})
+		}
+	}
+
+	// Database accesses (EntityManager operations, JDBC statements)
+	accesses := scan.ParseDataAccess(trimmed, p.lines, p.entityManagers)
+	for _, da := range accesses {
+		nodes = append(nodes, p.persistenceNode(da, lineNum))
+	}
+
+	// 1. Detect Returns (a call through a field in the returned expression runs first)
+	if strings.HasPrefix(trimmed, "return") {
+		if target, ok := p.index.resolveFieldCall(trimmed, sourceFile, p.className, p.service); ok && len(accesses) == 0 {
+			nodes = append(nodes, fieldCallSteps(target, fullHandler, fmt.Sprintf("%s:%d", sourceFile, lineNum), p.service, p.index, p.depth, p.maxDepth, p.state)...)
+		}
+		nodes = append(nodes, model.FlowNode{Step: model.FlowStep{
+			Kind:        model.FlowStepReturn,
+			Description: fmt.Sprintf("Return: %s", strings.TrimSuffix(strings.TrimPrefix(trimmed, "return "), ";")),
+			FromMethod:  fullHandler,
+			Confidence:  model.ConfidenceHigh,
+			Evidence:    fmt.Sprintf("%s:%d", sourceFile, lineNum),
+		}})
+	}
+
+	if len(accesses) > 0 {
+		return nodes
+	}
+
+	// 2. Detect Outbound REST Calls (reuse patterns from scanOutboundCalls). The method-name
+	// heuristic only applies to receivers without an implementation in the sources:
+	// cache.put(...) on an injected FastCache is a call into FastCache, not a REST call, but
+	// payments.post(...) on a REST client interface without implementation stays outbound.
+	fieldTarget, isFieldCall := p.index.resolveFieldCall(trimmed, sourceFile, p.className, p.service)
+	localCall := (isFieldCall && fieldTarget.Class != "") || p.isLocalMethodCall(trimmed)
+	outboundPatterns := []string{"get(", "post(", "put(", "delete(", "RESTClient", "WebTarget", "HttpURLConnection"}
+	isOutbound := false
+	for _, pattern := range outboundPatterns {
+		if localCall && !isClientPattern(pattern) {
+			continue
+		}
+		if strings.Contains(trimmed, pattern) {
+			isOutbound = true
+			call := model.RESTCall{
+				FromHandler: methodName,
+				SourceFile:  sourceFile,
+				Confidence:  model.ConfidenceLow,
+			}
+			// Try to find HTTP method
+			upper := strings.ToUpper(trimmed)
+			for _, m := range []string{"GET", "POST", "PUT", "DELETE"} {
+				if strings.Contains(upper, m) {
+					call.HTTPMethod = m
+					break
+				}
+			}
+			// Try to find target path
+			if strings.Contains(trimmed, "\"") {
+				start := strings.Index(trimmed, "\"")
+				end := strings.LastIndex(trimmed, "\"")
+				if end > start {
+					path := trimmed[start+1 : end]
+					if strings.HasPrefix(path, "/") || strings.HasPrefix(path, "http") {
+						call.TargetPath = path
+						call.Confidence = model.ConfidenceHigh
+					}
+				}
 			}
 
-			// 2. Detect Returns
-			if strings.HasPrefix(trimmed, "return") {
-				steps = append(steps, model.FlowStep{
-					Kind:        model.FlowStepReturn,
-					Description: fmt.Sprintf("Return: %s", strings.TrimSuffix(strings.TrimPrefix(trimmed, "return "), ";")),
-					FromMethod:  fullHandler,
-					Confidence:  model.ConfidenceHigh,
-					Evidence:    fmt.Sprintf("%s:%d", sourceFile, lineNum),
-				})
+			desc := "Outbound REST call"
+			if call.HTTPMethod != "" && call.TargetPath != "" {
+				desc = fmt.Sprintf("Call: %s %s", call.HTTPMethod, call.TargetPath)
 			}
 
-			// 3. Detect Outbound REST Calls (reuse patterns from scanOutboundCalls)
-			outboundPatterns := []string{"get(", "post(", "put(", "delete(", "RESTClient", "WebTarget", "HttpURLConnection"}
-			isOutbound := false
-			for _, p := range outboundPatterns {
-				if strings.Contains(trimmed, p) {
-					isOutbound = true
-					call := model.RESTCall{
-						FromHandler: methodName,
-						SourceFile:  sourceFile,
-						Confidence:  model.ConfidenceLow,
-					}
-					// Try to find HTTP method
-					upper := strings.ToUpper(trimmed)
-					for _, m := range []string{"GET", "POST", "PUT", "DELETE"} {
-						if strings.Contains(upper, m) {
-							call.HTTPMethod = m
-							break
-						}
+			// Check if this call was resolved in Phase F5
+			resScope := model.ResolutionUnresolved
+			resolvedTo := ""
+			var followed []model.FlowNode
+			followEvidence := ""
+			for _, svcCall := range p.service.RESTCalls {
+				if svcCall.FromHandler == methodName && svcCall.HTTPMethod == call.HTTPMethod && svcCall.TargetPath == call.TargetPath {
+					resScope = svcCall.ResolutionScope
+					if svcCall.TargetResource != "" {
+						resolvedTo = svcCall.TargetService + " -> " + svcCall.TargetResource
+					} else if svcCall.TargetSystem != "" {
+						resolvedTo = svcCall.TargetSystem
 					}
-					// Try to find target path
-					if strings.Contains(trimmed, "\"") {
-						start := strings.Index(trimmed, "\"")
-						end := strings.LastIndex(trimmed, "\"")
-						if end > start {
-							path := trimmed[start+1 : end]
-							if strings.HasPrefix(path, "/") || strings.HasPrefix(path, "http") {
-								call.TargetPath = path
-								call.Confidence = model.ConfidenceHigh
-							}
-						}
+					// Continue into the handler of the other service
+					if p.state.follower != nil && resScope == model.ResolutionCrossService && svcCall.TargetResource != "" {
+						followed, followEvidence = p.state.follower.follow(svcCall, p.maxDepth)
 					}
+					break
+				}
+			}
 
-					desc := "Outbound REST call"
-					if call.HTTPMethod != "" && call.TargetPath != "" {
-						desc = fmt.Sprintf("Call: %s %s", call.HTTPMethod, call.TargetPath)
-					}
+			nodes = append(nodes, model.FlowNode{
+				Step: model.FlowStep{
+					Kind:               model.FlowStepOutbound,
+					Description:        desc,
+					FromMethod:         fullHandler,
+					ToMethod:           resolvedTo,
+					Confidence:         call.Confidence,
+					Evidence:           fmt.Sprintf("%s:%d", sourceFile, lineNum),
+					ResolutionScope:    resScope,
+					ResolutionEvidence: followEvidence,
+				},
+				Children: followed,
+			})
+			break
+		}
+	}
 
-					// Check if this call was resolved in Phase F5
-					resScope := model.ResolutionUnresolved
-					resolvedTo := ""
-					for _, svcCall := range service.RESTCalls {
-						if svcCall.FromHandler == methodName && svcCall.HTTPMethod == call.HTTPMethod && svcCall.TargetPath == call.TargetPath {
-							resScope = svcCall.ResolutionScope
-							if svcCall.TargetResource != "" {
-								resolvedTo = svcCall.TargetService + " -> " + svcCall.TargetResource
-							}
-							break
-						}
-					}
+	// 3. Detect Internal Method Calls (same class expansion)
+	if !isOutbound && strings.Contains(trimmed, "(") && !strings.Contains(trimmed, "new ") && !strings.Contains(trimmed, "return ") && !strings.HasPrefix(trimmed, "if") && !strings.HasPrefix(trimmed, "for") && !strings.HasPrefix(trimmed, "while") {
+		innerMethod := extractMethodName(trimmed)
+		if isFieldCall {
+			// 4. Cross-class call through an injected or declared field
+			nodes = append(nodes, fieldCallSteps(fieldTarget, fullHandler, fmt.Sprintf("%s:%d", sourceFile, lineNum), p.service, p.index, p.depth, p.maxDepth, p.state)...)
+		} else if innerMethod != "" && innerMethod != methodName {
+			nodes = append(nodes, p.internalCall(innerMethod, lineNum)...)
+		}
+	}
 
-					steps = append(steps, model.FlowStep{
-						Kind:            model.FlowStepOutbound,
-						Description:     desc,
-						FromMethod:      fullHandler,
-						ToMethod:        resolvedTo,
-						Confidence:      call.Confidence,
-						Evidence:        fmt.Sprintf("%s:%d", sourceFile, lineNum),
-						ResolutionScope: resScope,
-					})
-					break
-				}
+	return nodes
+}
+
+// isLocalMethodCall reports whether the statement's first call is an unqualified call to a
+// method declared in the current file.
+func (p *methodFlowParser) isLocalMethodCall(line string) bool {
+	name := extractMethodName(line)
+	if name == "" {
+		return false
+	}
+	at := strings.Index(line, name+"(")
+	if at > 0 && line[at-1] == '.' {
+		return false
+	}
+	return methodExistsInFile(p.service.Tree, p.sourceFile, name)
+}
+
+// isClientPattern reports whether an outbound pattern names an HTTP client type rather
+// than a method name.
+func isClientPattern(pattern string) bool {
+	return !strings.HasSuffix(pattern, "(")
+}
+
+// internalCall expands a call to a method of the same file, or records why it was not
+// expanded. It returns no steps when the method is not declared in the file.
+func (p *methodFlowParser) internalCall(innerMethod string, lineNum int) []model.FlowNode {
+	// Check if method exists in the same file (simplistic check)
+	if !methodExistsInFile(p.service.Tree, p.sourceFile, innerMethod) {
+		return nil
+	}
+	targetHandler := fmt.Sprintf("%s.%s", p.className, innerMethod)
+	if p.depth < p.maxDepth && !p.state.visited[targetHandler] {
+		return []model.FlowNode{{
+			Step: model.FlowStep{
+				Kind:        model.FlowStepCall,
+				Description: fmt.Sprintf("Call internal: %s", innerMethod),
+				FromMethod:  p.fullHandler,
+				ToMethod:    targetHandler,
+				Confidence:  model.ConfidenceMedium,
+				Evidence:    fmt.Sprintf("%s:%d", p.sourceFile, lineNum),
+			},
+			// Recurse
+			Children: scanMethodFlow(p.sourceFile, p.className, innerMethod, p.service, p.index, p.depth+1, p.maxDepth, p.state),
+		}}
+	}
+
+	reason := "depth limit"
+	if p.state.visited[targetHandler] {
+		reason = "already visited / potential cycle"
+	}
+	return []model.FlowNode{{Step: model.FlowStep{
+		Kind:        model.FlowStepUnexpanded,
+		Description: fmt.Sprintf("Call internal: %s (unexpanded - %s)", innerMethod, reason),
+		FromMethod:  p.fullHandler,
+		ToMethod:    targetHandler,
+		Confidence:  model.ConfidenceHigh,
+		Evidence:    fmt.Sprintf("%s:%d", p.sourceFile, lineNum),
+	}}}
+}
+
+// fieldCallSteps expands a call through a field into the implementation method, or records
+// why it was not expanded. The resolution evidence is kept on the step.
+func fieldCallSteps(target receiverTarget, fromMethod, evidence string, service *model.Service, index *typeIndex, depth, maxDepth int, state *flowState) []model.FlowNode {
+	call := fmt.Sprintf("%s.%s", target.Field, target.Method)
+	unexpanded := func(toMethod, reason string) []model.FlowNode {
+		return []model.FlowNode{{Step: model.FlowStep{
+			Kind:               model.FlowStepUnexpanded,
+			Description:        fmt.Sprintf("Call: %s (unexpanded - %s)", call, reason),
+			FromMethod:         fromMethod,
+			ToMethod:           toMethod,
+			Confidence:         model.ConfidenceHigh,
+			Evidence:           evidence,
+			ResolutionEvidence: target.Evidence,
+		}}}
+	}
+
+	if target.Class == "" {
+		return unexpanded("", "implementation not resolved")
+	}
+	targetHandler := fmt.Sprintf("%s.%s", target.Class, target.Method)
+	if !methodExistsInFile(service.Tree, target.SourceFile, target.Method) {
+		return unexpanded(targetHandler, "method not found in "+target.Class)
+	}
+	if depth >= maxDepth {
+		return unexpanded(targetHandler, "depth limit")
+	}
+	if state.visited[targetHandler] {
+		return unexpanded(targetHandler, "already visited / potential cycle")
+	}
+
+	return []model.FlowNode{{
+		Step: model.FlowStep{
+			Kind:               model.FlowStepCall,
+			Description:        fmt.Sprintf("Call: %s -> %s", call, targetHandler),
+			FromMethod:         fromMethod,
+			ToMethod:           targetHandler,
+			Confidence:         target.Confidence,
+			Evidence:           evidence,
+			ResolutionEvidence: target.Evidence,
+		},
+		Children: scanMethodFlow(target.SourceFile, target.Class, target.Method, service, index, depth+1, maxDepth, state),
+	}}
+}
+
+// isIfStatement reports whether the statement starts with an if keyword.
+func isIfStatement(text string) bool {
+	return strings.HasPrefix(text, "if (") || strings.HasPrefix(text, "if(")
+}
+
+// isTryStatement reports whether the statement starts a try block.
+func isTryStatement(text string) bool {
+	return text == "try" || strings.HasPrefix(text, "try {") || strings.HasPrefix(text, "try{") || strings.HasPrefix(text, "try (") || strings.HasPrefix(text, "try(")
+}
+
+// isLoopStatement reports whether the statement starts a for, while or do loop.
+func isLoopStatement(text string) bool {
+	return strings.HasPrefix(text, "for (") || strings.HasPrefix(text, "for(") ||
+		strings.HasPrefix(text, "while (") || strings.HasPrefix(text, "while(") || isDoStatement(text)
+}
+
+// isDoStatement reports whether the statement starts a do-while loop.
+func isDoStatement(text string) bool {
+	return text == "do" || strings.HasPrefix(text, "do {") || strings.HasPrefix(text, "do{")
+}
+
+// isSwitchStatement reports whether the statement starts a switch.
+func isSwitchStatement(text string) bool {
+	return strings.HasPrefix(text, "switch (") || strings.HasPrefix(text, "switch(")
+}
+
+// splitCaseLabel splits a case label line ("case A, B:", "case A ->", "default:") into the
+// label and the statement text following it.
+func splitCaseLabel(text string) (string, string, bool) {
+	var body string
+	switch {
+	case strings.HasPrefix(text, "case "):
+		body = text[len("case "):]
+	case strings.HasPrefix(text, "default"):
+		body = text[len("default"):]
+		if t := strings.TrimSpace(body); !strings.HasPrefix(t, ":") && !strings.HasPrefix(t, "->") {
+			return "", "", false
+		}
+	default:
+		return "", "", false
+	}
+
+	var quote byte
+	for i := 0; i < len(body); i++ {
+		c := body[i]
+		if quote != 0 {
+			if c == '\\' {
+				i++
+			} else if c == quote {
+				quote = 0
 			}
+			continue
+		}
+		switch {
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '-' && i+1 < len(body) && body[i+1] == '>':
+			return labelText(text, body[:i]), strings.TrimSpace(body[i+2:]), true
+		case c == ':' && (i+1 == len(body) || body[i+1] != ':') && (i == 0 || body[i-1] != ':'):
+			return labelText(text, body[:i]), strings.TrimSpace(body[i+1:]), true
+		}
+	}
+	return "", "", false
+}
+
+// labelText returns the display label of a case ("default" for the default label).
+func labelText(text, label string) string {
+	if strings.HasPrefix(text, "default") {
+		return "default"
+	}
+	return strings.TrimSpace(label)
+}
 
-			// 4. Detect Internal Method Calls (same class expansion)
-			if !isOutbound && strings.Contains(trimmed, "(") && !strings.Contains(trimmed, "new ") && !strings.Contains(trimmed, "return ") && !strings.HasPrefix(trimmed, "if") && !strings.HasPrefix(trimmed, "for") && !strings.HasPrefix(trimmed, "while") {
-				innerMethod := extractMethodName(trimmed)
-				if innerMethod != "" && innerMethod != methodName {
-					// Check if method exists in the same file (simplistic check)
-					if methodExistsInFile(sourceFile, innerMethod) {
-						targetHandler := fmt.Sprintf("%s.%s", className, innerMethod)
-						if depth < maxDepth && !visited[targetHandler] {
-							steps = append(steps, model.FlowStep{
-								Kind:        model.FlowStepCall,
-								Description: fmt.Sprintf("Call internal: %s", innerMethod),
-								FromMethod:  fullHandler,
-								ToMethod:    targetHandler,
-								Confidence:  model.ConfidenceMedium,
-								Evidence:    fmt.Sprintf("%s:%d", sourceFile, lineNum),
-							})
-
-							// Recurse
-							innerSteps := scanMethodFlow(sourceFile, className, innerMethod, service, depth+1, maxDepth, visited)
-							steps = append(steps, innerSteps...)
-						} else {
-							reason := "depth limit"
-							if visited[targetHandler] {
-								reason = "already visited / potential cycle"
-							}
-							steps = append(steps, model.FlowStep{
-								Kind:        model.FlowStepUnexpanded,
-								Description: fmt.Sprintf("Call internal: %s (unexpanded - %s)", innerMethod, reason),
-								FromMethod:  fullHandler,
-								ToMethod:    targetHandler,
-								Confidence:  model.ConfidenceHigh,
-								Evidence:    fmt.Sprintf("%s:%d", sourceFile, lineNum),
-							})
-						}
+// splitTernary splits a top-level conditional expression "cond ? a : b".
+func splitTernary(expr string) (string, string, string, bool) {
+	depth := 0
+	question, colon := -1, -1
+	nested := 0
+	var quote byte
+	for i := 0; i < len(expr) && colon < 0; i++ {
+		c := expr[i]
+		if quote != 0 {
+			if c == '\\' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+			continue
+		}
+		switch c {
+		case '"', '\'':
+			quote = c
+		case '(', '[', '{':
+			depth++
+		case ')', ']', '}':
+			depth--
+		case '?':
+			if depth != 0 {
+				continue
+			}
+			if question < 0 {
+				question = i
+			} else {
+				nested++
+			}
+		case ':':
+			if depth != 0 || question < 0 || (i+1 < len(expr) && expr[i+1] == ':') || expr[i-1] == ':' {
+				continue
+			}
+			if nested > 0 {
+				nested--
+				continue
+			}
+			colon = i
+		}
+	}
+	if question < 0 || colon < 0 {
+		return "", "", "", false
+	}
+	cond := strings.TrimSpace(expr[:question])
+	if inner, rest := splitCondition(cond); strings.HasPrefix(cond, "(") && rest == "" {
+		cond = inner
+	}
+	return strings.TrimSpace(cond), strings.TrimSpace(expr[question+1 : colon]), strings.TrimSpace(expr[colon+1:]), true
+}
+
+// braceDelta returns the change in brace depth over a line, ignoring string and char literals.
+func braceDelta(line string) int {
+	delta := 0
+	var quote byte
+	for i := 0; i < len(line); i++ {
+		c := line[i]
+		if quote != 0 {
+			if c == '\\' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+			continue
+		}
+		switch c {
+		case '"', '\'':
+			quote = c
+		case '{':
+			delta++
+		case '}':
+			delta--
+		}
+	}
+	return delta
+}
+
+// isThrowStatement reports whether the statement is a throw.
+func isThrowStatement(text string) bool {
+	return strings.HasPrefix(text, "throw ")
+}
+
+// catchTypes returns the exception types of a catch parameter ("final IOException | SQLException e").
+func catchTypes(param string) []string {
+	fields := strings.Fields(strings.ReplaceAll(param, "|", " | "))
+	if len(fields) > 0 {
+		fields = fields[:len(fields)-1] // Parameter name
+	}
+	var types []string
+	for _, f := range fields {
+		if f == "|" || f == "final" || strings.HasPrefix(f, "@") {
+			continue
+		}
+		types = append(types, simpleName(f))
+	}
+	return types
+}
+
+// splitCondition returns the parenthesized condition of an if header and the text after it.
+func splitCondition(text string) (string, string) {
+	start := strings.Index(text, "(")
+	if start == -1 {
+		return "unknown condition", ""
+	}
+	depth := 0
+	for i := start; i < len(text); i++ {
+		switch text[i] {
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth == 0 {
+				return text[start+1 : i], strings.TrimSpace(text[i+1:])
+			}
+		}
+	}
+	// Condition continues on the next line(s); keep what is visible
+	return extractCondition(text), ""
+}
+
+// findBlockEnd locates the brace closing the block opened by the first '{' in text,
+// which is the tail of lines[line]. It returns the closing line, the block content when
+// the block closes on the same line, and the text following the closing brace.
+// Braces inside string and char literals are ignored.
+func findBlockEnd(lines []string, line int, text string) (int, string, string) {
+	depth := 0
+	openIdx := -1
+	for l := line; l < len(lines); l++ {
+		s := text
+		if l != line {
+			s = lines[l]
+		}
+		var quote byte
+		for i := 0; i < len(s); i++ {
+			c := s[i]
+			if quote != 0 {
+				if c == '\\' {
+					i++
+				} else if c == quote {
+					quote = 0
+				}
+				continue
+			}
+			switch c {
+			case '"', '\'':
+				quote = c
+			case '{':
+				if depth == 0 && l == line {
+					openIdx = i
+				}
+				depth++
+			case '}':
+				depth--
+				if depth == 0 {
+					inner := ""
+					if l == line && openIdx >= 0 {
+						inner = s[openIdx+1 : i]
 					}
+					return l, inner, s[i+1:]
 				}
 			}
 		}
 	}
-
-	return steps
+	return len(lines), "", ""
 }
 
 func extractCondition(line string) string {
@@ -288,8 +1101,8 @@ func extractMethodName(line string) string {
 	return method
 }
 
-func methodExistsInFile(filePath, methodName string) bool {
-	f, err := os.Open(filePath)
+func methodExistsInFile(tree *model.SourceTree, filePath, methodName string) bool {
+	f, err := tree.Open(filePath)
 	if err != nil {
 		return false
 	}
@@ -305,3 +1118,18 @@ func methodExistsInFile(filePath, methodName string) bool {
 	}
 	return false
 }
+
+func readSourceLines(tree *model.SourceTree, path string) ([]string, error) {
+	f, err := tree.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var lines []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+	}
+	return lines, scanner.Err()
+}
@@ -0,0 +1,80 @@
+package app
+
+import (
+	"jz/model"
+	"runtime"
+	"sync"
+)
+
+// ExtractAllFlows extracts the flows of every resource of every service, in parallel. The
+// result is ordered by service and resource as analyzed; resources whose methods are all
+// filtered out are left out.
+func ExtractAllFlows(services []model.Service, methodFilter string, pathFilter string, maxDepth int, followDepth int) []model.ResourceFlows {
+	type job struct {
+		svc *model.Service
+		res *model.RESTResource
+	}
+	var jobs []job
+	var withResources []*model.Service
+	for i := range services {
+		if len(services[i].RESTResources) == 0 {
+			continue
+		}
+		withResources = append(withResources, &services[i])
+		for j := range services[i].RESTResources {
+			jobs = append(jobs, job{&services[i], &services[i].RESTResources[j]})
+		}
+	}
+
+	// Type indexes are read-only once built and shared by the resources of a service
+	indexes := make([]*typeIndex, len(withResources))
+	parallel(len(withResources), func(i int) {
+		indexes[i] = buildTypeIndex(withResources[i])
+	})
+	indexOf := make(map[*model.Service]*typeIndex)
+	for i, svc := range withResources {
+		indexOf[svc] = indexes[i]
+	}
+
+	results := make([]model.ResourceFlows, len(jobs))
+	parallel(len(jobs), func(i int) {
+		j := jobs[i]
+		results[i] = model.ResourceFlows{
+			Service:  j.svc.Name,
+			Resource: j.res.Name,
+			Flows:    extractResourceFlows(services, j.svc, j.res, indexOf[j.svc], methodFilter, pathFilter, maxDepth, followDepth),
+		}
+	})
+
+	var catalog []model.ResourceFlows
+	for _, r := range results {
+		if len(r.Flows) > 0 {
+			catalog = append(catalog, r)
+		}
+	}
+	return catalog
+}
+
+// parallel calls fn for 0..n-1 on up to GOMAXPROCS goroutines and waits for all calls.
+func parallel(n int, fn func(i int)) {
+	workers := runtime.GOMAXPROCS(0)
+	if workers > n {
+		workers = n
+	}
+	next := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range next {
+				fn(i)
+			}
+		}()
+	}
+	for i := 0; i < n; i++ {
+		next <- i
+	}
+	close(next)
+	wg.Wait()
+}
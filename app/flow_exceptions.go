@@ -0,0 +1,261 @@
+package app
+
+import (
+	"fmt"
+	"jz/model"
+	"slices"
+	"strings"
+)
+
+// jaxrsExceptionStatuses are the statuses of the JAX-RS WebApplicationException subclasses.
+var jaxrsExceptionStatuses = map[string]int{
+	"BadRequestException":          400,
+	"NotAuthorizedException":       401,
+	"ForbiddenException":           403,
+	"NotFoundException":            404,
+	"NotAllowedException":          405,
+	"NotAcceptableException":       406,
+	"NotSupportedException":        415,
+	"InternalServerErrorException": 500,
+	"ServiceUnavailableException":  503,
+}
+
+// exceptionParents are the supertypes of the JAX-RS exceptions and of common JDK exceptions.
+var exceptionParents = map[string]string{
+	"WebApplicationException":       "RuntimeException",
+	"ClientErrorException":          "WebApplicationException",
+	"ServerErrorException":          "WebApplicationException",
+	"RedirectionException":          "WebApplicationException",
+	"BadRequestException":           "ClientErrorException",
+	"NotAuthorizedException":        "ClientErrorException",
+	"ForbiddenException":            "ClientErrorException",
+	"NotFoundException":             "ClientErrorException",
+	"NotAllowedException":           "ClientErrorException",
+	"NotAcceptableException":        "ClientErrorException",
+	"NotSupportedException":         "ClientErrorException",
+	"InternalServerErrorException":  "ServerErrorException",
+	"ServiceUnavailableException":   "ServerErrorException",
+	"IllegalArgumentException":      "RuntimeException",
+	"IllegalStateException":         "RuntimeException",
+	"NullPointerException":          "RuntimeException",
+	"UnsupportedOperationException": "RuntimeException",
+	"NumberFormatException":         "IllegalArgumentException",
+	"RuntimeException":              "Exception",
+	"IOException":                   "Exception",
+	"SQLException":                  "Exception",
+	"Exception":                     "Throwable",
+}
+
+// exceptionMapping resolves the throws of a flow to the HTTP statuses they produce.
+type exceptionMapping struct {
+	service  *model.Service
+	index    *typeIndex
+	statuses []model.FlowErrorStatus
+	caughtBy map[string][]string // Catch evidence -> thrown types it handles
+	async    int                 // Depth of enclosing async scopes
+}
+
+// mapFlowExceptions annotates the throw steps of a flow with the catch that handles them or
+// the HTTP status they produce, and returns the statuses of the throws that leave the handler.
+// A throw leaves the handler unless an enclosing catch (in the handler or in a caller of an
+// expanded callee) declares the thrown type or one of its supertypes. Throws in async tasks
+// do not reach the handler, and throws in followed cross-service flows answer the call.
+func mapFlowExceptions(nodes []model.FlowNode, service *model.Service, index *typeIndex) []model.FlowErrorStatus {
+	m := &exceptionMapping{service: service, index: index, caughtBy: make(map[string][]string)}
+	m.walk(nodes, nil, nil)
+	return m.statuses
+}
+
+// walk visits the nodes with the catch handlers of the enclosing try blocks (innermost last)
+// and the types caught by the enclosing catch, used for rethrows. A catch rethrows the types
+// thrown in its try block that it handles, followed by its declared types.
+func (m *exceptionMapping) walk(nodes []model.FlowNode, handlers [][]model.FlowStep, caught []string) {
+	for i := range nodes {
+		n := &nodes[i]
+		switch n.Step.Kind {
+		case model.FlowStepTry:
+			var catches []model.FlowStep
+			for _, h := range n.Else {
+				if h.Step.Kind == model.FlowStepCatch {
+					catches = append(catches, h.Step)
+				}
+			}
+			m.walk(n.Children, append(handlers[:len(handlers):len(handlers)], catches), caught)
+			for j := range n.Else {
+				inner := caught
+				if h := n.Else[j].Step; h.Kind == model.FlowStepCatch {
+					inner = nil
+					for _, t := range append(m.caughtBy[h.Evidence], h.Exceptions...) {
+						if !slices.Contains(inner, t) {
+							inner = append(inner, t)
+						}
+					}
+				}
+				m.walk(n.Else[j].Children, handlers, inner)
+			}
+		case model.FlowStepThrow:
+			m.mapThrow(&n.Step, handlers, caught)
+		case model.FlowStepCall:
+			// A rethrow in the callee refers to the callee's own catch
+			m.walk(n.Children, handlers, nil)
+		case model.FlowStepOutbound:
+			// A followed flow runs in another service and was mapped there
+		case model.FlowStepLambda:
+			if n.Step.ExecutionScope != model.FlowScopeAsync {
+				m.walk(n.Children, handlers, caught)
+				continue
+			}
+			// An async task completes outside the handler's try blocks
+			m.async++
+			m.walk(n.Children, nil, nil)
+			m.async--
+		default:
+			m.walk(n.Children, handlers, caught)
+			m.walk(n.Else, handlers, caught)
+		}
+	}
+}
+
+// mapThrow resolves a single throw step.
+func (m *exceptionMapping) mapThrow(step *model.FlowStep, handlers [][]model.FlowStep, caught []string) {
+	types := step.Exceptions
+	if len(types) == 0 {
+		if len(caught) == 0 {
+			step.ResolutionEvidence = "thrown type not determined"
+			if m.async > 0 {
+				return
+			}
+			m.statuses = append(m.statuses, model.FlowErrorStatus{Mapping: step.ResolutionEvidence, Evidence: step.Evidence})
+			return
+		}
+		// Rethrow inside a catch block
+		types = caught
+	}
+
+	declared := step.HTTPStatus
+	step.HTTPStatus = 0
+	var mappings []string
+	uncaught := 0
+	for _, t := range types {
+		if by, ok := m.catchingHandler(t, handlers); ok {
+			if step.CaughtAt == "" {
+				step.CaughtAt = by.Evidence
+			}
+			m.caughtBy[by.Evidence] = append(m.caughtBy[by.Evidence], t)
+			mappings = append(mappings, fmt.Sprintf("%s caught by catch (%s) at %s", t, strings.Join(by.Exceptions, " | "), by.Evidence))
+			continue
+		}
+		uncaught++
+		if m.async > 0 {
+			mappings = append(mappings, fmt.Sprintf("%s completes the async task exceptionally; does not reach the HTTP response", t))
+			continue
+		}
+		status, mapping := m.statusOf(t, declared)
+		if len(types) == 1 {
+			step.HTTPStatus = status
+		}
+		mappings = append(mappings, mapping)
+		m.statuses = append(m.statuses, model.FlowErrorStatus{
+			Status:    status,
+			Exception: t,
+			Mapping:   mapping,
+			Evidence:  step.Evidence,
+		})
+	}
+	if uncaught > 0 {
+		// Some types leave the handler; the throw does not end in a catch
+		step.CaughtAt = ""
+	}
+	step.ResolutionEvidence = strings.Join(mappings, "; ")
+}
+
+// catchingHandler returns the innermost enclosing catch that declares the type or a supertype.
+func (m *exceptionMapping) catchingHandler(exception string, handlers [][]model.FlowStep) (model.FlowStep, bool) {
+	chain := m.supertypes(exception)
+	for i := len(handlers) - 1; i >= 0; i-- {
+		for _, c := range handlers[i] {
+			for _, t := range c.Exceptions {
+				if slices.Contains(chain, t) {
+					return c, true
+				}
+			}
+		}
+	}
+	return model.FlowStep{}, false
+}
+
+// supertypes returns the exception followed by its known supertypes, nearest first. Types
+// declared in the service sources use their extends clause; JAX-RS and common JDK types use
+// exceptionParents. Exception and Throwable close every chain.
+func (m *exceptionMapping) supertypes(exception string) []string {
+	chain := []string{exception}
+	for cur := exception; ; {
+		parent := exceptionParents[cur]
+		if types := m.index.byName[cur]; len(types) == 1 && len(types[0].Extends) > 0 {
+			parent = types[0].Extends[0]
+		}
+		if parent == "" || slices.Contains(chain, parent) {
+			break
+		}
+		chain = append(chain, parent)
+		cur = parent
+	}
+	for _, t := range []string{"Exception", "Throwable"} {
+		if !slices.Contains(chain, t) {
+			chain = append(chain, t)
+		}
+	}
+	return chain
+}
+
+// statusOf maps an uncaught exception to its HTTP status: the ExceptionMapper of the nearest
+// type in its supertype chain, then the JAX-RS exception status, then a status passed to the
+// constructor. Other exceptions produce the container default 500.
+func (m *exceptionMapping) statusOf(exception string, declared int) (int, string) {
+	chain := m.supertypes(exception)
+
+	for _, t := range chain {
+		for _, em := range m.service.ExceptionMappers {
+			if em.Exception != t {
+				continue
+			}
+			via := ""
+			if t != exception {
+				via = fmt.Sprintf(" for %s", t)
+			}
+			switch len(em.Statuses) {
+			case 1:
+				return em.Statuses[0], fmt.Sprintf("%s -> %d by ExceptionMapper %s%s (%s)", exception, em.Statuses[0], em.Class, via, em.Evidence)
+			case 0:
+				return 0, fmt.Sprintf("%s handled by ExceptionMapper %s%s; status not determined statically (%s)", exception, em.Class, via, em.Evidence)
+			default:
+				return 0, fmt.Sprintf("%s handled by ExceptionMapper %s%s; one of %s (%s)", exception, em.Class, via, joinStatuses(em.Statuses), em.Evidence)
+			}
+		}
+	}
+
+	if status, ok := jaxrsExceptionStatuses[exception]; ok {
+		return status, fmt.Sprintf("%s -> %d (JAX-RS)", exception, status)
+	}
+	if declared != 0 {
+		return declared, fmt.Sprintf("%s -> %d (status argument)", exception, declared)
+	}
+	for _, t := range chain[1:] {
+		if status, ok := jaxrsExceptionStatuses[t]; ok {
+			return status, fmt.Sprintf("%s extends %s -> %d (JAX-RS)", exception, t, status)
+		}
+	}
+	if slices.Contains(chain, "WebApplicationException") {
+		return 0, fmt.Sprintf("%s is a WebApplicationException; status not determined statically", exception)
+	}
+	return 500, fmt.Sprintf("%s has no ExceptionMapper -> 500 (container default)", exception)
+}
+
+// joinStatuses formats a status list ("400, 409").
+func joinStatuses(statuses []int) string {
+	parts := make([]string, len(statuses))
+	for i, s := range statuses {
+		parts[i] = fmt.Sprintf("%d", s)
+	}
+	return strings.Join(parts, ", ")
+}
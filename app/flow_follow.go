@@ -0,0 +1,103 @@
+package app
+
+import (
+	"fmt"
+	"jz/model"
+	"slices"
+)
+
+// callFollower continues flows across resolved cross-service REST calls. It is shared by
+// the flow of the requested handler and the flows it follows into.
+type callFollower struct {
+	services []model.Service
+	maxHops  int                   // Cross-service depth budget
+	indexes  map[string]*typeIndex // Service name -> type index, built on first use
+	path     []string              // Handlers on the current call path (service:Resource.method)
+}
+
+// newCallFollower returns a follower with the given cross-service depth budget, or nil when
+// following is disabled.
+func newCallFollower(services []model.Service, maxHops int) *callFollower {
+	if maxHops <= 0 {
+		return nil
+	}
+	return &callFollower{services: services, maxHops: maxHops, indexes: make(map[string]*typeIndex)}
+}
+
+// follow extracts the flow of the REST method a cross-service call targets. It returns the
+// blocks of the target flow, with every step marked with the target service, and how the
+// call was (or was not) followed. Calls are not followed past the depth budget or into a
+// handler already on the call path.
+func (f *callFollower) follow(call model.RESTCall, maxDepth int) ([]model.FlowNode, string) {
+	svc, res, method, ok := f.target(call)
+	if !ok {
+		return nil, fmt.Sprintf("not followed - no unique %s method in %s -> %s", call.HTTPMethod, call.TargetService, call.TargetResource)
+	}
+	key := fmt.Sprintf("%s:%s", svc.Name, method.Handler)
+	if slices.Contains(f.path, key) {
+		return nil, fmt.Sprintf("not followed - %s is already on the call path (cycle)", key)
+	}
+	if len(f.path)-1 >= f.maxHops {
+		return nil, fmt.Sprintf("not followed - cross-service depth limit (%d)", f.maxHops)
+	}
+
+	index, ok := f.indexes[svc.Name]
+	if !ok {
+		index = buildTypeIndex(svc)
+		f.indexes[svc.Name] = index
+	}
+	flow, ok := extractMethodFlow(svc, res, method, index, maxDepth, f)
+	if !ok {
+		return nil, fmt.Sprintf("not followed - handler %s not parsed", method.Handler)
+	}
+	markService(flow.Blocks, svc.Name)
+	return flow.Blocks, fmt.Sprintf("followed into %s %s (%s %s)", svc.Name, method.Handler, method.HTTPMethod, method.FullPath)
+}
+
+// target locates the REST method a resolved call reaches: the method of the target resource
+// with the call's HTTP method and path, or the servlet method whose url-pattern matches.
+// Only a unique match is returned.
+func (f *callFollower) target(call model.RESTCall) (*model.Service, *model.RESTResource, model.RESTMethod, bool) {
+	for i := range f.services {
+		svc := &f.services[i]
+		if svc.Name != call.TargetService {
+			continue
+		}
+		for j := range svc.RESTResources {
+			res := &svc.RESTResources[j]
+			if res.Name != call.TargetResource {
+				continue
+			}
+			var matches []model.RESTMethod
+			for _, m := range res.Methods {
+				if m.HTTPMethod == call.HTTPMethod && m.FullPath == call.TargetPath {
+					matches = append(matches, m)
+				}
+			}
+			if len(matches) == 0 && res.Kind == model.EntryPointServlet {
+				for _, m := range res.Methods {
+					if (m.HTTPMethod == call.HTTPMethod || m.HTTPMethod == model.HTTPMethodAny) && matchURLPattern(m.FullPath, call.TargetPath) {
+						matches = append(matches, m)
+					}
+				}
+			}
+			if len(matches) == 1 {
+				return svc, res, matches[0], true
+			}
+			return nil, nil, model.RESTMethod{}, false
+		}
+	}
+	return nil, nil, model.RESTMethod{}, false
+}
+
+// markService records the service on the steps of a followed flow. Steps of flows followed
+// further keep the service they run in.
+func markService(nodes []model.FlowNode, service string) {
+	for i := range nodes {
+		if nodes[i].Step.Service == "" {
+			nodes[i].Step.Service = service
+		}
+		markService(nodes[i].Children, service)
+		markService(nodes[i].Else, service)
+	}
+}
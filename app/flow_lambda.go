@@ -0,0 +1,404 @@
+package app
+
+import (
+	"fmt"
+	"jz/model"
+	"regexp"
+	"strings"
+)
+
+// methodRefRegex matches method references (this::enrich, repo::save, Mapper::toDto).
+var methodRefRegex = regexp.MustCompile(`\b(this|super|[A-Za-z_]\w*)::(\w+)`)
+
+// asyncHosts are methods whose functional argument runs as an asynchronous task
+// (executors and CompletableFuture stages) rather than in the calling thread.
+var asyncHosts = map[string]bool{
+	"supplyAsync": true, "runAsync": true,
+	"thenApply": true, "thenApplyAsync": true,
+	"thenAccept": true, "thenAcceptAsync": true,
+	"thenRun": true, "thenRunAsync": true,
+	"thenCompose": true, "thenComposeAsync": true,
+	"thenCombine": true, "thenCombineAsync": true,
+	"whenComplete": true, "whenCompleteAsync": true,
+	"handle": true, "handleAsync": true,
+	"exceptionally": true,
+	"submit":        true, "execute": true, "schedule": true,
+	"scheduleAtFixedRate": true, "scheduleWithFixedDelay": true,
+}
+
+// lambdaPlaceholder stands in for a lambda in the statement text parsed without it.
+const lambdaPlaceholder = "λ"
+
+// lambdaScope is a lambda expression or method reference found in a statement.
+type lambdaScope struct {
+	start, end int    // Byte range in the statement text
+	text       string // Source text of the lambda or method reference
+	host       string // Method receiving it as an argument (map, forEach, supplyAsync)
+	body       string // Lambda body (expression, or inline block content)
+	open       bool   // Block body that continues on the following lines
+	refTarget  string // Method reference receiver (this, a field or a class)
+	refMethod  string
+}
+
+// scope returns the execution scope the lambda opens.
+func (sc lambdaScope) scope() string {
+	if asyncHosts[sc.host] {
+		return model.FlowScopeAsync
+	}
+	return model.FlowScopeLambda
+}
+
+// findLambdaScopes returns the top-level lambdas and method references of a statement,
+// in source order. Lambdas nested in another lambda's body are found when that body is parsed.
+func findLambdaScopes(text string) []lambdaScope {
+	if strings.HasPrefix(text, "case ") || strings.HasPrefix(text, "default") {
+		// Arrow labels of a switch
+		return nil
+	}
+	literal := literalMask(text)
+	var scopes []lambdaScope
+	inside := func(pos int) bool {
+		for _, sc := range scopes {
+			if pos >= sc.start && pos < sc.end {
+				return true
+			}
+		}
+		return false
+	}
+
+	// Lambdas: "x -> expr", "(a, b) -> expr", "() -> { ... }"
+	for i := 0; i+1 < len(text); i++ {
+		if literal[i] || text[i] != '-' || text[i+1] != '>' || inside(i) {
+			continue
+		}
+		start := lambdaParamsStart(text, i)
+		if start < 0 {
+			continue
+		}
+		sc := lambdaScope{start: start, host: hostCall(text, start, literal)}
+		k := i + 2
+		for k < len(text) && text[k] == ' ' {
+			k++
+		}
+		if k < len(text) && text[k] == '{' {
+			if close := matchingClose(text, k, literal); close >= 0 {
+				sc.body, sc.end = strings.TrimSpace(text[k+1:close]), close+1
+			} else {
+				sc.open, sc.end = true, len(text)
+			}
+		} else {
+			sc.end = expressionEnd(text, k, literal)
+			sc.body = strings.TrimSpace(text[k:sc.end])
+		}
+		sc.text = strings.TrimSpace(text[sc.start:sc.end])
+		scopes = append(scopes, sc)
+		i = sc.end - 1
+	}
+
+	// Method references (constructor references are not followed)
+	for _, m := range methodRefRegex.FindAllStringSubmatchIndex(text, -1) {
+		if literal[m[0]] || inside(m[0]) || text[m[4]:m[5]] == "new" {
+			continue
+		}
+		scopes = append(scopes, lambdaScope{
+			start:     m[0],
+			end:       m[1],
+			text:      text[m[0]:m[1]],
+			host:      hostCall(text, m[0], literal),
+			refTarget: text[m[2]:m[3]],
+			refMethod: text[m[4]:m[5]],
+		})
+	}
+
+	// Source order
+	for i := 1; i < len(scopes); i++ {
+		for j := i; j > 0 && scopes[j].start < scopes[j-1].start; j-- {
+			scopes[j], scopes[j-1] = scopes[j-1], scopes[j]
+		}
+	}
+	return scopes
+}
+
+// parseLambdaStatement parses a statement containing lambdas or method references. The
+// statement without them is parsed as usual; each lambda becomes a lambda step holding the
+// steps of its body. Lambdas of a returned expression run before the return.
+func (p *methodFlowParser) parseLambdaStatement(trimmed string, line int, scopes []lambdaScope) []model.FlowNode {
+	var direct strings.Builder
+	var lambdas []model.FlowNode
+	var texts []string
+	last := 0
+	for _, sc := range scopes {
+		direct.WriteString(trimmed[last:sc.start])
+		direct.WriteString(lambdaPlaceholder)
+		last = sc.end
+		texts = append(texts, sc.text)
+		lambdas = append(lambdas, p.lambdaNode(sc, line, nil))
+	}
+	direct.WriteString(trimmed[last:])
+
+	directNodes := p.parseDirect(direct.String(), line, texts)
+	if strings.HasPrefix(trimmed, "return") {
+		return append(lambdas, directNodes...)
+	}
+	return append(directNodes, lambdas...)
+}
+
+// parseBlockLambda parses a statement whose last lambda opens a block body that closes on a
+// later line ("list.forEach(x -> {"). It returns the steps and the next line to parse.
+func (p *methodFlowParser) parseBlockLambda(trimmed string, line int) ([]model.FlowNode, int) {
+	scopes := findLambdaScopes(trimmed)
+	sc := scopes[len(scopes)-1]
+	brace := sc.start + strings.Index(trimmed[sc.start:], "{")
+	closeLine, _, tail := findBlockEnd(p.lines, line, trimmed[brace:])
+	sc.text = strings.TrimSpace(trimmed[sc.start:brace]) + " { ... }"
+
+	node := p.lambdaNode(sc, line, p.parseRange(line+1, closeLine))
+
+	// The statement around the lambda, with the text following the closing brace
+	rest := trimmed[:sc.start] + lambdaPlaceholder + strings.TrimSpace(tail)
+	var directNodes []model.FlowNode
+	if others := findLambdaScopes(rest); len(others) > 0 {
+		directNodes = p.parseLambdaStatement(rest, line, others)
+	} else {
+		directNodes = p.parseDirect(rest, line, []string{sc.text})
+	}
+	if strings.HasPrefix(trimmed, "return") {
+		return append([]model.FlowNode{node}, directNodes...), closeLine + 1
+	}
+	return append(directNodes, node), closeLine + 1
+}
+
+// parseDirect parses a statement whose lambdas were replaced by placeholders and restores
+// their source text in the descriptions of its steps and statuses.
+func (p *methodFlowParser) parseDirect(direct string, line int, texts []string) []model.FlowNode {
+	restore := func(s string) string {
+		for _, t := range texts {
+			s = strings.Replace(s, lambdaPlaceholder, t, 1)
+		}
+		return s
+	}
+	before := len(p.state.statuses)
+	nodes := p.parseStatement(direct, line)
+	for i := range nodes {
+		nodes[i].Step.Description = restore(nodes[i].Step.Description)
+	}
+	for i := before; i < len(p.state.statuses); i++ {
+		p.state.statuses[i].Detail = restore(p.state.statuses[i].Detail)
+	}
+	return nodes
+}
+
+// lambdaNode builds the lambda step of a scope. body holds the parsed steps of a block body
+// spanning several lines; otherwise the inline body or the referenced method is parsed.
+func (p *methodFlowParser) lambdaNode(sc lambdaScope, line int, body []model.FlowNode) model.FlowNode {
+	lineNum := line + 1
+	scope := sc.scope()
+
+	label := "Lambda"
+	switch {
+	case scope == model.FlowScopeAsync:
+		label = "Async"
+	case sc.refMethod != "":
+		label = "Method reference"
+	}
+	desc := fmt.Sprintf("%s: %s", label, sc.text)
+	if sc.host != "" {
+		desc = fmt.Sprintf("%s (%s): %s", label, sc.host, sc.text)
+	}
+
+	switch {
+	case sc.refMethod != "":
+		body = p.methodRefSteps(sc, lineNum)
+	case body == nil && sc.body != "":
+		body = p.parseText(sc.body, line)
+	}
+	markScope(body, scope)
+
+	return model.FlowNode{
+		Step: model.FlowStep{
+			Kind:           model.FlowStepLambda,
+			Description:    desc,
+			FromMethod:     p.fullHandler,
+			Confidence:     model.ConfidenceMedium,
+			Evidence:       fmt.Sprintf("%s:%d", p.sourceFile, lineNum),
+			ExecutionScope: scope,
+		},
+		Children: body,
+	}
+}
+
+// methodRefSteps expands a method reference like a direct call: this::m and ClassName::m
+// on the current class as internal calls, field::m through the field's implementation.
+func (p *methodFlowParser) methodRefSteps(sc lambdaScope, lineNum int) []model.FlowNode {
+	switch sc.refTarget {
+	case "this", "super", p.className:
+		if sc.refMethod == p.methodName {
+			return nil
+		}
+		return p.internalCall(sc.refMethod, lineNum)
+	}
+	call := fmt.Sprintf("%s.%s(", sc.refTarget, sc.refMethod)
+	if target, ok := p.index.resolveFieldCall(call, p.sourceFile, p.className, p.service); ok {
+		return fieldCallSteps(target, p.fullHandler, fmt.Sprintf("%s:%d", p.sourceFile, lineNum), p.service, p.index, p.depth, p.maxDepth, p.state)
+	}
+	return nil
+}
+
+// markScope records the execution scope on steps nested in a lambda. An async scope
+// overrides an inner lambda scope.
+func markScope(nodes []model.FlowNode, scope string) {
+	for i := range nodes {
+		if nodes[i].Step.ExecutionScope == "" || scope == model.FlowScopeAsync {
+			nodes[i].Step.ExecutionScope = scope
+		}
+		markScope(nodes[i].Children, scope)
+		markScope(nodes[i].Else, scope)
+	}
+}
+
+// isBlockLambdaStatement reports whether the statement ends in a lambda block body that
+// continues on the following lines.
+func isBlockLambdaStatement(text string) bool {
+	if !strings.Contains(text, "->") {
+		return false
+	}
+	scopes := findLambdaScopes(text)
+	return len(scopes) > 0 && scopes[len(scopes)-1].open
+}
+
+// lambdaParamsStart returns the start of the parameter list of the lambda whose arrow is at
+// arrow ("x ->", "(a, b) ->"), or -1 if there is none.
+func lambdaParamsStart(text string, arrow int) int {
+	j := arrow - 1
+	for j >= 0 && text[j] == ' ' {
+		j--
+	}
+	if j < 0 {
+		return -1
+	}
+	if text[j] == ')' {
+		depth := 0
+		for ; j >= 0; j-- {
+			switch text[j] {
+			case ')':
+				depth++
+			case '(':
+				depth--
+				if depth == 0 {
+					return j
+				}
+			}
+		}
+		return -1
+	}
+	end := j
+	for j >= 0 && isIdentChar(text[j]) {
+		j--
+	}
+	if j == end {
+		return -1
+	}
+	return j + 1
+}
+
+// hostCall returns the name of the method whose argument list encloses pos.
+func hostCall(text string, pos int, literal []bool) string {
+	depth := 0
+	for q := pos - 1; q >= 0; q-- {
+		if literal[q] {
+			continue
+		}
+		switch text[q] {
+		case ')':
+			depth++
+		case '(':
+			if depth > 0 {
+				depth--
+				continue
+			}
+			e := q
+			for e > 0 && text[e-1] == ' ' {
+				e--
+			}
+			s := e
+			for s > 0 && isIdentChar(text[s-1]) {
+				s--
+			}
+			return text[s:e]
+		}
+	}
+	return ""
+}
+
+// expressionEnd returns the end of an expression lambda body starting at start: the first
+// top-level ',' or ';', or the parenthesis closing the enclosing argument list.
+func expressionEnd(text string, start int, literal []bool) int {
+	depth := 0
+	for i := start; i < len(text); i++ {
+		if literal[i] {
+			continue
+		}
+		switch text[i] {
+		case '(', '[', '{':
+			depth++
+		case ')', ']', '}':
+			if depth == 0 {
+				return i
+			}
+			depth--
+		case ',', ';':
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return len(text)
+}
+
+// matchingClose returns the index of the brace closing the one at open, or -1.
+func matchingClose(text string, open int, literal []bool) int {
+	depth := 0
+	for i := open; i < len(text); i++ {
+		if literal[i] {
+			continue
+		}
+		switch text[i] {
+		case '{':
+			depth++
+		case '}':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// literalMask marks the bytes of text that are inside string or char literals.
+func literalMask(text string) []bool {
+	mask := make([]bool, len(text))
+	var quote byte
+	for i := 0; i < len(text); i++ {
+		c := text[i]
+		if quote != 0 {
+			mask[i] = true
+			if c == '\\' && i+1 < len(text) {
+				i++
+				mask[i] = true
+			} else if c == quote {
+				quote = 0
+			}
+			continue
+		}
+		if c == '"' || c == '\'' {
+			quote = c
+			mask[i] = true
+		}
+	}
+	return mask
+}
+
+func isIdentChar(c byte) bool {
+	return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9') || c == '_'
+}
@@ -0,0 +1,158 @@
+package app
+
+import (
+	"fmt"
+	"jz/model"
+	"jz/scan"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// fieldCallRegex matches calls through a receiver identifier (orderService.create( / this.repo.save().
+var fieldCallRegex = regexp.MustCompile(`(?:\bthis\.)?\b([a-z_]\w*)\.(\w+)\s*\(`)
+
+// typeIndex indexes the Java types of a service for cross-class flow expansion.
+type typeIndex struct {
+	byName map[string][]scan.JavaType
+	byFile map[string]scan.JavaType
+}
+
+// buildTypeIndex scans the service sources once per flow extraction.
+func buildTypeIndex(svc *model.Service) *typeIndex {
+	idx := &typeIndex{
+		byName: make(map[string][]scan.JavaType),
+		byFile: make(map[string]scan.JavaType),
+	}
+	if svc.RootPath == "" || svc.Tree == nil {
+		return idx
+	}
+	types, err := scan.ScanJavaTypes(svc.Tree, svc.RootPath)
+	if err != nil {
+		return idx
+	}
+	for _, t := range types {
+		idx.byName[t.Name] = append(idx.byName[t.Name], t)
+		idx.byFile[t.SourceFile] = t
+	}
+	return idx
+}
+
+// receiverTarget is the implementation a field-receiver call was resolved to.
+type receiverTarget struct {
+	Field      string
+	Method     string
+	Class      string // Implementation class (empty if unresolved)
+	SourceFile string
+	Confidence string
+	Evidence   string // Resolution evidence, or the reason resolution failed
+}
+
+// resolveFieldCall finds the first call on the line made through a field of the current class
+// and maps the field type to its implementation:
+//
+//  1. the CDI injection point for the field, if resolved to a bean class
+//  2. a unique DS component providing the field type
+//  3. the field type itself, if it is a concrete class
+//  4. a unique concrete class implementing or extending the field type
+//
+// ok is false when the line has no call through a field whose type is declared in the service.
+func (idx *typeIndex) resolveFieldCall(line, sourceFile, className string, svc *model.Service) (receiverTarget, bool) {
+	current, ok := idx.byFile[sourceFile]
+	if !ok {
+		return receiverTarget{}, false
+	}
+
+	for _, m := range fieldCallRegex.FindAllStringSubmatch(line, -1) {
+		typ, isField := current.Fields[m[1]]
+		if !isField || len(idx.byName[typ]) == 0 {
+			continue
+		}
+		target := receiverTarget{Field: m[1], Method: m[2]}
+
+		impl, confidence, evidence := idx.implementationOf(typ, className, m[1], svc)
+		if impl == "" {
+			target.Evidence = evidence
+			return target, true
+		}
+		types := idx.byName[impl]
+		if len(types) != 1 {
+			target.Evidence = fmt.Sprintf("implementation class %s is not unique in the service sources", impl)
+			return target, true
+		}
+		target.Class = impl
+		target.SourceFile = types[0].SourceFile
+		target.Confidence = confidence
+		target.Evidence = evidence
+		return target, true
+	}
+	return receiverTarget{}, false
+}
+
+// implementationOf maps a field type to its implementation class. An empty class means the
+// mapping is not unique; the evidence then explains why.
+func (idx *typeIndex) implementationOf(typ, className, field string, svc *model.Service) (string, string, string) {
+	// 1. CDI injection point resolution
+	for _, ip := range svc.InjectionPoints {
+		if ip.Class != className || ip.Member != field || ip.Status != model.InjectionResolved {
+			continue
+		}
+		for _, b := range svc.Beans {
+			if b.Name == ip.Bean && b.Kind != model.BeanProducer {
+				return b.Class, model.ConfidenceHigh, "CDI: " + ip.Explanation
+			}
+		}
+	}
+
+	// 2. DS component providing the interface
+	var dsImpls []string
+	var dsEvidence string
+	for _, comp := range svc.Components {
+		for _, iface := range comp.ProvidedInterfaces {
+			if iface == typ || strings.HasSuffix(iface, "."+typ) {
+				dsImpls = append(dsImpls, simpleName(comp.ImplementationClass))
+				dsEvidence = fmt.Sprintf("DS component %s provides %s (%s)", comp.Name, iface, comp.SourceXML)
+			}
+		}
+	}
+	if len(dsImpls) == 1 {
+		return dsImpls[0], model.ConfidenceHigh, dsEvidence
+	}
+	if len(dsImpls) > 1 {
+		return "", "", fmt.Sprintf("%d DS components provide %s", len(dsImpls), typ)
+	}
+
+	// 3. Concrete field type
+	declared := idx.byName[typ]
+	if len(declared) == 1 && !declared[0].Interface && !declared[0].Abstract {
+		return typ, model.ConfidenceHigh, fmt.Sprintf("field type %s is a concrete class (%s)", typ, declared[0].SourceFile)
+	}
+
+	// 4. Unique implementing class
+	var impls []scan.JavaType
+	for _, types := range idx.byName {
+		for _, t := range types {
+			if t.Interface || t.Abstract {
+				continue
+			}
+			if slices.Contains(t.Implements, typ) || slices.Contains(t.Extends, typ) {
+				impls = append(impls, t)
+			}
+		}
+	}
+	if len(impls) == 1 {
+		return impls[0].Name, model.ConfidenceMedium, fmt.Sprintf("unique implementation of %s (%s)", typ, impls[0].SourceFile)
+	}
+	if len(impls) == 0 {
+		return "", "", fmt.Sprintf("no implementation of %s found in the service sources", typ)
+	}
+	return "", "", fmt.Sprintf("%d implementations of %s found; none selected", len(impls), typ)
+}
+
+// simpleName strips the package from a qualified class name.
+func simpleName(qualified string) string {
+	if idx := strings.LastIndex(qualified, "."); idx >= 0 {
+		return qualified[idx+1:]
+	}
+	return qualified
+}
@@ -0,0 +1,46 @@
+package app
+
+import (
+	"archive/zip"
+	"bytes"
+	"fmt"
+	"io/fs"
+	"os/exec"
+	"strings"
+)
+
+// RevisionFS returns the files of a git revision as a file system held in memory. The
+// files are read from the local object store with git archive, so no checkout or network
+// access is needed. When repoPath is a subdirectory of the work tree, only that
+// subdirectory is returned.
+func RevisionFS(repoPath, rev string) (fs.FS, error) {
+	if _, err := runGit(repoPath, "rev-parse", "--verify", "--quiet", rev+"^{commit}"); err != nil {
+		return nil, fmt.Errorf("unknown git revision '%s' in %s", rev, repoPath)
+	}
+	out, err := runGit(repoPath, "rev-parse", "--show-toplevel", "--show-prefix")
+	if err != nil {
+		return nil, err
+	}
+	lines := strings.Split(strings.TrimRight(string(out), "\n"), "\n")
+	top, treeish := lines[0], rev
+	if len(lines) > 1 && lines[1] != "" {
+		treeish = rev + ":" + strings.TrimSuffix(lines[1], "/")
+	}
+	archive, err := runGit(top, "archive", "--format=zip", treeish)
+	if err != nil {
+		return nil, err
+	}
+	return zip.NewReader(bytes.NewReader(archive), int64(len(archive)))
+}
+
+// runGit runs a git command in dir and returns its standard output.
+func runGit(dir string, args ...string) ([]byte, error) {
+	cmd := exec.Command("git", append([]string{"-C", dir}, args...)...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("git %s: %v: %s", args[0], err, strings.TrimSpace(stderr.String()))
+	}
+	return out, nil
+}
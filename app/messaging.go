@@ -0,0 +1,149 @@
+package app
+
+import (
+	"jz/model"
+	"sort"
+	"strings"
+)
+
+// attachMessaging assigns message producers and consumers under the service root to the service.
+func attachMessaging(svc *model.Service, a sourceArtifacts) {
+	for _, ep := range a.messaging {
+		if !underRoot(ep.SourceFile, svc.RootPath) {
+			continue
+		}
+		ep.Service = svc.Name
+		svc.MessageEndpoints = append(svc.MessageEndpoints, ep)
+	}
+	for _, cfg := range a.channelConfig {
+		if underRoot(cfg.SourceFile, svc.RootPath) {
+			svc.ChannelConfigs = append(svc.ChannelConfigs, cfg)
+		}
+	}
+}
+
+// resolveMessageEndpoints maps source-level destination references to physical destinations.
+//
+//   - name: the literal is the physical destination name.
+//   - jndi: resolved through a server.xml jmsQueue/jmsTopic with that jndiName; without a
+//     definition the JNDI name itself identifies the channel.
+//   - activation-spec: resolved through a unique jmsActivationSpec whose id ends with
+//     /<MDB class>, then its destinationRef or destinationLookup. Otherwise unresolved.
+//   - channel: resolved through the service's unique mp.messaging.incoming|outgoing.<channel>
+//     connector; the topic (or channel name) becomes the destination. Channels without
+//     a connector are in-memory and stay unresolved.
+func resolveMessageEndpoints(services []model.Service, srv model.LibertyServer) {
+	byID := make(map[string]model.JMSDestination)
+	byJNDI := make(map[string]model.JMSDestination)
+	for _, d := range srv.JMSDestinations {
+		byID[d.ID] = d
+		if d.JNDIName != "" {
+			byJNDI[d.JNDIName] = d
+		}
+	}
+
+	resolveJNDI := func(ep *model.MessageEndpoint, jndi string) {
+		name := strings.TrimPrefix(jndi, "java:comp/env/")
+		if d, ok := byJNDI[name]; ok {
+			ep.Destination = physicalName(d)
+			ep.DestinationKind = d.Kind
+			ep.ResolutionEvidence = "jndiName " + name + " defined in " + srv.ServerXML
+			return
+		}
+		ep.Destination = name
+		ep.ResolutionEvidence = "JNDI name (no server.xml definition)"
+	}
+
+	for i := range services {
+		for j := range services[i].MessageEndpoints {
+			ep := &services[i].MessageEndpoints[j]
+
+			switch ep.ReferenceType {
+			case "name":
+				ep.Destination = ep.Reference
+				ep.ResolutionEvidence = "literal destination name"
+			case "jndi":
+				resolveJNDI(ep, ep.Reference)
+			case "activation-spec":
+				var matches []model.JMSActivationSpec
+				for _, as := range srv.ActivationSpecs {
+					if strings.HasSuffix(as.ID, "/"+ep.Reference) {
+						matches = append(matches, as)
+					}
+				}
+				if len(matches) != 1 {
+					continue
+				}
+				if d, ok := byID[matches[0].DestinationRef]; ok {
+					ep.Destination = physicalName(d)
+					ep.DestinationKind = d.Kind
+					ep.ResolutionEvidence = "jmsActivationSpec " + matches[0].ID + " -> " + d.ID
+				} else if matches[0].DestinationLookup != "" {
+					resolveJNDI(ep, matches[0].DestinationLookup)
+				}
+			case "channel":
+				resolveChannel(ep, services[i].ChannelConfigs)
+			}
+		}
+
+		sort.Slice(services[i].MessageEndpoints, func(a, b int) bool {
+			return services[i].MessageEndpoints[a].Evidence < services[i].MessageEndpoints[b].Evidence
+		})
+	}
+}
+
+// resolveChannel maps a reactive messaging channel to its configured connector and destination.
+func resolveChannel(ep *model.MessageEndpoint, configs []model.ChannelConfig) {
+	direction := "incoming"
+	if ep.Role == model.MessageProducer {
+		direction = "outgoing"
+	}
+
+	var matches []model.ChannelConfig
+	for _, cfg := range configs {
+		if cfg.Direction == direction && cfg.Channel == ep.Reference && cfg.Connector != "" {
+			matches = append(matches, cfg)
+		}
+	}
+	if len(matches) != 1 {
+		if len(matches) == 0 {
+			ep.ResolutionEvidence = "no mp.messaging." + direction + "." + ep.Reference + ".connector (in-memory channel)"
+		} else {
+			ep.ResolutionEvidence = "ambiguous mp.messaging." + direction + "." + ep.Reference + " configuration"
+		}
+		return
+	}
+
+	cfg := matches[0]
+	ep.Protocol = connectorProtocol(cfg.Connector)
+	ep.Destination = cfg.Destination
+	if ep.Destination == "" {
+		ep.Destination = ep.Reference
+	}
+	if ep.Protocol == model.ProtocolKafka {
+		ep.DestinationKind = "topic"
+	}
+	ep.ResolutionEvidence = "mp.messaging." + direction + "." + ep.Reference + " connector " + cfg.Connector + " at " + cfg.Evidence
+}
+
+// connectorProtocol derives the messaging protocol from a connector name (smallrye-kafka -> kafka).
+func connectorProtocol(connector string) string {
+	c := strings.ToLower(connector)
+	for _, p := range []string{model.ProtocolKafka, "amqp", "mqtt", model.ProtocolJMS} {
+		if strings.Contains(c, p) {
+			return p
+		}
+	}
+	return c
+}
+
+// physicalName returns the broker-level destination name, falling back to the JNDI name and id.
+func physicalName(d model.JMSDestination) string {
+	if d.PhysicalName != "" {
+		return d.PhysicalName
+	}
+	if d.JNDIName != "" {
+		return d.JNDIName
+	}
+	return d.ID
+}
@@ -0,0 +1,176 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+	"jz/model"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+
+	"go.yaml.in/yaml/v3"
+)
+
+// specMethods are the operation keys of an OpenAPI path item.
+var specMethods = []string{"get", "put", "post", "delete", "options", "head", "patch", "trace"}
+
+// LoadOpenAPISpec reads the operations of an OpenAPI 3 document (YAML or JSON). Each
+// operation keeps the file:line where it is declared; request body and response media
+// types are read through local $ref references to components.
+func LoadOpenAPISpec(path string) (model.APISpec, error) {
+	root, err := readYAMLMapping(path)
+	if err != nil {
+		return model.APISpec{}, err
+	}
+	return openAPISpec(root, path)
+}
+
+// errNotMapping is returned by readYAMLMapping for documents whose top level is not a mapping.
+var errNotMapping = errors.New("not a YAML or JSON mapping")
+
+// readYAMLMapping parses a YAML or JSON file whose top level is a mapping.
+func readYAMLMapping(path string) (*yaml.Node, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("parsing %s: %v", path, err)
+	}
+	if len(doc.Content) == 0 || doc.Content[0].Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("%s is %w", path, errNotMapping)
+	}
+	return doc.Content[0], nil
+}
+
+// openAPISpec reads the operations of a parsed OpenAPI 3 document.
+func openAPISpec(root *yaml.Node, path string) (model.APISpec, error) {
+	spec := model.APISpec{Source: path}
+	if v := mappingValue(root, "openapi"); v != nil {
+		spec.Version = v.Value
+	}
+	if !strings.HasPrefix(spec.Version, "3.") {
+		if mappingValue(root, "swagger") != nil {
+			return spec, fmt.Errorf("%s is a Swagger 2 document; only OpenAPI 3 is supported", path)
+		}
+		return spec, fmt.Errorf("%s is not an OpenAPI 3 document (no openapi: 3.x field)", path)
+	}
+	if title := mappingValue(mappingValue(root, "info"), "title"); title != nil {
+		spec.Title = title.Value
+	}
+	if servers := mappingValue(root, "servers"); servers != nil {
+		for _, server := range servers.Content {
+			if u := mappingValue(server, "url"); u != nil {
+				spec.Servers = append(spec.Servers, u.Value)
+			}
+		}
+	}
+	if len(spec.Servers) > 0 {
+		spec.BasePath = serverBasePath(spec.Servers[0])
+	}
+
+	paths := mappingValue(root, "paths")
+	if paths == nil {
+		return spec, nil
+	}
+	for i := 0; i+1 < len(paths.Content); i += 2 {
+		p, item := paths.Content[i].Value, resolveRef(root, paths.Content[i+1])
+		for _, method := range specMethods {
+			op := mappingValue(item, method)
+			if op == nil {
+				continue
+			}
+			so := model.SpecOperation{
+				HTTPMethod: strings.ToUpper(method),
+				Path:       p,
+				Evidence:   fmt.Sprintf("%s:%d", path, op.Line),
+			}
+			if body := resolveRef(root, mappingValue(op, "requestBody")); body != nil {
+				so.Consumes = mappingKeys(mappingValue(body, "content"))
+			}
+			if responses := mappingValue(op, "responses"); responses != nil {
+				seen := make(map[string]bool)
+				for j := 1; j < len(responses.Content); j += 2 {
+					resp := resolveRef(root, responses.Content[j])
+					for _, mt := range mappingKeys(mappingValue(resp, "content")) {
+						if !seen[mt] {
+							seen[mt] = true
+							so.Produces = append(so.Produces, mt)
+						}
+					}
+				}
+				sort.Strings(so.Produces)
+			}
+			spec.Operations = append(spec.Operations, so)
+		}
+	}
+	return spec, nil
+}
+
+// serverBasePath returns the path of a server URL ("https://host/shop/api" -> "/shop/api").
+// Server variables in the path are kept as written.
+func serverBasePath(raw string) string {
+	p := raw
+	if u, err := url.Parse(raw); err == nil && u.Host != "" {
+		p = u.Path
+	} else if i := strings.Index(raw, "://"); i >= 0 {
+		p = raw[i+3:]
+		if j := strings.Index(p, "/"); j >= 0 {
+			p = p[j:]
+		} else {
+			p = ""
+		}
+	}
+	p = strings.TrimSuffix(p, "/")
+	if p != "" && !strings.HasPrefix(p, "/") {
+		p = "/" + p
+	}
+	return p
+}
+
+// mappingValue returns the value of a key in a YAML mapping, or nil.
+func mappingValue(node *yaml.Node, key string) *yaml.Node {
+	if node == nil || node.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+	return nil
+}
+
+// mappingKeys returns the keys of a YAML mapping in document order.
+func mappingKeys(node *yaml.Node) []string {
+	if node == nil || node.Kind != yaml.MappingNode {
+		return nil
+	}
+	var keys []string
+	for i := 0; i < len(node.Content); i += 2 {
+		keys = append(keys, node.Content[i].Value)
+	}
+	return keys
+}
+
+// resolveRef follows a local $ref ("#/components/responses/NotFound"). Other references
+// are left unresolved, and the node is returned as is.
+func resolveRef(root, node *yaml.Node) *yaml.Node {
+	for depth := 0; depth < 8; depth++ {
+		ref := mappingValue(node, "$ref")
+		if ref == nil || !strings.HasPrefix(ref.Value, "#/") {
+			return node
+		}
+		target := root
+		for _, part := range strings.Split(strings.TrimPrefix(ref.Value, "#/"), "/") {
+			part = strings.ReplaceAll(strings.ReplaceAll(part, "~1", "/"), "~0", "~")
+			if target = mappingValue(target, part); target == nil {
+				return node
+			}
+		}
+		node = target
+	}
+	return node
+}
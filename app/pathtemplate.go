@@ -0,0 +1,35 @@
+package app
+
+import (
+	"regexp"
+	"strings"
+)
+
+var templateParamRegex = regexp.MustCompile(`\{\s*(\w[\w.-]*)\s*(?::\s*([^}]*))?\}`)
+
+// PathTemplate turns a JAX-RS path into an OpenAPI path template: parameters with regular
+// expressions ({id: [0-9]+}) become {id}. It returns the parameter names in path order and
+// their expressions by name.
+func PathTemplate(path string) (string, []string, map[string]string) {
+	var names []string
+	patterns := make(map[string]string)
+	template := templateParamRegex.ReplaceAllStringFunc(path, func(t string) string {
+		m := templateParamRegex.FindStringSubmatch(t)
+		names = append(names, m[1])
+		if p := strings.TrimSpace(m[2]); p != "" {
+			patterns[m[1]] = p
+		}
+		return "{" + m[1] + "}"
+	})
+	return template, names, patterns
+}
+
+// templateShape erases the parameter names of a path template (/items/{id} -> /items/{}),
+// so templates that differ only by parameter names match. Trailing slashes are ignored.
+func templateShape(path string) string {
+	shape := templateParamRegex.ReplaceAllString(path, "{}")
+	if shape != "/" {
+		shape = strings.TrimSuffix(shape, "/")
+	}
+	return shape
+}
@@ -0,0 +1,162 @@
+package app
+
+import (
+	"fmt"
+	"jz/model"
+	"strings"
+)
+
+// attachPersistence assigns the database accesses and named queries located under the
+// service root to the service, and resolves named query lookups to the entities or
+// tables of their declaration.
+func attachPersistence(svc *model.Service, a sourceArtifacts) {
+	for _, e := range a.entities {
+		if underRoot(e.SourceFile, svc.RootPath) {
+			svc.Entities = append(svc.Entities, e)
+		}
+	}
+	for _, u := range a.units {
+		if underRoot(u.SourceFile, svc.RootPath) {
+			svc.PersistenceUnits = append(svc.PersistenceUnits, u)
+		}
+	}
+	assignEntityUnits(svc)
+	for _, q := range a.namedQueries {
+		if underRoot(q.SourceFile, svc.RootPath) {
+			svc.NamedQueries = append(svc.NamedQueries, q)
+		}
+	}
+	for _, da := range a.dataAccesses {
+		if !underRoot(da.SourceFile, svc.RootPath) {
+			continue
+		}
+		if da.Operation == "createNamedQuery" {
+			resolveNamedQuery(&da, svc.NamedQueries)
+		}
+		svc.DataAccesses = append(svc.DataAccesses, da)
+	}
+}
+
+// assignEntityUnits records the persistence unit managing each entity: the unit listing its
+// class, or the only unit of the service (units include the annotated classes of their
+// archive by default).
+func assignEntityUnits(svc *model.Service) {
+	for i := range svc.Entities {
+		e := &svc.Entities[i]
+		var listing []string
+		for _, u := range svc.PersistenceUnits {
+			for _, c := range u.Classes {
+				if c == e.Class || c == e.Package+"."+e.Class {
+					listing = append(listing, u.Name)
+					break
+				}
+			}
+		}
+		switch {
+		case len(listing) == 1:
+			e.Unit = listing[0]
+		case len(listing) == 0 && len(svc.PersistenceUnits) == 1:
+			e.Unit = svc.PersistenceUnits[0].Name
+		}
+	}
+}
+
+// resolvePersistenceUnits links every persistence unit to the Liberty dataSource whose
+// jndiName (or id) matches the unit's data source name. Names given in the component
+// environment (java:comp/env/jdbc/x) match jdbc/x. Only unique matches are linked.
+func resolvePersistenceUnits(services []model.Service, srv model.LibertyServer) {
+	for i := range services {
+		for j := range services[i].PersistenceUnits {
+			u := &services[i].PersistenceUnits[j]
+			name := u.DataSourceName()
+			if name == "" {
+				u.DataSourceEvidence = "no data source declared"
+				continue
+			}
+			lookup := strings.TrimPrefix(name, "java:comp/env/")
+
+			var match []model.DataSource
+			for _, ds := range srv.DataSources {
+				if ds.JNDIName == lookup || ds.ID == lookup {
+					match = append(match, ds)
+				}
+			}
+			switch len(match) {
+			case 0:
+				u.DataSourceEvidence = fmt.Sprintf("%s matches no dataSource in server.xml", name)
+			case 1:
+				u.DataSource = match[0].ID
+				u.DataSourceEvidence = fmt.Sprintf("%s matches dataSource %s (%s)", name, match[0].ID, srv.ServerXML)
+			default:
+				u.DataSourceEvidence = fmt.Sprintf("%s matches %d dataSources in server.xml", name, len(match))
+			}
+		}
+	}
+}
+
+// resolveNamedQuery sets the entities or tables of a named query lookup from the unique
+// declaration of that name and returns the declaration evidence. Lookups of undeclared or
+// ambiguous names keep the entity of their result class, if any.
+func resolveNamedQuery(da *model.DataAccess, queries []model.NamedQuery) string {
+	var match []model.NamedQuery
+	for _, q := range queries {
+		if q.Name == da.Query {
+			match = append(match, q)
+		}
+	}
+	switch len(match) {
+	case 0:
+		return "named query not declared in the service"
+	case 1:
+	default:
+		return fmt.Sprintf("named query declared %d times", len(match))
+	}
+	if len(match[0].Entities) > 0 {
+		da.Entities = match[0].Entities
+	}
+	da.Tables = match[0].Tables
+	return fmt.Sprintf("@NamedQuery on %s (%s): %s", match[0].Entity, match[0].Evidence, match[0].Query)
+}
+
+// persistenceNode builds the flow step of a database access.
+func (p *methodFlowParser) persistenceNode(da model.DataAccess, lineNum int) model.FlowNode {
+	step := model.FlowStep{
+		Kind:        model.FlowStepPersistence,
+		FromMethod:  p.fullHandler,
+		Confidence:  model.ConfidenceHigh,
+		Evidence:    fmt.Sprintf("%s:%d", p.sourceFile, lineNum),
+		Description: dataAccessLabel(da),
+	}
+	if da.Operation == "createNamedQuery" {
+		step.ResolutionEvidence = resolveNamedQuery(&da, p.service.NamedQueries)
+	}
+	step.Entities, step.Tables = da.Entities, da.Tables
+	if len(step.Entities) == 0 && len(step.Tables) == 0 {
+		step.Confidence = model.ConfidenceMedium
+	}
+	return model.FlowNode{Step: step}
+}
+
+// dataAccessLabel describes a database access ("JPA find: Order", "JDBC prepareStatement: SELECT ...").
+func dataAccessLabel(da model.DataAccess) string {
+	if da.Kind == model.DataAccessJDBC {
+		return labelWithDetail("JDBC "+da.Operation, da.Query)
+	}
+	switch da.Operation {
+	case "createQuery":
+		return labelWithDetail("JPA query", da.Query)
+	case "createNamedQuery":
+		return labelWithDetail("JPA named query", da.Query)
+	case "createNativeQuery":
+		return labelWithDetail("Native query", da.Query)
+	}
+	return labelWithDetail("JPA "+da.Operation, strings.Join(da.Entities, ", "))
+}
+
+// labelWithDetail appends the detail to the label when it is known.
+func labelWithDetail(label, detail string) string {
+	if detail == "" {
+		return label
+	}
+	return label + ": " + detail
+}
@@ -0,0 +1,111 @@
+package app
+
+import (
+	"jz/model"
+	"net/url"
+	"sort"
+)
+
+// attachSOAP assigns JAX-WS endpoints, WSDL addresses and client calls to the service.
+// A WSDL is linked to an endpoint only when its service QName matches exactly.
+func attachSOAP(svc *model.Service, a sourceArtifacts) {
+	for _, ep := range a.soapEndpoints {
+		if !underRoot(ep.SourceFile, svc.RootPath) {
+			continue
+		}
+
+		for _, def := range a.wsdls {
+			if !underRoot(def.Path, svc.RootPath) || def.TargetNamespace != ep.Namespace {
+				continue
+			}
+			for _, ws := range def.Services {
+				if ws.Name != ep.ServiceName {
+					continue
+				}
+				ep.WSDLFile = def.Path
+				for _, p := range ws.Ports {
+					if p.Address != "" && (ep.PortName == "" || ep.PortName == p.Name) {
+						ep.Addresses = append(ep.Addresses, p.Address)
+					}
+				}
+			}
+			if ep.WSDLFile != "" {
+				break
+			}
+		}
+
+		svc.SOAPEndpoints = append(svc.SOAPEndpoints, ep)
+	}
+
+	for _, call := range a.soapCalls {
+		if !underRoot(call.SourceFile, svc.RootPath) {
+			continue
+		}
+		call.FromService = svc.Name
+		svc.SOAPCalls = append(svc.SOAPCalls, call)
+	}
+}
+
+// linkSOAPCalls resolves SOAP client calls to known endpoints.
+// Resolution order: service QName, exact endpoint address, endpoint address path.
+// A link is only recorded for a unique match.
+func linkSOAPCalls(services []model.Service) {
+	byQName := make(map[string][]targetResource)
+	byAddress := make(map[string][]targetResource)
+	byPath := make(map[string][]targetResource)
+
+	for _, svc := range services {
+		for _, ep := range svc.SOAPEndpoints {
+			t := targetResource{serviceName: svc.Name, resourceName: ep.ServiceName}
+			byQName[ep.QName()] = append(byQName[ep.QName()], t)
+			for _, addr := range ep.Addresses {
+				byAddress[addr] = append(byAddress[addr], t)
+				if p := addressPath(addr); p != "" {
+					byPath[p] = append(byPath[p], t)
+				}
+			}
+		}
+	}
+
+	for i := range services {
+		for j := range services[i].SOAPCalls {
+			call := &services[i].SOAPCalls[j]
+			call.ResolutionScope = model.ResolutionUnresolved
+
+			var target []targetResource
+			switch {
+			case call.ServiceQName != "" && len(byQName[call.ServiceQName]) == 1:
+				target = byQName[call.ServiceQName]
+				call.ResolutionEvidence = "service QName match"
+			case call.Address != "" && len(byAddress[call.Address]) == 1:
+				target = byAddress[call.Address]
+				call.ResolutionEvidence = "endpoint address match"
+			case call.Address != "" && addressPath(call.Address) != "" && len(byPath[addressPath(call.Address)]) == 1:
+				target = byPath[addressPath(call.Address)]
+				call.ResolutionEvidence = "endpoint address path match"
+			}
+
+			if len(target) == 1 {
+				call.TargetService = target[0].serviceName
+				call.TargetEndpoint = target[0].resourceName
+				call.ResolutionScope = model.ResolutionCrossService
+				if call.TargetService == call.FromService {
+					call.ResolutionScope = model.ResolutionSameService
+				}
+			}
+		}
+
+		sort.Slice(services[i].SOAPCalls, func(a, b int) bool {
+			return services[i].SOAPCalls[a].Evidence < services[i].SOAPCalls[b].Evidence
+		})
+	}
+}
+
+// addressPath returns the host-independent path of an endpoint address.
+func addressPath(addr string) string {
+	u, err := url.Parse(addr)
+	if err != nil || u.Path == "" || u.Path == "/" {
+		return ""
+	}
+	return normalizePath(u.Path)
+}
@@ -0,0 +1,260 @@
+package app
+
+import (
+	"jz/model"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// specOperationMethods are the HTTP methods compared with an OpenAPI document.
+var specOperationMethods = []string{"GET", "PUT", "POST", "DELETE", "OPTIONS", "HEAD", "PATCH", "TRACE"}
+
+// specPath returns the path of a JAX-RS method as an OpenAPI template.
+func specPath(m model.RESTMethod) string {
+	template, _, _ := PathTemplate(m.FullPath)
+	return template
+}
+
+// CheckOpenAPI compares the JAX-RS operations of a service with the operations of an
+// OpenAPI document. Paths are matched by template shape, so {id} and {itemId} match.
+// Spec paths are tried as declared and prefixed by the server base path, with the
+// service context root removed; the prefix that matches the most operations is kept.
+func CheckOpenAPI(svc model.Service, spec model.APISpec) model.SpecCheck {
+	check := model.SpecCheck{
+		Spec:           spec.Source,
+		Service:        svc.Name,
+		SpecOperations: len(spec.Operations),
+	}
+
+	var code []model.RESTMethod
+	for _, res := range svc.RESTResources {
+		if res.Kind != model.EntryPointJAXRS {
+			continue
+		}
+		for _, m := range res.Methods {
+			if slices.Contains(specOperationMethods, m.HTTPMethod) {
+				code = append(code, m)
+			}
+		}
+	}
+	check.CodeOperations = len(code)
+
+	codeKeys := make(map[string]bool)
+	for _, c := range code {
+		codeKeys[c.HTTPMethod+" "+templateShape(c.FullPath)] = true
+	}
+	check.SpecPrefix = bestSpecPrefix(spec, svc.Application.ContextRoot, codeKeys)
+
+	// Index both sides by template shape
+	codeByShape := make(map[string][]model.RESTMethod)
+	var codeShapes []string
+	for _, c := range code {
+		shape := templateShape(c.FullPath)
+		if _, ok := codeByShape[shape]; !ok {
+			codeShapes = append(codeShapes, shape)
+		}
+		codeByShape[shape] = append(codeByShape[shape], c)
+	}
+	specByShape := make(map[string][]model.SpecOperation)
+	var specShapes []string
+	for _, op := range spec.Operations {
+		shape := templateShape(check.SpecPrefix + op.Path)
+		if _, ok := specByShape[shape]; !ok {
+			specShapes = append(specShapes, shape)
+		}
+		specByShape[shape] = append(specByShape[shape], op)
+	}
+
+	for _, shape := range codeShapes {
+		codeOps := codeByShape[shape]
+		specOps, ok := specByShape[shape]
+		if !ok {
+			for _, c := range codeOps {
+				check.Drifts = append(check.Drifts, model.SpecDrift{
+					Kind:         model.DriftMissingFromSpec,
+					HTTPMethod:   c.HTTPMethod,
+					Path:         specPath(c),
+					Handler:      c.Handler,
+					CodeEvidence: c.SourceFile,
+				})
+			}
+			continue
+		}
+
+		var codeOnly []model.RESTMethod
+		matchedSpec := make(map[int]bool)
+		for _, c := range codeOps {
+			idx := -1
+			for i, op := range specOps {
+				if op.HTTPMethod == c.HTTPMethod && !matchedSpec[i] {
+					idx = i
+					break
+				}
+			}
+			if idx < 0 {
+				codeOnly = append(codeOnly, c)
+				continue
+			}
+			matchedSpec[idx] = true
+			check.Matched++
+			check.Drifts = append(check.Drifts, mediaTypeDrifts(c, specOps[idx])...)
+		}
+		var specOnly []model.SpecOperation
+		for i, op := range specOps {
+			if !matchedSpec[i] {
+				specOnly = append(specOnly, op)
+			}
+		}
+		if len(codeOnly) > 0 || len(specOnly) > 0 {
+			check.Drifts = append(check.Drifts, methodMismatch(specPath(codeOps[0]), codeOnly, specOnly))
+		}
+	}
+
+	for _, shape := range specShapes {
+		if _, ok := codeByShape[shape]; ok {
+			continue
+		}
+		for _, op := range specByShape[shape] {
+			check.Drifts = append(check.Drifts, model.SpecDrift{
+				Kind:         model.DriftMissingFromCode,
+				HTTPMethod:   op.HTTPMethod,
+				Path:         check.SpecPrefix + op.Path,
+				SpecEvidence: op.Evidence,
+			})
+		}
+	}
+
+	sort.SliceStable(check.Drifts, func(i, j int) bool {
+		a, b := check.Drifts[i], check.Drifts[j]
+		if a.Kind != b.Kind {
+			return a.Kind < b.Kind
+		}
+		if a.Path != b.Path {
+			return a.Path < b.Path
+		}
+		return a.HTTPMethod < b.HTTPMethod
+	})
+	return check
+}
+
+// bestSpecPrefix returns the prefix to apply to spec paths: none, the server base path,
+// or the server base path without the context root. Ties keep the earlier candidate.
+func bestSpecPrefix(spec model.APISpec, contextRoot string, codeKeys map[string]bool) string {
+	candidates := []string{""}
+	if base := spec.BasePath; base != "" {
+		root := "/" + strings.Trim(contextRoot, "/")
+		if root != "/" && (base == root || strings.HasPrefix(base, root+"/")) {
+			candidates = append(candidates, strings.TrimPrefix(base, root))
+		}
+		candidates = append(candidates, base)
+	}
+
+	best, bestCount := "", -1
+	for _, prefix := range candidates {
+		count := 0
+		for _, op := range spec.Operations {
+			if codeKeys[op.HTTPMethod+" "+templateShape(prefix+op.Path)] {
+				count++
+			}
+		}
+		if count > bestCount {
+			best, bestCount = prefix, count
+		}
+	}
+	return best
+}
+
+// methodMismatch reports the methods declared for a path on one side only.
+func methodMismatch(path string, codeOnly []model.RESTMethod, specOnly []model.SpecOperation) model.SpecDrift {
+	d := model.SpecDrift{Kind: model.DriftMethodMismatch, Path: path}
+	if len(codeOnly) > 0 {
+		var methods, handlers []string
+		for _, c := range codeOnly {
+			methods = append(methods, c.HTTPMethod)
+			handlers = append(handlers, c.Handler)
+		}
+		d.Handler = strings.Join(handlers, ", ")
+		d.CodeEvidence = codeOnly[0].SourceFile
+		d.Details = append(d.Details, "only in code: "+strings.Join(methods, ", "))
+	}
+	if len(specOnly) > 0 {
+		var methods []string
+		for _, op := range specOnly {
+			methods = append(methods, op.HTTPMethod)
+		}
+		d.SpecEvidence = specOnly[0].Evidence
+		d.Details = append(d.Details, "only in spec: "+strings.Join(methods, ", "))
+	}
+	return d
+}
+
+// mediaTypeDrifts compares the media types of a matched operation. A side that declares
+// no media types is not compared, nor is code whose media types are not all resolved;
+// request bodies are only compared for POST, PUT and PATCH.
+func mediaTypeDrifts(c model.RESTMethod, op model.SpecOperation) []model.SpecDrift {
+	var drifts []model.SpecDrift
+	compare := func(kind model.SpecDriftKind, code, spec []string) {
+		codeOnly, specOnly := mediaTypeDifference(code, spec), mediaTypeDifference(spec, code)
+		if len(code) == 0 || len(spec) == 0 || !model.MediaTypesKnown(code) || (len(codeOnly) == 0 && len(specOnly) == 0) {
+			return
+		}
+		d := model.SpecDrift{
+			Kind:         kind,
+			HTTPMethod:   c.HTTPMethod,
+			Path:         specPath(c),
+			Handler:      c.Handler,
+			CodeEvidence: c.SourceFile,
+			SpecEvidence: op.Evidence,
+		}
+		if len(codeOnly) > 0 {
+			d.Details = append(d.Details, "only in code: "+strings.Join(codeOnly, ", "))
+		}
+		if len(specOnly) > 0 {
+			d.Details = append(d.Details, "only in spec: "+strings.Join(specOnly, ", "))
+		}
+		drifts = append(drifts, d)
+	}
+	if c.HTTPMethod == "POST" || c.HTTPMethod == "PUT" || c.HTTPMethod == "PATCH" {
+		compare(model.DriftConsumesMismatch, c.Consumes, op.Consumes)
+	}
+	compare(model.DriftProducesMismatch, c.Produces, op.Produces)
+	return drifts
+}
+
+// mediaTypeDifference returns the media types of a that no media type of b covers.
+// Wildcards (*/*, text/*) cover the types they name; parameters (;charset=...) are ignored.
+func mediaTypeDifference(a, b []string) []string {
+	var out []string
+	for _, x := range a {
+		covered := false
+		for _, y := range b {
+			if mediaTypesMatch(x, y) {
+				covered = true
+				break
+			}
+		}
+		if !covered {
+			out = append(out, x)
+		}
+	}
+	return out
+}
+
+func mediaTypesMatch(a, b string) bool {
+	a, b = normalizeMediaType(a), normalizeMediaType(b)
+	if a == b || a == "*/*" || b == "*/*" {
+		return true
+	}
+	at, _, _ := strings.Cut(a, "/")
+	bt, _, _ := strings.Cut(b, "/")
+	return at == bt && (a == at+"/*" || b == bt+"/*")
+}
+
+// normalizeMediaType lowercases a media type and drops its parameters.
+func normalizeMediaType(mt string) string {
+	if i := strings.Index(mt, ";"); i >= 0 {
+		mt = mt[:i]
+	}
+	return strings.ToLower(strings.TrimSpace(mt))
+}
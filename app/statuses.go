@@ -0,0 +1,64 @@
+package app
+
+import (
+	"jz/model"
+	"sort"
+)
+
+// statusFlowDepth is the call expansion depth used for the status inventory
+// (the default of jz flow extract).
+const statusFlowDepth = 3
+
+// inventoryResponseStatuses records on every REST method the HTTP statuses its expanded flow
+// can produce: statuses set through Response builders and statuses of uncaught exceptions.
+func inventoryResponseStatuses(services []model.Service) {
+	for i := range services {
+		svc := &services[i]
+		if svc.RootPath == "" || len(svc.RESTResources) == 0 {
+			continue
+		}
+		index := buildTypeIndex(svc)
+
+		for r := range svc.RESTResources {
+			res := &svc.RESTResources[r]
+			for m := range res.Methods {
+				method := &res.Methods[m]
+				flow, ok := extractMethodFlow(svc, res, *method, index, statusFlowDepth, nil)
+				if !ok {
+					continue
+				}
+				method.Statuses = flowStatuses(flow)
+			}
+		}
+	}
+}
+
+// flowStatuses merges the response and exception statuses of a flow, sorted by status and
+// evidence. A status produced twice by the same line is listed once.
+func flowStatuses(flow model.ExecutionFlow) []model.ResponseStatus {
+	statuses := append([]model.ResponseStatus(nil), flow.ResponseStatuses...)
+	for _, es := range flow.ErrorStatuses {
+		statuses = append(statuses, model.ResponseStatus{
+			Status:   es.Status,
+			Source:   model.StatusSourceException,
+			Detail:   es.Mapping,
+			Evidence: es.Evidence,
+		})
+	}
+
+	sort.SliceStable(statuses, func(a, b int) bool {
+		if statuses[a].Status != statuses[b].Status {
+			return statuses[a].Status < statuses[b].Status
+		}
+		return statuses[a].Evidence < statuses[b].Evidence
+	})
+
+	var unique []model.ResponseStatus
+	for _, s := range statuses {
+		if n := len(unique); n > 0 && unique[n-1].Status == s.Status && unique[n-1].Evidence == s.Evidence {
+			continue
+		}
+		unique = append(unique, s)
+	}
+	return unique
+}
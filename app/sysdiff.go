@@ -0,0 +1,357 @@
+package app
+
+import (
+	"fmt"
+	"jz/model"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// DiffSystems compares two complete analyses: services, REST endpoints, DS components,
+// system graph edges, Liberty features and resolved REST call edges.
+func DiffSystems(servicesA []model.Service, graphA model.SystemGraph, servicesB []model.Service, graphB model.SystemGraph) model.SystemDiff {
+	var d model.SystemDiff
+
+	// 1. Services
+	namesA, namesB := serviceNames(servicesA), serviceNames(servicesB)
+	for _, name := range namesA {
+		if !slices.Contains(namesB, name) {
+			d.Services = append(d.Services, model.ItemChange{Kind: model.ChangeRemoved, Name: name})
+		}
+	}
+	for _, name := range namesB {
+		if !slices.Contains(namesA, name) {
+			d.Services = append(d.Services, model.ItemChange{Kind: model.ChangeAdded, Name: name})
+		}
+	}
+
+	// 2. Endpoints
+	d.Endpoints = diffEndpoints(systemEndpoints(servicesA), systemEndpoints(servicesB))
+	for i := range d.Endpoints {
+		classifyEndpointChange(&d.Endpoints[i])
+	}
+
+	// 3. DS components
+	d.Components = diffComponents(servicesA, servicesB)
+
+	// 4. System graph edges
+	d.Dependencies = diffEdges(graphEdges(graphA), graphEdges(graphB))
+
+	// 5. Liberty features
+	featuresA, featuresB := systemFeatures(servicesA), systemFeatures(servicesB)
+	for _, f := range featuresA {
+		if !slices.Contains(featuresB, f) {
+			d.Features = append(d.Features, model.ItemChange{Kind: model.ChangeRemoved, Name: f})
+		}
+	}
+	for _, f := range featuresB {
+		if !slices.Contains(featuresA, f) {
+			d.Features = append(d.Features, model.ItemChange{Kind: model.ChangeAdded, Name: f})
+		}
+	}
+
+	// 6. Resolved REST call edges
+	d.Calls = diffEdges(callEdges(servicesA), callEdges(servicesB))
+
+	return d
+}
+
+func serviceNames(services []model.Service) []string {
+	var names []string
+	for _, s := range services {
+		names = append(names, s.Name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// systemEndpoints flattens the REST methods of all services.
+func systemEndpoints(services []model.Service) []model.Endpoint {
+	var eps []model.Endpoint
+	for _, svc := range services {
+		for _, res := range svc.RESTResources {
+			for _, m := range res.Methods {
+				eps = append(eps, model.Endpoint{
+					Service:    svc.Name,
+					Resource:   res.Name,
+					HTTPMethod: m.HTTPMethod,
+					Path:       m.FullPath,
+					Handler:    m.Handler,
+					Consumes:   m.Consumes,
+					Produces:   m.Produces,
+					Auth:       m.Auth,
+					Roles:      m.Roles,
+					WebRoles:   m.WebRoles,
+					WebSecured: m.WebConstrained,
+				})
+			}
+		}
+	}
+	sort.SliceStable(eps, func(a, b int) bool {
+		if eps[a].Service != eps[b].Service {
+			return eps[a].Service < eps[b].Service
+		}
+		if eps[a].Path != eps[b].Path {
+			return eps[a].Path < eps[b].Path
+		}
+		return eps[a].HTTPMethod < eps[b].HTTPMethod
+	})
+	return eps
+}
+
+// diffEndpoints matches endpoints by service, HTTP method and path, then pairs the remaining
+// removed and added endpoints of a service that share a unique handler (a changed path or
+// HTTP method).
+func diffEndpoints(before, after []model.Endpoint) []model.EndpointChange {
+	key := func(e model.Endpoint) string { return e.Service + "|" + e.HTTPMethod + "|" + e.Path }
+	afterByKey := make(map[string]int)
+	for i, e := range after {
+		afterByKey[key(e)] = i
+	}
+	matched := make([]bool, len(after))
+
+	var changes []model.EndpointChange
+	var removed []model.Endpoint
+	for _, b := range before {
+		i, ok := afterByKey[key(b)]
+		if !ok || matched[i] {
+			removed = append(removed, b)
+			continue
+		}
+		matched[i] = true
+		if details := endpointDetails(b, after[i]); len(details) > 0 {
+			bb, aa := b, after[i]
+			changes = append(changes, model.EndpointChange{Kind: model.ChangeChanged, Before: &bb, After: &aa, Details: details})
+		}
+	}
+	var added []model.Endpoint
+	for i, a := range after {
+		if !matched[i] {
+			added = append(added, a)
+		}
+	}
+
+	handlerCount := func(eps []model.Endpoint, e model.Endpoint) int {
+		n := 0
+		for _, x := range eps {
+			if x.Service == e.Service && x.Handler == e.Handler {
+				n++
+			}
+		}
+		return n
+	}
+	paired := make([]bool, len(added))
+	for _, b := range removed {
+		bb := b
+		change := model.EndpointChange{Kind: model.ChangeRemoved, Before: &bb}
+		if handlerCount(removed, b) == 1 {
+			for i, a := range added {
+				if !paired[i] && a.Service == b.Service && a.Handler == b.Handler && handlerCount(added, a) == 1 {
+					paired[i] = true
+					aa := a
+					change = model.EndpointChange{Kind: model.ChangeChanged, Before: &bb, After: &aa, Details: endpointDetails(b, a)}
+					break
+				}
+			}
+		}
+		changes = append(changes, change)
+	}
+	for i, a := range added {
+		if !paired[i] {
+			aa := a
+			changes = append(changes, model.EndpointChange{Kind: model.ChangeAdded, After: &aa})
+		}
+	}
+
+	sort.SliceStable(changes, func(i, j int) bool {
+		return endpointSortKey(changes[i]) < endpointSortKey(changes[j])
+	})
+	return changes
+}
+
+func endpointSortKey(c model.EndpointChange) string {
+	e := c.After
+	if e == nil {
+		e = c.Before
+	}
+	return e.Service + "|" + e.Path + "|" + e.HTTPMethod
+}
+
+// endpointDetails describes the contract differences between two versions of an endpoint.
+func endpointDetails(b, a model.Endpoint) []string {
+	var details []string
+	if b.HTTPMethod != a.HTTPMethod {
+		details = append(details, fmt.Sprintf("HTTP method: %s -> %s", b.HTTPMethod, a.HTTPMethod))
+	}
+	if b.Path != a.Path {
+		details = append(details, fmt.Sprintf("path: %s -> %s", b.Path, a.Path))
+	}
+	if b.Handler != a.Handler {
+		details = append(details, fmt.Sprintf("handler: %s -> %s", b.Handler, a.Handler))
+	}
+	details = append(details, listDetails("consumes", b.Consumes, a.Consumes)...)
+	details = append(details, listDetails("produces", b.Produces, a.Produces)...)
+	details = append(details, listDetails("auth", b.Auth, a.Auth)...)
+	details = append(details, listDetails("roles", b.Roles, a.Roles)...)
+	if b.WebSecured != a.WebSecured {
+		details = append(details, fmt.Sprintf("web.xml security-constraint: %t -> %t", b.WebSecured, a.WebSecured))
+	}
+	details = append(details, listDetails("web.xml roles", b.WebRoles, a.WebRoles)...)
+	return details
+}
+
+// listDetails describes the values added to and removed from a list.
+func listDetails(label string, before, after []string) []string {
+	var details []string
+	for _, v := range before {
+		if !slices.Contains(after, v) {
+			details = append(details, fmt.Sprintf("%s removed: %s", label, v))
+		}
+	}
+	for _, v := range after {
+		if !slices.Contains(before, v) {
+			details = append(details, fmt.Sprintf("%s added: %s", label, v))
+		}
+	}
+	return details
+}
+
+// diffComponents compares the DS components of the services present in both analyses by
+// name, including their provided and referenced interfaces.
+func diffComponents(servicesA, servicesB []model.Service) []model.ItemChange {
+	componentsOf := func(services []model.Service) map[string]model.DSComponent {
+		m := make(map[string]model.DSComponent)
+		for _, svc := range services {
+			for _, c := range svc.Components {
+				m[svc.Name+"|"+c.Name] = c
+			}
+		}
+		return m
+	}
+	compsA, compsB := componentsOf(servicesA), componentsOf(servicesB)
+
+	var keys []string
+	for k := range compsA {
+		keys = append(keys, k)
+	}
+	for k := range compsB {
+		if _, ok := compsA[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+
+	var changes []model.ItemChange
+	for _, k := range keys {
+		service, name, _ := strings.Cut(k, "|")
+		a, inA := compsA[k]
+		b, inB := compsB[k]
+		switch {
+		case !inB:
+			changes = append(changes, model.ItemChange{Kind: model.ChangeRemoved, Service: service, Name: name})
+		case !inA:
+			changes = append(changes, model.ItemChange{Kind: model.ChangeAdded, Service: service, Name: name})
+		default:
+			var details []string
+			if a.ImplementationClass != b.ImplementationClass {
+				details = append(details, fmt.Sprintf("implementation: %s -> %s", a.ImplementationClass, b.ImplementationClass))
+			}
+			details = append(details, listDetails("provides", a.ProvidedInterfaces, b.ProvidedInterfaces)...)
+			details = append(details, listDetails("references", a.ReferencedInterfaces, b.ReferencedInterfaces)...)
+			if len(details) > 0 {
+				changes = append(changes, model.ItemChange{Kind: model.ChangeChanged, Service: service, Name: name, Details: details})
+			}
+		}
+	}
+	return changes
+}
+
+// graphEdges returns the service dependency and message edges of a system graph.
+func graphEdges(g model.SystemGraph) []model.EdgeChange {
+	var edges []model.EdgeChange
+	for _, dep := range g.Dependencies {
+		edges = append(edges, model.EdgeChange{From: dep.FromService, To: dep.ToService, Label: dep.Interface, Type: dep.Kind})
+	}
+	for _, e := range g.MessageEdges {
+		channel := e.Protocol + ":" + e.Channel
+		if e.Role == model.MessageProducer {
+			edges = append(edges, model.EdgeChange{From: e.Service, To: channel, Label: "produces", Type: "message"})
+		} else {
+			edges = append(edges, model.EdgeChange{From: channel, To: e.Service, Label: "consumes", Type: "message"})
+		}
+	}
+	return edges
+}
+
+// callEdges returns the resolved REST calls of all services as resource-to-resource edges,
+// or resource-to-system edges for calls to external systems.
+func callEdges(services []model.Service) []model.EdgeChange {
+	var edges []model.EdgeChange
+	for _, svc := range services {
+		for _, call := range svc.RESTCalls {
+			to := call.TargetService + "/" + call.TargetResource
+			switch {
+			case call.TargetResource != "":
+			case call.TargetSystem != "":
+				to = "external:" + call.TargetSystem
+			default:
+				continue
+			}
+			edges = append(edges, model.EdgeChange{
+				From:  svc.Name + "/" + call.FromResource,
+				To:    to,
+				Label: strings.TrimSpace(call.HTTPMethod + " " + call.TargetPath),
+				Type:  "rest",
+			})
+		}
+	}
+	return edges
+}
+
+// diffEdges returns the edges only in before (removed) and only in after (added), sorted.
+func diffEdges(before, after []model.EdgeChange) []model.EdgeChange {
+	key := func(e model.EdgeChange) string { return e.Type + "|" + e.From + "|" + e.To + "|" + e.Label }
+	inBefore, inAfter := make(map[string]bool), make(map[string]bool)
+	for _, e := range before {
+		inBefore[key(e)] = true
+	}
+	for _, e := range after {
+		inAfter[key(e)] = true
+	}
+
+	var changes []model.EdgeChange
+	seen := make(map[string]bool)
+	for _, e := range before {
+		if k := key(e); !inAfter[k] && !seen[k] {
+			seen[k] = true
+			e.Kind = model.ChangeRemoved
+			changes = append(changes, e)
+		}
+	}
+	for _, e := range after {
+		if k := key(e); !inBefore[k] && !seen[k] {
+			seen[k] = true
+			e.Kind = model.ChangeAdded
+			changes = append(changes, e)
+		}
+	}
+	sort.SliceStable(changes, func(i, j int) bool {
+		return key(changes[i]) < key(changes[j])
+	})
+	return changes
+}
+
+// systemFeatures returns the Liberty features enabled for any service, sorted.
+func systemFeatures(services []model.Service) []string {
+	var features []string
+	for _, svc := range services {
+		for _, f := range svc.Features {
+			if !slices.Contains(features, f) {
+				features = append(features, f)
+			}
+		}
+	}
+	sort.Strings(features)
+	return features
+}
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"fmt"
+	"jz/app"
+	"jz/report"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+// exitDrift is the exit status of jz check when the code and the checked document differ
+// (1 is used for errors).
+const exitDrift = 2
+
+var (
+	checkSpec    string
+	checkService string
+	checkFormat  string
+	checkOutput  string
+)
+
+var checkCmd = &cobra.Command{
+	Use:   "check",
+	Short: "Check the code against external documents",
+}
+
+var checkOpenAPICmd = &cobra.Command{
+	Use:   "openapi --spec <file> <service-path>",
+	Short: "Report drift between the JAX-RS operations and an OpenAPI 3 spec",
+	Long: `jz check openapi compares the JAX-RS operations of a service with the paths and
+operations of an OpenAPI 3 document (YAML or JSON): operations missing from the spec,
+operations missing from the code, method mismatches on shared paths, and request or
+response media type mismatches. It exits with status 2 when drift is found.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		if checkFormat != "markdown" && checkFormat != "json" {
+			fmt.Fprintf(os.Stderr, "Error: invalid format '%s'\n", checkFormat)
+			os.Exit(1)
+		}
+		spec, err := app.LoadOpenAPISpec(checkSpec)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		services, sysGraph, _ := analyze(args[0])
+		services, _, err = filterData(services, sysGraph, checkService)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		switch {
+		case len(services) == 0:
+			fmt.Fprintln(os.Stderr, "Error: no services detected")
+			os.Exit(1)
+		case len(services) > 1:
+			fmt.Fprintln(os.Stderr, "Error: several services found; use --service to pick the one the spec describes")
+			os.Exit(1)
+		}
+
+		check := app.CheckOpenAPI(services[0], spec)
+		output := report.GenerateSpecCheckMarkdown(check)
+		if checkFormat == "json" {
+			output = marshalJSON(check)
+		}
+		if err := writeOutput(output, checkOutput); err != nil {
+			fmt.Fprintf(os.Stderr, "Error writing output: %v\n", err)
+			os.Exit(1)
+		}
+
+		if n := len(check.Drifts); n > 0 {
+			fmt.Fprintf(os.Stderr, "%d difference(s) between the code and %s\n", n, checkSpec)
+			os.Exit(exitDrift)
+		}
+	},
+}
+
+func init() {
+	checkOpenAPICmd.Flags().StringVar(&checkSpec, "spec", "", "OpenAPI 3 document to check (YAML or JSON)")
+	checkOpenAPICmd.Flags().StringVar(&checkService, "service", "", "Service the spec describes, when the path holds several")
+	checkOpenAPICmd.Flags().StringVar(&checkFormat, "format", "markdown", "Output format: markdown|json")
+	checkOpenAPICmd.Flags().StringVar(&checkOutput, "output", "", "Write output to file")
+	checkOpenAPICmd.MarkFlagRequired("spec")
+	checkCmd.AddCommand(checkOpenAPICmd)
+	rootCmd.AddCommand(checkCmd)
+}
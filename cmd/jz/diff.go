@@ -0,0 +1,74 @@
+package main
+
+import (
+	"fmt"
+	"jz/app"
+	"jz/report"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+// exitBreaking is the exit status of jz diff when breaking API changes are detected
+// (1 is used for errors).
+const exitBreaking = 2
+
+var (
+	diffFormat  string
+	diffOutput  string
+	diffGitBase string
+	diffGitHead string
+)
+
+var diffCmd = &cobra.Command{
+	Use:   "diff <pathA> <pathB> | --git-base <rev> [--git-head <rev>] <repo>",
+	Short: "Compare the complete analyses of two code versions",
+	Long: `jz diff compares two code versions: services, REST endpoints, DS components,
+system graph edges, Liberty features and resolved REST call edges.
+Endpoint changes are classified as breaking or non-breaking; jz diff exits with
+status 2 when breaking changes exist.`,
+	Args: cobra.RangeArgs(1, 2),
+	Run: func(cmd *cobra.Command, args []string) {
+		sideA, sideB, err := diffInputs(args, diffGitBase, diffGitHead)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		servicesA, graphA, _ := sideA.analyze()
+		servicesB, graphB, _ := sideB.analyze()
+		d := app.DiffSystems(servicesA, graphA, servicesB, graphB)
+
+		var output string
+		switch diffFormat {
+		case "markdown":
+			output = report.GenerateSystemDiffMarkdown(d, sideA.label, sideB.label)
+		case "json":
+			output = marshalJSON(d)
+		case "mermaid":
+			output = report.GenerateSystemDiffMermaid(d, graphA, graphB)
+		default:
+			fmt.Fprintf(os.Stderr, "Error: invalid format '%s'\n", diffFormat)
+			os.Exit(1)
+		}
+
+		if err := writeOutput(output, diffOutput); err != nil {
+			fmt.Fprintf(os.Stderr, "Error writing output: %v\n", err)
+			os.Exit(1)
+		}
+
+		// Release gates fail on breaking API changes
+		if n := d.BreakingChanges(); n > 0 {
+			fmt.Fprintf(os.Stderr, "%d breaking API change(s) detected\n", n)
+			os.Exit(exitBreaking)
+		}
+	},
+}
+
+func init() {
+	diffCmd.Flags().StringVar(&diffFormat, "format", "markdown", "Output format: markdown|json|mermaid")
+	diffCmd.Flags().StringVar(&diffOutput, "output", "", "Write output to file")
+	diffCmd.Flags().StringVar(&diffGitBase, "git-base", "", "Compare this git revision of <repo> (read from the local object store)")
+	diffCmd.Flags().StringVar(&diffGitHead, "git-head", "", "Compare against this git revision instead of the work tree")
+	rootCmd.AddCommand(diffCmd)
+}
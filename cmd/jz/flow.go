@@ -3,8 +3,11 @@ package main
 import (
 	"fmt"
 	"jz/app"
+	"jz/model"
 	"jz/report"
 	"os"
+	"path/filepath"
+	"strings"
 
 	"github.com/spf13/cobra"
 )
@@ -17,6 +20,13 @@ var (
 	flowFormat   string
 	flowOutput   string
 	flowCompact  bool
+
+	flowFollowCalls bool
+	flowFollowDepth int
+	flowAll         bool
+	flowStrictIndex bool
+	flowGitBase     string
+	flowGitHead     string
 )
 
 var flowCmd = &cobra.Command{
@@ -35,34 +45,37 @@ var flowExtractCmd = &cobra.Command{
 }
 
 var flowDiffCmd = &cobra.Command{
-	Use:   "diff <pathA> <pathB>",
+	Use:   "diff <pathA> <pathB> | --git-base <rev> [--git-head <rev>] <repo>",
 	Short: "Compare execution flows between two code versions",
-	Args:  cobra.ExactArgs(2),
+	Args:  cobra.RangeArgs(1, 2),
 	Run: func(cmd *cobra.Command, args []string) {
 		if flowResource == "" {
 			fmt.Fprintln(os.Stderr, "Error: --resource is required")
 			os.Exit(1)
 		}
 
-		pathA := args[0]
-		pathB := args[1]
+		sideA, sideB, err := diffInputs(args, flowGitBase, flowGitHead)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
 
-		servicesA, _, _ := app.Analyze(pathA)
-		flowsA, err := app.ExtractFlow(servicesA, flowResource, flowMethod, flowPath, flowMaxDepth)
+		servicesA, _, _ := sideA.analyze()
+		flowsA, err := app.ExtractFlow(servicesA, flowResource, flowMethod, flowPath, flowMaxDepth, followDepth())
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "Error analyzing pathA: %v\n", err)
 			os.Exit(1)
 		}
 
-		servicesB, _, _ := app.Analyze(pathB)
-		flowsB, err := app.ExtractFlow(servicesB, flowResource, flowMethod, flowPath, flowMaxDepth)
+		servicesB, _, _ := sideB.analyze()
+		flowsB, err := app.ExtractFlow(servicesB, flowResource, flowMethod, flowPath, flowMaxDepth, followDepth())
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "Error analyzing pathB: %v\n", err)
 			os.Exit(1)
 		}
 
-		diffs := app.DiffFlows(flowsA, flowsB)
-		output := report.GenerateFlowDiffMarkdown(diffs, flowResource)
+		diffs := app.DiffFlows(flowsA, flowsB, flowStrictIndex)
+		output := report.GenerateFlowDiffMarkdown(diffs, flowResource, flowStrictIndex)
 
 		if flowOutput != "" {
 			err := os.WriteFile(flowOutput, []byte(output), 0644)
@@ -78,14 +91,22 @@ var flowDiffCmd = &cobra.Command{
 }
 
 func runExtract(rootDir string) {
+	if flowAll {
+		if flowResource != "" {
+			fmt.Fprintln(os.Stderr, "Error: --resource cannot be combined with --all")
+			os.Exit(1)
+		}
+		runExtractAll(rootDir)
+		return
+	}
 	if flowResource == "" {
-		fmt.Fprintln(os.Stderr, "Error: --resource is required")
+		fmt.Fprintln(os.Stderr, "Error: --resource is required (or use --all)")
 		os.Exit(1)
 	}
 
-	services, _, _ := app.Analyze(rootDir)
+	services, _, _ := analyze(rootDir)
 
-	flows, err := app.ExtractFlow(services, flowResource, flowMethod, flowPath, flowMaxDepth)
+	flows, err := app.ExtractFlow(services, flowResource, flowMethod, flowPath, flowMaxDepth, followDepth())
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 		os.Exit(1)
@@ -101,6 +122,8 @@ func runExtract(rootDir string) {
 		md := report.GenerateFlowMarkdown(flows, flowResource, flowPath)
 		mmd := report.GenerateFlowMermaid(flows, flowResource, flowCompact)
 		output = md + "\n\n---\n\n" + mmd
+	case "json":
+		output = marshalJSON(flows)
 	default:
 		fmt.Fprintf(os.Stderr, "Error: invalid format '%s'\n", flowFormat)
 		os.Exit(1)
@@ -118,15 +141,97 @@ func runExtract(rootDir string) {
 	}
 }
 
+// runExtractAll extracts the flows of every resource. JSON output is a single catalog
+// (file or stdout); the other formats write one file per resource under the --output
+// directory, as <service>/<Resource>.md and .mmd.
+func runExtractAll(rootDir string) {
+	switch flowFormat {
+	case "markdown", "mermaid", "all", "json":
+	default:
+		fmt.Fprintf(os.Stderr, "Error: invalid format '%s'\n", flowFormat)
+		os.Exit(1)
+	}
+	if flowFormat != "json" && flowOutput == "" {
+		fmt.Fprintln(os.Stderr, "Error: --all requires --output <dir> (or --format json)")
+		os.Exit(1)
+	}
+
+	services, _, _ := analyze(rootDir)
+	catalog := app.ExtractAllFlows(services, flowMethod, flowPath, flowMaxDepth, followDepth())
+
+	if flowFormat == "json" {
+		output := marshalJSON(catalog)
+		if flowOutput == "" {
+			fmt.Println(output)
+			return
+		}
+		if err := os.WriteFile(flowOutput, []byte(output), 0644); err != nil {
+			fmt.Fprintf(os.Stderr, "Error writing to file: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("Flows of %d resource(s) written to %s\n", len(catalog), flowOutput)
+		return
+	}
+
+	files := 0
+	for _, rf := range catalog {
+		dir := filepath.Join(flowOutput, safeFileName(rf.Service))
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			fmt.Fprintf(os.Stderr, "Error creating directory: %v\n", err)
+			os.Exit(1)
+		}
+		for ext, output := range resourceFlowFiles(rf) {
+			path := filepath.Join(dir, safeFileName(rf.Resource)+ext)
+			if err := os.WriteFile(path, []byte(output), 0644); err != nil {
+				fmt.Fprintf(os.Stderr, "Error writing to file: %v\n", err)
+				os.Exit(1)
+			}
+			files++
+		}
+	}
+	fmt.Printf("Flows of %d resource(s) written to %s (%d files)\n", len(catalog), flowOutput, files)
+}
+
+// resourceFlowFiles renders the flows of a resource in the selected format, keyed by file extension.
+func resourceFlowFiles(rf model.ResourceFlows) map[string]string {
+	files := make(map[string]string)
+	if flowFormat == "markdown" || flowFormat == "all" {
+		files[".md"] = report.GenerateFlowMarkdown(rf.Flows, rf.QualifiedName(), flowPath)
+	}
+	if flowFormat == "mermaid" || flowFormat == "all" {
+		files[".mmd"] = report.GenerateFlowMermaid(rf.Flows, rf.QualifiedName(), flowCompact)
+	}
+	return files
+}
+
+// safeFileName replaces path separators in a service or resource name.
+func safeFileName(name string) string {
+	return strings.NewReplacer("/", "_", "\\", "_", ":", "_").Replace(name)
+}
+
+// followDepth returns the cross-service depth budget, or 0 unless --follow-calls is set.
+func followDepth() int {
+	if !flowFollowCalls {
+		return 0
+	}
+	return flowFollowDepth
+}
+
 func init() {
 	// Root flags for flow
-	flowCmd.PersistentFlags().StringVar(&flowResource, "resource", "", "REST resource class name (required)")
+	flowCmd.PersistentFlags().StringVar(&flowResource, "resource", "", "REST resource class name or service/Resource (required unless --all)")
 	flowCmd.PersistentFlags().StringVar(&flowMethod, "method", "", "Filter to a single HTTP method")
 	flowCmd.PersistentFlags().StringVar(&flowPath, "path", "", "Filter to a single REST path")
 	flowCmd.PersistentFlags().IntVar(&flowMaxDepth, "max-depth", 3, "Limit call expansion depth")
-	flowCmd.PersistentFlags().StringVar(&flowFormat, "format", "markdown", "Output format: markdown|mermaid|all")
+	flowCmd.PersistentFlags().StringVar(&flowFormat, "format", "markdown", "Output format: markdown|mermaid|all|json")
 	flowCmd.PersistentFlags().StringVar(&flowOutput, "output", "", "Output file path")
 	flowCmd.PersistentFlags().BoolVar(&flowCompact, "compact", false, "Enable visual-only guard chain compaction in Mermaid diagrams")
+	flowCmd.PersistentFlags().BoolVar(&flowFollowCalls, "follow-calls", false, "Continue flows into the handlers of resolved cross-service REST calls")
+	flowCmd.PersistentFlags().IntVar(&flowFollowDepth, "follow-depth", 2, "Limit the number of services followed with --follow-calls")
+	flowDiffCmd.Flags().StringVar(&flowGitBase, "git-base", "", "Compare this git revision of <repo> (read from the local object store)")
+	flowDiffCmd.Flags().StringVar(&flowGitHead, "git-head", "", "Compare against this git revision instead of the work tree")
+	flowDiffCmd.Flags().BoolVar(&flowStrictIndex, "strict-index", false, "Compare steps index by index instead of aligning them")
+	flowExtractCmd.Flags().BoolVar(&flowAll, "all", false, "Extract the flows of every resource of every service (requires --output <dir> unless --format json)")
 
 	// Allow "jz flow <path>" for backward compatibility if no subcommand is provided
 	// We do this by setting Run on flowCmd to treat the first arg as path if it's not "extract" or "diff"
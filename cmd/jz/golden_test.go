@@ -0,0 +1,189 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "rewrite the expected files under testdata")
+
+// goldenCase runs jz with args from the repository root and compares its standard output
+// with the expected file (relative to the root) and its exit status with exit.
+type goldenCase struct {
+	name     string
+	args     []string
+	expected string
+	exit     int
+}
+
+var goldenCases = []goldenCase{
+	{"flows/simple", []string{"flow", "extract", "testdata/flows/simple/input", "--resource", "ExampleApiV1"}, "testdata/flows/simple/expected.md", 0},
+	{"flows/simple/mermaid", []string{"flow", "extract", "testdata/flows/simple/input", "--resource", "ExampleApiV1", "--format", "mermaid"}, "testdata/flows/simple/expected.mmd", 0},
+	{"flows/guards", []string{"flow", "extract", "testdata/flows/guards/input", "--resource", "ExampleApiV1"}, "testdata/flows/guards/expected.md", 0},
+	{"flows/blocks", []string{"flow", "extract", "testdata/flows/blocks/input", "--resource", "OrderApi"}, "testdata/flows/blocks/expected.md", 0},
+	{"flows/blocks/mermaid", []string{"flow", "extract", "testdata/flows/blocks/input", "--resource", "OrderApi", "--format", "mermaid"}, "testdata/flows/blocks/expected.mmd", 0},
+	{"flows/exceptions", []string{"flow", "extract", "testdata/flows/exceptions/input", "--resource", "InvoiceApi"}, "testdata/flows/exceptions/expected.md", 0},
+	{"flows/loops", []string{"flow", "extract", "testdata/flows/loops/input", "--resource", "ShipmentApi"}, "testdata/flows/loops/expected.md", 0},
+	{"flows/loops/mermaid", []string{"flow", "extract", "testdata/flows/loops/input", "--resource", "ShipmentApi", "--format", "mermaid"}, "testdata/flows/loops/expected.mmd", 0},
+	{"flows/lambdas", []string{"flow", "extract", "testdata/flows/lambdas/input", "--resource", "ReportApi"}, "testdata/flows/lambdas/expected.md", 0},
+	{"flows/persistence", []string{"flow", "extract", "testdata/flows/persistence/input", "--resource", "CustomerApi"}, "testdata/flows/persistence/expected.md", 0},
+	{"flows/persistence/report", []string{"report", "markdown", "testdata/flows/persistence/input"}, "testdata/flows/persistence/expected.report.md", 0},
+	{"flows/outbound", []string{"flow", "extract", "testdata/flows/outbound/input", "--resource", "ExampleApiV1"}, "testdata/flows/outbound/expected.md", 0},
+	{"flows/fieldcalls", []string{"flow", "extract", "testdata/flows/fieldcalls/input", "--resource", "OrderApi"}, "testdata/flows/fieldcalls/expected.md", 0},
+	{"flows/restclient", []string{"flow", "extract", "testdata/flows/restclient/input", "--resource", "CheckoutApi"}, "testdata/flows/restclient/expected.md", 0},
+	{"flows/follow", []string{"flow", "extract", "testdata/flows/follow/input", "--resource", "CheckoutApi", "--follow-calls"}, "testdata/flows/follow/expected.md", 0},
+	{"flows/all/json", []string{"flow", "extract", "testdata/flows/all/input", "--all", "--format", "json"}, "testdata/flows/all/expected.json", 0},
+	{"flows/all/qualified", []string{"flow", "extract", "testdata/flows/all/input", "--resource", "com.acme.billing/StatusResource"}, "testdata/flows/all/expected.billing-status.md", 0},
+	{"flows/diff", []string{"flow", "diff", "testdata/flows/diff/v1", "testdata/flows/diff/v2", "--resource", "ExampleApiV1"}, "testdata/flows/diff/expected.diff.md", 0},
+	{"services/sibling-bundles", []string{"report", "markdown", "testdata/services/sibling-bundles/input"}, "testdata/services/sibling-bundles/expected.md", 0},
+	{"services/sibling-modules", []string{"report", "markdown", "testdata/services/sibling-modules/input"}, "testdata/services/sibling-modules/expected.md", 0},
+	{"archives/dependency-jars", []string{"report", "markdown", "testdata/archives/dependency-jars/input"}, "testdata/archives/dependency-jars/expected.md", 0},
+	{"archives/dependency-jars/included", []string{"report", "markdown", "--include-dependency-archives", "testdata/archives/dependency-jars/input"}, "testdata/archives/dependency-jars/expected.included.md", 0},
+	{"ear/with-bundles", []string{"report", "markdown", "testdata/ear/with-bundles/input"}, "testdata/ear/with-bundles/expected.md", 0},
+	{"diff/compatible", []string{"diff", "testdata/diff/compatible/before", "testdata/diff/compatible/after"}, "testdata/diff/compatible/expected.md", 0},
+	{"diff/breaking", []string{"diff", "testdata/diff/breaking/before", "testdata/diff/breaking/after"}, "testdata/diff/breaking/expected.md", exitBreaking},
+	{"openapi/items", []string{"report", "openapi", "testdata/openapi/items/input"}, "testdata/openapi/items/expected.yaml", 0},
+	{"openapi/items/json", []string{"report", "openapi", "testdata/openapi/items/input", "--format", "json"}, "testdata/openapi/items/expected.json", 0},
+	{"openapi/items/check", []string{"check", "openapi", "--spec", "testdata/openapi/items/spec.yaml", "testdata/openapi/items/input"}, "testdata/openapi/items/expected.check.md", 0},
+	{"openapi/items/check/drift", []string{"check", "openapi", "--spec", "testdata/openapi/items/spec-drift.yaml", "testdata/openapi/items/input"}, "testdata/openapi/items/expected.check-drift.md", exitDrift},
+	{"cdi/injection", []string{"report", "markdown", "testdata/cdi/injection/input"}, "testdata/cdi/injection/expected.md", 0},
+	{"messaging/jms-queues", []string{"report", "markdown", "testdata/messaging/jms-queues/input"}, "testdata/messaging/jms-queues/expected.md", 0},
+	{"messaging/jms-queues/mermaid", []string{"report", "mermaid", "testdata/messaging/jms-queues/input"}, "testdata/messaging/jms-queues/expected.mmd", 0},
+	{"messaging/kafka-channels", []string{"report", "markdown", "testdata/messaging/kafka-channels/input"}, "testdata/messaging/kafka-channels/expected.md", 0},
+	{"messaging/kafka-channels/mermaid", []string{"report", "mermaid", "testdata/messaging/kafka-channels/input"}, "testdata/messaging/kafka-channels/expected.mmd", 0},
+	{"persistence/shared-tables", []string{"report", "markdown", "testdata/persistence/shared-tables/input"}, "testdata/persistence/shared-tables/expected.md", 0},
+	{"persistence/shared-tables/mermaid", []string{"report", "mermaid", "testdata/persistence/shared-tables/input"}, "testdata/persistence/shared-tables/expected.mmd", 0},
+	{"statuses/inventory", []string{"report", "markdown", "testdata/statuses/inventory/input"}, "testdata/statuses/inventory/expected.md", 0},
+	{"security/best-match", []string{"report", "markdown", "testdata/security/best-match/input"}, "testdata/security/best-match/expected.md", 0},
+}
+
+// buildJZ builds the jz binary into a temporary directory.
+func buildJZ(t *testing.T) string {
+	t.Helper()
+	bin := filepath.Join(t.TempDir(), "jz")
+	build := exec.Command("go", "build", "-o", bin, ".")
+	if out, err := build.CombinedOutput(); err != nil {
+		t.Fatalf("building jz: %v\n%s", err, out)
+	}
+	return bin
+}
+
+func TestGolden(t *testing.T) {
+	root, err := filepath.Abs("../..")
+	if err != nil {
+		t.Fatal(err)
+	}
+	bin := buildJZ(t)
+
+	for _, tc := range goldenCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cmd := exec.Command(bin, tc.args...)
+			cmd.Dir = root
+			var stdout, stderr bytes.Buffer
+			cmd.Stdout, cmd.Stderr = &stdout, &stderr
+			exit := 0
+			if err := cmd.Run(); err != nil {
+				var exitErr *exec.ExitError
+				if !errors.As(err, &exitErr) {
+					t.Fatal(err)
+				}
+				exit = exitErr.ExitCode()
+			}
+			if exit != tc.exit {
+				t.Errorf("exit status %d, want %d\n%s", exit, tc.exit, stderr.String())
+			}
+
+			expectedFile := filepath.Join(root, tc.expected)
+			if *update {
+				if err := os.WriteFile(expectedFile, stdout.Bytes(), 0644); err != nil {
+					t.Fatal(err)
+				}
+				return
+			}
+			expected, err := os.ReadFile(expectedFile)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(stdout.Bytes(), expected) {
+				t.Errorf("output differs from %s (run go test ./cmd/jz -update to accept):\n%s", tc.expected, stdout.String())
+			}
+		})
+	}
+}
+
+// TestDiffWorkTree compares the committed revision of a repository with its unchanged work
+// tree, given as a relative path: both sides must analyze to the same system.
+func TestDiffWorkTree(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	bin := buildJZ(t)
+	repo := t.TempDir()
+	if err := os.CopyFS(repo, os.DirFS("../../testdata/diff/compatible/after")); err != nil {
+		t.Fatal(err)
+	}
+	for _, args := range [][]string{
+		{"init", "-q"},
+		{"add", "-A"},
+		{"-c", "user.name=jz", "-c", "user.email=jz@example.com", "commit", "-q", "-m", "base"},
+	} {
+		if out, err := exec.Command("git", append([]string{"-C", repo}, args...)...).CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+
+	for _, dir := range []string{".", "./", "src/.."} {
+		cmd := exec.Command(bin, "diff", "--git-base", "HEAD", dir)
+		cmd.Dir = repo
+		out, err := cmd.CombinedOutput()
+		if err != nil {
+			t.Errorf("jz diff --git-base HEAD %s: %v\n%s", dir, err, out)
+			continue
+		}
+		if !bytes.Contains(out, []byte("No structural differences detected")) {
+			t.Errorf("jz diff --git-base HEAD %s reported differences:\n%s", dir, out)
+		}
+	}
+}
+
+// TestFlowExtractAllDirectory writes the flows of every resource as a directory tree, one
+// directory per service, so that same-named resources of different services are kept apart.
+func TestFlowExtractAllDirectory(t *testing.T) {
+	bin := buildJZ(t)
+	out := t.TempDir()
+	cmd := exec.Command(bin, "flow", "extract", "testdata/flows/all/input", "--all", "--format", "all", "--output", out)
+	cmd.Dir = "../.."
+	if output, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("jz flow extract --all: %v\n%s", err, output)
+	}
+
+	var files []string
+	err := filepath.WalkDir(out, func(path string, d os.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		rel, err := filepath.Rel(out, path)
+		files = append(files, filepath.ToSlash(rel))
+		return err
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		"com.acme.billing/InvoiceResource.md",
+		"com.acme.billing/InvoiceResource.mmd",
+		"com.acme.billing/StatusResource.md",
+		"com.acme.billing/StatusResource.mmd",
+		"com.acme.orders/StatusResource.md",
+		"com.acme.orders/StatusResource.mmd",
+	}
+	if !slices.Equal(files, want) {
+		t.Errorf("files = %v, want %v", files, want)
+	}
+}
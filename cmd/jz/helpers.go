@@ -1,9 +1,15 @@
 package main
 
 import (
+	"encoding/json"
 	"fmt"
+	"io/fs"
+	"jz/app"
 	"jz/model"
+	"jz/scan"
 	"os"
+	"path/filepath"
+	"sort"
 )
 
 // filterData filters services and system graph based on the service name.
@@ -40,10 +46,57 @@ func filterData(services []model.Service, sysGraph model.SystemGraph, serviceNam
 		}
 	}
 
-	// 4. Update System Graph
+	// 4. Filter Message Channels (keep every edge of a channel the service touches)
+	touched := make(map[string]bool)
+	for _, e := range sysGraph.MessageEdges {
+		if e.Service == serviceName {
+			touched[e.Protocol+"|"+e.Channel] = true
+		}
+	}
+	var newChannels []model.MessageChannel
+	for _, ch := range sysGraph.Channels {
+		if touched[ch.Protocol+"|"+ch.Name] {
+			newChannels = append(newChannels, ch)
+		}
+	}
+	var newEdges []model.MessageEdge
+	for _, e := range sysGraph.MessageEdges {
+		if touched[e.Protocol+"|"+e.Channel] {
+			newEdges = append(newEdges, e)
+		}
+	}
+
+	// 5. Filter Shared Tables (keep tables the service maps)
+	var newTables []model.SharedTable
+	for _, t := range sysGraph.SharedTables {
+		for _, s := range t.Services {
+			if s == serviceName {
+				newTables = append(newTables, t)
+				break
+			}
+		}
+	}
+
+	// 6. Filter External Systems (keep the systems the service calls)
+	var newExternalDeps []model.ExternalDependency
+	var newExternalSystems []string
+	for _, d := range sysGraph.ExternalDependencies {
+		if d.FromService == serviceName {
+			newExternalDeps = append(newExternalDeps, d)
+			newExternalSystems = append(newExternalSystems, d.System)
+		}
+	}
+	sort.Strings(newExternalSystems)
+
+	// 7. Update System Graph
 	newGraph := model.SystemGraph{
-		Services:     []string{serviceName},
-		Dependencies: newDeps,
+		Services:             []string{serviceName},
+		Dependencies:         newDeps,
+		Channels:             newChannels,
+		MessageEdges:         newEdges,
+		SharedTables:         newTables,
+		ExternalSystems:      newExternalSystems,
+		ExternalDependencies: newExternalDeps,
 	}
 
 	return newServices, newGraph, nil
@@ -58,3 +111,90 @@ func writeOutput(content string, outputPath string) error {
 
 	return os.WriteFile(outputPath, []byte(content), 0644)
 }
+
+// marshalJSON renders a value as indented JSON.
+func marshalJSON(v interface{}) string {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error encoding JSON: %v\n", err)
+		os.Exit(1)
+	}
+	return string(data)
+}
+
+// diffSide is one side of a comparison: a directory on disk, or a git revision held in
+// memory and reported under "<repo>@<rev>".
+type diffSide struct {
+	fsys  fs.FS // nil for a directory on disk
+	path  string
+	label string
+}
+
+func (s diffSide) analyze() ([]model.Service, model.SystemGraph, app.Diagnostic) {
+	if s.fsys == nil {
+		return analyze(s.path)
+	}
+	return linkExternal(app.AnalyzeFS(s.fsys, s.path, treeOptions()))
+}
+
+// analyze analyzes rootDir and links its outbound calls to the --external systems.
+func analyze(rootDir string) ([]model.Service, model.SystemGraph, app.Diagnostic) {
+	return linkExternal(app.Analyze(rootDir, treeOptions()))
+}
+
+// treeOptions returns the archive options of the --include-dependency-archives flag.
+func treeOptions() scan.TreeOptions {
+	return scan.TreeOptions{DependencyArchives: includeDependencyArchives}
+}
+
+// linkExternal resolves unresolved outbound calls against the --external systems.
+func linkExternal(services []model.Service, sysGraph model.SystemGraph, diag app.Diagnostic) ([]model.Service, model.SystemGraph, app.Diagnostic) {
+	if len(externalPaths) == 0 {
+		return services, sysGraph, diag
+	}
+	systems, err := app.LoadExternalSystems(externalPaths)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error loading external systems: %v\n", err)
+		os.Exit(1)
+	}
+	return services, app.LinkExternalSystems(services, systems), diag
+}
+
+// diffInputs returns the two trees to compare. Without git revisions they are the two
+// path arguments. With --git-base, the single argument is a git work tree: the base
+// revision (and the head revision, if given) are read from the repository, and the head
+// defaults to the work tree itself.
+func diffInputs(args []string, gitBase, gitHead string) (a, b diffSide, err error) {
+	if gitBase == "" && gitHead == "" {
+		if len(args) != 2 {
+			return a, b, fmt.Errorf("expected <pathA> <pathB>, or --git-base <rev> <repo>")
+		}
+		return diffSide{path: args[0], label: args[0]}, diffSide{path: args[1], label: args[1]}, nil
+	}
+	if gitBase == "" {
+		return a, b, fmt.Errorf("--git-head requires --git-base")
+	}
+	if len(args) != 1 {
+		return a, b, fmt.Errorf("expected a single <repo> path with --git-base")
+	}
+	// Both sides report paths under the same cleaned name ("./" and "." alike)
+	repo := filepath.Clean(args[0])
+
+	if a, err = revisionSide(repo, gitBase); err != nil {
+		return a, b, err
+	}
+	b = diffSide{path: repo, label: repo + " (work tree)"}
+	if gitHead != "" {
+		b, err = revisionSide(repo, gitHead)
+	}
+	return a, b, err
+}
+
+func revisionSide(repo, rev string) (diffSide, error) {
+	fsys, err := app.RevisionFS(repo, rev)
+	if err != nil {
+		return diffSide{}, err
+	}
+	name := fmt.Sprintf("%s@%s", repo, rev)
+	return diffSide{fsys: fsys, path: name, label: name}, nil
+}
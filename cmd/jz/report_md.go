@@ -2,7 +2,6 @@ package main
 
 import (
 	"fmt"
-	"jz/app"
 	"jz/report"
 	"os"
 
@@ -20,7 +19,7 @@ var reportMarkdownCmd = &cobra.Command{
 	Args:  cobra.ExactArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
 		rootDir := args[0]
-		services, sysGraph, diag := app.Analyze(rootDir)
+		services, sysGraph, diag := analyze(rootDir)
 
 		// Filter
 		services, sysGraph, err := filterData(services, sysGraph, mdService)
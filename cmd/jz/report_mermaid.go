@@ -2,7 +2,6 @@ package main
 
 import (
 	"fmt"
-	"jz/app"
 	"jz/report"
 	"os"
 	"strings"
@@ -22,7 +21,7 @@ var reportMermaidCmd = &cobra.Command{
 	Args:  cobra.ExactArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
 		rootDir := args[0]
-		services, sysGraph, _ := app.Analyze(rootDir)
+		services, sysGraph, _ := analyze(rootDir)
 
 		// Filter
 		services, sysGraph, err := filterData(services, sysGraph, mermaidService)
@@ -53,6 +52,14 @@ var reportMermaidCmd = &cobra.Command{
 				sb.WriteString(report.GenerateComponentMermaid(svc.InternalGraph))
 				sb.WriteString("\n")
 			}
+
+			// Data model (per service with JPA entities)
+			for _, svc := range services {
+				if len(svc.Entities) > 0 {
+					sb.WriteString(report.GenerateEntityMermaid(svc, sysGraph.SharedTables))
+					sb.WriteString("\n")
+				}
+			}
 		}
 
 		// Output
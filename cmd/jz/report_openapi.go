@@ -0,0 +1,94 @@
+package main
+
+import (
+	"fmt"
+	"jz/model"
+	"jz/report"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	openapiService string
+	openapiOutput  string
+	openapiFormat  string
+)
+
+var reportOpenAPICmd = &cobra.Command{
+	Use:   "openapi <root-path>",
+	Short: "Generate OpenAPI 3 documents of the REST resources, one per service",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		if openapiFormat != "yaml" && openapiFormat != "json" {
+			fmt.Fprintf(os.Stderr, "Error: invalid format '%s'\n", openapiFormat)
+			os.Exit(1)
+		}
+
+		rootDir := args[0]
+		services, sysGraph, _ := analyze(rootDir)
+
+		// Filter
+		services, _, err := filterData(services, sysGraph, openapiService)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		var documented []model.Service
+		for _, svc := range services {
+			if report.HasOpenAPIOperations(svc) {
+				documented = append(documented, svc)
+			}
+		}
+		if len(documented) == 0 {
+			fmt.Fprintln(os.Stderr, "Error: no JAX-RS operations detected; nothing to document")
+			os.Exit(1)
+		}
+
+		// A single document goes to --output or stdout; several go to one file per service
+		// under the --output directory, or to stdout as a YAML stream.
+		if len(documented) == 1 {
+			if err := writeOutput(report.GenerateOpenAPI(documented[0], openapiFormat), openapiOutput); err != nil {
+				fmt.Fprintf(os.Stderr, "Error writing output: %v\n", err)
+				os.Exit(1)
+			}
+			return
+		}
+
+		if openapiOutput == "" {
+			if openapiFormat == "json" {
+				fmt.Fprintln(os.Stderr, "Error: several services found; use --service or --output <dir> with --format json")
+				os.Exit(1)
+			}
+			var docs []string
+			for _, svc := range documented {
+				docs = append(docs, report.GenerateOpenAPI(svc, openapiFormat))
+			}
+			fmt.Print("---\n" + strings.Join(docs, "---\n"))
+			return
+		}
+
+		if err := os.MkdirAll(openapiOutput, 0755); err != nil {
+			fmt.Fprintf(os.Stderr, "Error creating output directory: %v\n", err)
+			os.Exit(1)
+		}
+		for _, svc := range documented {
+			file := filepath.Join(openapiOutput, safeFileName(svc.Name)+".openapi."+openapiFormat)
+			if err := os.WriteFile(file, []byte(report.GenerateOpenAPI(svc, openapiFormat)), 0644); err != nil {
+				fmt.Fprintf(os.Stderr, "Error writing output: %v\n", err)
+				os.Exit(1)
+			}
+		}
+		fmt.Printf("OpenAPI documents of %d service(s) written to %s\n", len(documented), openapiOutput)
+	},
+}
+
+func init() {
+	reportOpenAPICmd.Flags().StringVar(&openapiService, "service", "", "Filter by service name")
+	reportOpenAPICmd.Flags().StringVar(&openapiOutput, "output", "", "Write output to file (or directory, for several services)")
+	reportOpenAPICmd.Flags().StringVar(&openapiFormat, "format", "yaml", "Output format (yaml, json)")
+	reportCmd.AddCommand(reportOpenAPICmd)
+}
@@ -12,6 +12,19 @@ var rootCmd = &cobra.Command{
 	Long:  `jz performs static analysis on Java codebases, focusing on OSGi and JAX-RS constructs.`,
 }
 
+// externalPaths are the --external registries of external systems (OpenAPI spec
+// directories or files, or host catalogs).
+var externalPaths []string
+
+// includeDependencyArchives makes the analysis descend into the archives of lib, target
+// and build directories, which are third-party libraries or build outputs.
+var includeDependencyArchives bool
+
+func init() {
+	rootCmd.PersistentFlags().BoolVar(&includeDependencyArchives, "include-dependency-archives", false, "Also analyze the .jar/.war/.ear archives under lib, WEB-INF/lib, target and build directories")
+	rootCmd.PersistentFlags().StringArrayVar(&externalPaths, "external", nil, "Register external systems from a directory of OpenAPI specs, a spec or a host catalog (repeatable)")
+}
+
 func Execute() {
 	if err := rootCmd.Execute(); err != nil {
 		os.Exit(1)
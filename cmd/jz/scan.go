@@ -2,7 +2,6 @@ package main
 
 import (
     "fmt"
-    "jz/app"
     "jz/report"
 
     "github.com/spf13/cobra"
@@ -14,7 +13,7 @@ var scanCmd = &cobra.Command{
     Args:  cobra.ExactArgs(1),
     Run: func(cmd *cobra.Command, args []string) {
         rootDir := args[0]
-        services, sysGraph, diagnostic := app.Analyze(rootDir)
+        services, sysGraph, diagnostic := analyze(rootDir)
         fmt.Println(report.GenerateMarkdown(services, sysGraph, diagnostic))
     },
 }
@@ -1,7 +1,11 @@
 package graph
 
 import (
+	"fmt"
 	"jz/model"
+	"slices"
+	"sort"
+	"strings"
 )
 
 // BuildSystemGraph constructs a system-level dependency graph from a list of Services.
@@ -58,6 +62,7 @@ func BuildSystemGraph(services []model.Service) model.SystemGraph {
 								FromService: svc.Name,
 								ToService:   providerName,
 								Interface:   refIface,
+								Kind:        model.DependencyDS,
 							}
 							graph.Dependencies = append(graph.Dependencies, dep)
 							seenEdges[key] = true
@@ -68,5 +73,170 @@ func BuildSystemGraph(services []model.Service) model.SystemGraph {
 		}
 	}
 
+	// SOAP Edges (resolved cross-service client calls)
+	for _, svc := range services {
+		for _, call := range svc.SOAPCalls {
+			if call.ResolutionScope != model.ResolutionCrossService {
+				continue
+			}
+			key := edgeKey{
+				From:  svc.Name,
+				To:    call.TargetService,
+				Iface: "soap:" + call.TargetEndpoint,
+			}
+			if !seenEdges[key] {
+				graph.Dependencies = append(graph.Dependencies, model.ServiceDependency{
+					FromService: svc.Name,
+					ToService:   call.TargetService,
+					Interface:   key.Iface,
+					Kind:        model.DependencySOAP,
+				})
+				seenEdges[key] = true
+			}
+		}
+	}
+
+	// Message Channels (producer -> channel -> consumer)
+	type channelKey struct {
+		Protocol, Name string
+	}
+	seenChannels := make(map[channelKey]bool)
+	seenMessageEdges := make(map[model.MessageEdge]bool)
+	for _, svc := range services {
+		for _, ep := range svc.MessageEndpoints {
+			if ep.Destination == "" {
+				continue
+			}
+			ck := channelKey{Protocol: ep.Protocol, Name: ep.Destination}
+			if !seenChannels[ck] {
+				graph.Channels = append(graph.Channels, model.MessageChannel{
+					Name:     ep.Destination,
+					Kind:     ep.DestinationKind,
+					Protocol: ep.Protocol,
+				})
+				seenChannels[ck] = true
+			}
+			edge := model.MessageEdge{
+				Service:  svc.Name,
+				Protocol: ep.Protocol,
+				Channel:  ep.Destination,
+				Role:     ep.Role,
+			}
+			if !seenMessageEdges[edge] {
+				graph.MessageEdges = append(graph.MessageEdges, edge)
+				seenMessageEdges[edge] = true
+			}
+		}
+	}
+
+	// External Systems (calls resolved to registered external systems)
+	externalCalls := make(map[[2]string]int)
+	for _, svc := range services {
+		for _, call := range svc.RESTCalls {
+			if call.ResolutionScope != model.ResolutionExternal {
+				continue
+			}
+			key := [2]string{svc.Name, call.TargetSystem}
+			if externalCalls[key] == 0 {
+				graph.ExternalDependencies = append(graph.ExternalDependencies, model.ExternalDependency{
+					FromService: svc.Name,
+					System:      call.TargetSystem,
+				})
+				if !slices.Contains(graph.ExternalSystems, call.TargetSystem) {
+					graph.ExternalSystems = append(graph.ExternalSystems, call.TargetSystem)
+				}
+			}
+			externalCalls[key]++
+		}
+	}
+	for i := range graph.ExternalDependencies {
+		d := &graph.ExternalDependencies[i]
+		d.Calls = externalCalls[[2]string{d.FromService, d.System}]
+	}
+	sort.Strings(graph.ExternalSystems)
+	sort.SliceStable(graph.ExternalDependencies, func(i, j int) bool {
+		a, b := graph.ExternalDependencies[i], graph.ExternalDependencies[j]
+		if a.FromService != b.FromService {
+			return a.FromService < b.FromService
+		}
+		return a.System < b.System
+	})
+
+	// Shared Tables (the same table mapped by entities of several services). Mappings are
+	// grouped by table and data source; a mapping whose data source is not known may use
+	// any of them, so it joins every group of its table, which is then reported with the
+	// data source undetermined.
+	type tableMapping struct {
+		Table, Service, Entity, DataSource string
+	}
+	mappings := make(map[string][]tableMapping)
+	var tableOrder []string
+	for _, svc := range services {
+		for _, e := range svc.Entities {
+			key := strings.ToLower(e.Table)
+			if _, ok := mappings[key]; !ok {
+				tableOrder = append(tableOrder, key)
+			}
+			mappings[key] = append(mappings[key], tableMapping{
+				Table:      e.Table,
+				Service:    svc.Name,
+				Entity:     fmt.Sprintf("%s:%s (%s)", svc.Name, e.Name, e.Evidence),
+				DataSource: entityDataSource(svc, e),
+			})
+		}
+	}
+	for _, key := range tableOrder {
+		var dataSources []string
+		for _, m := range mappings[key] {
+			if m.DataSource != "" && !slices.Contains(dataSources, m.DataSource) {
+				dataSources = append(dataSources, m.DataSource)
+			}
+		}
+		if len(dataSources) == 0 {
+			dataSources = []string{""}
+		}
+		for _, ds := range dataSources {
+			t := model.SharedTable{Table: mappings[key][0].Table, DataSource: ds}
+			var known []string
+			for _, m := range mappings[key] {
+				if m.DataSource != ds && m.DataSource != "" {
+					continue
+				}
+				if m.DataSource == ds && !slices.Contains(known, m.Service) {
+					known = append(known, m.Service)
+				}
+				if !slices.Contains(t.Services, m.Service) {
+					t.Services = append(t.Services, m.Service)
+				}
+				t.Entities = append(t.Entities, m.Entity)
+			}
+			if len(t.Services) < 2 {
+				continue
+			}
+			if len(known) < len(t.Services) {
+				t.DataSource = ""
+			}
+			sort.Strings(t.Services)
+			graph.SharedTables = append(graph.SharedTables, t)
+		}
+	}
+	sort.SliceStable(graph.SharedTables, func(i, j int) bool {
+		return strings.ToLower(graph.SharedTables[i].Table) < strings.ToLower(graph.SharedTables[j].Table)
+	})
+
 	return graph
 }
+
+// entityDataSource returns the data source of the unit managing an entity: the linked
+// Liberty dataSource, else the data source name declared by the unit.
+func entityDataSource(svc model.Service, e model.Entity) string {
+	for _, u := range svc.PersistenceUnits {
+		if u.Name == e.Unit {
+			if u.DataSource != "" {
+				return u.DataSource
+			}
+			return strings.TrimPrefix(u.DataSourceName(), "java:comp/env/")
+		}
+	}
+	return ""
+}
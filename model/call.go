@@ -18,6 +18,7 @@ const (
 const (
 	ResolutionSameService  = "same-service"
 	ResolutionCrossService = "cross-service"
+	ResolutionExternal     = "external" // Registered external system (see ExternalSystem)
 	ResolutionUnresolved   = "unresolved"
 )
 
@@ -31,10 +32,11 @@ type RESTCall struct {
 	TargetPath         string // Literal or resolved string
 	TargetService      string // Only populated if unambiguous
 	TargetResource     string // Only populated if unambiguous
+	TargetSystem       string // External system, for calls resolved to the external scope
 	SourceFile         string
 	DetectionType      string // literal, constant, unknown
 	Confidence         string // high, medium, low
-	ResolutionScope    string // same-service, cross-service, unresolved
+	ResolutionScope    string // same-service, cross-service, external, unresolved
 	ResolutionEvidence string // Short explanation of resolution (e.g. "path+method match")
 }
 
@@ -0,0 +1,46 @@
+package model
+
+// Bean kinds describe how a managed bean is declared.
+const (
+	BeanCDI      = "cdi"
+	BeanEJB      = "ejb"
+	BeanProducer = "producer"
+)
+
+// Injection point resolution states.
+const (
+	InjectionResolved    = "resolved"
+	InjectionAmbiguous   = "ambiguous"
+	InjectionUnsatisfied = "unsatisfied"
+	InjectionExternal    = "external" // Type is not declared in the scanned sources
+)
+
+// Bean represents a CDI managed bean, an EJB session bean or a CDI producer.
+type Bean struct {
+	Name          string   // Class name, or Class.member for producers
+	Class         string   // Simple name of the declaring class
+	QualifiedName string   // Package-qualified declaring class
+	Kind          string   // cdi, ejb, producer
+	Scope         string   // ApplicationScoped, RequestScoped, Dependent, Stateless, Singleton, ...
+	Types         []string // Bean types (simple names): class, superclass and interfaces, or the producer type
+	Qualifiers    []string // Qualifier annotations (simple names), Named("x") for @Named
+	Alternative   bool     // Enabled alternative (@Alternative with @Priority)
+	SourceFile    string
+	Evidence      string // file:line of the declaration
+}
+
+// InjectionPoint represents an @Inject or @EJB dependency declared by a class.
+type InjectionPoint struct {
+	Class       string // Declaring class (simple name)
+	Member      string // Field name, or constructor/method parameter name
+	Type        string // Required type (simple name, generics stripped)
+	Qualifiers  []string
+	Annotation  string // Inject, EJB
+	BeanName    string // @EJB(beanName = "...")
+	SourceFile  string
+	Evidence    string
+	Status      string   // resolved, ambiguous, unsatisfied, external
+	Bean        string   // Resolved bean name (if resolved)
+	Candidates  []string // Matching beans (if ambiguous)
+	Explanation string
+}
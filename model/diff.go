@@ -23,3 +23,85 @@ type StepDiff struct {
 	Before *FlowStep
 	After  *FlowStep
 }
+
+// ChangeKind defines the type of change of an item between two analyses.
+type ChangeKind string
+
+const (
+	ChangeAdded   ChangeKind = "added"
+	ChangeRemoved ChangeKind = "removed"
+	ChangeChanged ChangeKind = "changed"
+)
+
+// SystemDiff represents the structural differences between two complete analyses.
+type SystemDiff struct {
+	Services     []ItemChange     // Services added or removed
+	Endpoints    []EndpointChange // REST endpoints added, removed or changed
+	Components   []ItemChange     // DS components and their provided/referenced interfaces
+	Dependencies []EdgeChange     // System graph edges (DS, SOAP and message edges)
+	Features     []ItemChange     // Liberty features
+	Calls        []EdgeChange     // Resolved REST call edges
+}
+
+// Empty reports whether the two analyses are structurally identical.
+func (d SystemDiff) Empty() bool {
+	return len(d.Services) == 0 && len(d.Endpoints) == 0 && len(d.Components) == 0 &&
+		len(d.Dependencies) == 0 && len(d.Features) == 0 && len(d.Calls) == 0
+}
+
+// BreakingChanges returns the number of endpoint changes that break existing clients.
+func (d SystemDiff) BreakingChanges() int {
+	n := 0
+	for _, c := range d.Endpoints {
+		if c.Breaking {
+			n++
+		}
+	}
+	return n
+}
+
+// ItemChange is a named item added, removed or changed between two analyses.
+type ItemChange struct {
+	Kind    ChangeKind
+	Service string // Owning service (empty for system-wide items)
+	Name    string
+	Details []string // What changed (for changed items)
+}
+
+// EdgeChange is a graph edge added or removed between two analyses.
+type EdgeChange struct {
+	Kind  ChangeKind
+	From  string
+	To    string
+	Label string // Interface, channel role or call (e.g. "GET /orders")
+	Type  string // ds, soap, message, rest
+}
+
+// EndpointChange is a REST endpoint added, removed or changed between two analyses.
+// Endpoints are matched by service, HTTP method and path, then by service and handler
+// (a changed path or HTTP method).
+type EndpointChange struct {
+	Kind    ChangeKind
+	Before  *Endpoint // Nil for added endpoints
+	After   *Endpoint // Nil for removed endpoints
+	Details []string  // What changed (for changed endpoints)
+
+	// API compatibility for existing clients
+	Breaking        bool
+	BreakingReasons []string
+}
+
+// Endpoint is the contract of a single REST method as compared across analyses.
+type Endpoint struct {
+	Service    string
+	Resource   string
+	HTTPMethod string
+	Path       string
+	Handler    string
+	Consumes   []string
+	Produces   []string
+	Auth       []string // Resource auth annotations (e.g. @RolesAllowed)
+	Roles      []string // @RolesAllowed roles, when known
+	WebRoles   []string // Roles of a covering web.xml security-constraint
+	WebSecured bool     // Covered by a web.xml security-constraint
+}
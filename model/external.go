@@ -0,0 +1,18 @@
+package model
+
+// ExternalSystem is a system outside the analyzed sources that outbound calls can target.
+// It is registered from an OpenAPI document or from a catalog of host names.
+type ExternalSystem struct {
+	Name       string
+	Hosts      []string        // Host names calls use (host or host:port)
+	BasePath   string          // Path of the spec server URL, if any
+	Operations []SpecOperation // Spec operations (empty for catalog entries)
+	Source     string          // File the system was registered from
+}
+
+// ExternalDependency is an edge from a service to an external system it calls.
+type ExternalDependency struct {
+	FromService string
+	System      string
+	Calls       int // Resolved calls from the service to the system
+}
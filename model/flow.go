@@ -3,30 +3,103 @@ package model
 // ExecutionFlow represents the extracted execution flow for a specific REST resource.
 type ExecutionFlow struct {
 	ResourceName string
-	EntryPoint   string // HTTP method + path
-	Steps        []FlowStep
+	EntryPoint   string     // HTTP method + path
+	Blocks       []FlowNode // Block-structured steps
+	Steps        []FlowStep // Pre-order flattening of Blocks
+
+	// HTTP statuses set through Response builders in the expanded methods
+	ResponseStatuses []ResponseStatus
+
+	// HTTP statuses produced by exceptions that leave the handler
+	ErrorStatuses []FlowErrorStatus
+}
+
+// FlowErrorStatus is an HTTP status a flow can produce by throwing an exception.
+type FlowErrorStatus struct {
+	Status    int // 0 if not determined statically
+	Exception string
+	Mapping   string // How the exception maps to the status
+	Evidence  string // Throw site (file:line)
+}
+
+// FlowNode is a flow step together with the steps nested under it.
+// Conditions hold their then-branch in Children and their else-branch in Else;
+// calls hold the expanded callee in Children; containers (loops, try blocks)
+// hold their body in Children. Try blocks hold their catch and finally handlers
+// in Else; switches hold one case node per branch in Children; lambdas and method
+// references hold the steps of their body in Children; followed outbound calls hold the
+// flow of the target handler in Children.
+type FlowNode struct {
+	Step     FlowStep
+	Children []FlowNode
+	Else     []FlowNode
 }
 
 // FlowStepKind defines the type of execution step.
 type FlowStepKind string
 
 const (
-	FlowStepEntry      FlowStepKind = "entry"
-	FlowStepCondition  FlowStepKind = "condition"
-	FlowStepCall       FlowStepKind = "call"
-	FlowStepOutbound   FlowStepKind = "outbound"
-	FlowStepReturn     FlowStepKind = "return"
-	FlowStepUnexpanded FlowStepKind = "unexpanded"
+	FlowStepEntry       FlowStepKind = "entry"
+	FlowStepCondition   FlowStepKind = "condition"
+	FlowStepCall        FlowStepKind = "call"
+	FlowStepOutbound    FlowStepKind = "outbound"
+	FlowStepReturn      FlowStepKind = "return"
+	FlowStepUnexpanded  FlowStepKind = "unexpanded"
+	FlowStepTry         FlowStepKind = "try"
+	FlowStepCatch       FlowStepKind = "catch"
+	FlowStepFinally     FlowStepKind = "finally"
+	FlowStepThrow       FlowStepKind = "throw"
+	FlowStepLoop        FlowStepKind = "loop"
+	FlowStepSwitch      FlowStepKind = "switch"
+	FlowStepCase        FlowStepKind = "case"
+	FlowStepLambda      FlowStepKind = "lambda"
+	FlowStepPersistence FlowStepKind = "persistence"
+)
+
+// Execution scopes of steps that do not run directly in the handler thread.
+const (
+	FlowScopeLambda = "lambda" // Lambda or method reference (e.g. a stream stage); may run later or not at all
+	FlowScopeAsync  = "async"  // Task handed to an executor or a CompletableFuture stage
 )
 
 // FlowStep represents a single step in an execution flow.
 type FlowStep struct {
-	Index           int
-	Kind            FlowStepKind
-	Description     string
-	FromMethod      string
-	ToMethod        string
-	Confidence      string
-	Evidence        string
-	ResolutionScope string
+	Index              int
+	Depth              int // Nesting depth in the block structure (0 = handler body)
+	Kind               FlowStepKind
+	Description        string
+	FromMethod         string
+	ToMethod           string
+	Confidence         string
+	Evidence           string
+	ResolutionScope    string
+	ResolutionEvidence string // How a cross-class call was mapped to its implementation, or a throw to its status
+
+	// Exception handling
+	Exceptions []string // Caught (catch) or thrown (throw) exception types
+	HTTPStatus int      // Status a throw produces (0 if caught or not determined)
+	CaughtAt   string   // Evidence of the enclosing catch that handles a throw
+
+	// Deferred execution: the lambda/async scope the step runs in (see FlowScope constants).
+	// On a lambda step it is the scope the lambda opens.
+	ExecutionScope string
+
+	// Persistence: entities and tables a database access touches
+	Entities []string
+	Tables   []string
+
+	// Service the step runs in; set only on steps of a followed cross-service flow
+	Service string
+}
+
+// ResourceFlows groups the flows of one resource, addressed by service and resource name.
+type ResourceFlows struct {
+	Service  string
+	Resource string
+	Flows    []ExecutionFlow
+}
+
+// QualifiedName returns the service-qualified resource name (service/Resource).
+func (r ResourceFlows) QualifiedName() string {
+	return r.Service + "/" + r.Resource
 }
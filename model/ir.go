@@ -4,6 +4,7 @@ package model
 type Service struct {
 	Name        string
 	RootPath    string
+	Tree        *SourceTree `json:"-"` // Files the service was analyzed from
 	EntryPoints []EntryPoint
 	Components  []DSComponent
 
@@ -15,12 +16,41 @@ type Service struct {
 	Features    []string
 	Application LibertyApp
 
+	// Web deployment descriptor (WEB-INF/web.xml), if present
+	WebApp WebDescriptor
+
+	// Enterprise packaging (EAR) context
+	EnterpriseApp string // Owning EAR name, if the service is an EAR web module
+
 	// REST Resources (grouped entry points)
 	RESTResources []RESTResource
 
+	// JAX-RS ExceptionMapper providers
+	ExceptionMappers []ExceptionMapper
+
 	// Phase F4 additions
 	RESTCalls  []RESTCall
 	Boundaries []ServiceBoundary
+
+	// JAX-WS (SOAP) endpoints and client calls
+	SOAPEndpoints []SOAPEndpoint
+	SOAPCalls     []SOAPCall
+
+	// Message producers and consumers
+	MessageEndpoints []MessageEndpoint
+	ChannelConfigs   []ChannelConfig // mp.messaging.* channel configuration
+
+	// CDI beans, EJBs and their injection points
+	Beans           []Bean
+	InjectionPoints []InjectionPoint
+
+	// Database accesses and the named queries they can refer to
+	DataAccesses []DataAccess
+	NamedQueries []NamedQuery
+
+	// JPA data model
+	Entities         []Entity
+	PersistenceUnits []PersistenceUnit
 }
 
 // EntryPoint represents a REST entry point.
@@ -30,8 +60,23 @@ type EntryPoint struct {
 	Handler    string
 	SourceFile string
 	Resource   string // Resource class name (derived from handler)
+	Kind       string // jax-rs, servlet
+	Params     []RESTParam
+	Consumes   []string // Effective @Consumes media types (method-level, else class-level)
+	Produces   []string // Effective @Produces media types (method-level, else class-level)
+	Auth       []string // Effective auth annotations (method-level, else class-level)
+	Roles      []string // @RolesAllowed roles of the effective auth annotations, if all literal
 }
 
+// EntryPoint kinds describe how a request is dispatched to the handler.
+const (
+	EntryPointJAXRS   = "jax-rs"
+	EntryPointServlet = "servlet"
+)
+
+// HTTPMethodAny marks a servlet service() handler that accepts every HTTP method.
+const HTTPMethodAny = "ANY"
+
 // DSComponent represents an OSGi Declarative Service.
 type DSComponent struct {
 	Name                 string
@@ -48,6 +93,13 @@ type LibertyServer struct {
 	ServerXML       string
 	EnabledFeatures []string
 	DeployedApps    []LibertyApp
+
+	// Messaging resources
+	JMSDestinations []JMSDestination
+	ActivationSpecs []JMSActivationSpec
+
+	// JDBC data sources
+	DataSources []DataSource
 }
 
 // LibertyApp represents an application deployed in Liberty.
@@ -58,6 +110,22 @@ type LibertyApp struct {
 	ContextRoot string
 }
 
+// EnterpriseApp represents a Java EE enterprise application (EAR) described by META-INF/application.xml.
+type EnterpriseApp struct {
+	Name           string
+	ApplicationXML string
+	RootPath       string // Directory containing META-INF/application.xml
+	Modules        []EARModule
+}
+
+// EARModule represents a single module declared in application.xml.
+type EARModule struct {
+	Type        string // web, ejb, java, connector
+	URI         string // e.g. orders-web.war
+	ContextRoot string // Only for web modules
+	Path        string // Resolved module directory (empty if not found)
+}
+
 // DependencyGraph represents internal component-level dependencies.
 type DependencyGraph struct {
 	Nodes []ComponentNode
@@ -82,6 +150,17 @@ type DependencyEdge struct {
 type SystemGraph struct {
 	Services     []string
 	Dependencies []ServiceDependency
+
+	// Asynchronous coupling through message channels
+	Channels     []MessageChannel
+	MessageEdges []MessageEdge
+
+	// Coupling through tables mapped by several services
+	SharedTables []SharedTable
+
+	// REST calls to registered external systems
+	ExternalSystems      []string
+	ExternalDependencies []ExternalDependency
 }
 
 // ServiceDependency represents a dependency from one service to another.
@@ -89,4 +168,11 @@ type ServiceDependency struct {
 	FromService string
 	ToService   string
 	Interface   string
+	Kind        string // ds, soap
 }
+
+// Dependency kinds describe the mechanism behind a system-level dependency.
+const (
+	DependencyDS   = "ds"
+	DependencySOAP = "soap"
+)
@@ -0,0 +1,72 @@
+package model
+
+// Messaging roles describe how a service participates in a message channel.
+const (
+	MessageProducer = "producer"
+	MessageConsumer = "consumer"
+)
+
+// Messaging protocols.
+const (
+	ProtocolJMS   = "jms"
+	ProtocolKafka = "kafka"
+	// ProtocolReactive marks a MicroProfile Reactive Messaging channel whose
+	// connector is not configured (in-memory or unresolved).
+	ProtocolReactive = "reactive"
+)
+
+// MessageEndpoint represents a producer or consumer of a message channel detected in source code.
+type MessageEndpoint struct {
+	Service            string
+	Class              string
+	Role               string // producer, consumer
+	Protocol           string // jms, kafka, reactive, or the configured connector protocol
+	DestinationKind    string // queue, topic, channel
+	Destination        string // Resolved physical destination name (empty if unresolved)
+	Reference          string // As written in source (JNDI name, destination name, or MDB class)
+	ReferenceType      string // name, jndi, activation-spec, channel
+	SourceFile         string
+	Evidence           string // file:line
+	Confidence         string
+	ResolutionEvidence string
+}
+
+// MessageChannel represents a messaging destination shared by producers and consumers.
+type MessageChannel struct {
+	Name     string
+	Kind     string // queue, topic
+	Protocol string
+}
+
+// MessageEdge connects a service to a message channel (producer -> channel or channel -> consumer).
+type MessageEdge struct {
+	Service  string
+	Protocol string
+	Channel  string
+	Role     string // producer, consumer
+}
+
+// JMSDestination represents a Liberty jmsQueue or jmsTopic definition.
+type JMSDestination struct {
+	ID           string
+	JNDIName     string
+	Kind         string // queue, topic
+	PhysicalName string // queueName/topicName (wasJms) or baseQueueName/baseTopicName (wmqJms)
+}
+
+// JMSActivationSpec represents a Liberty jmsActivationSpec binding an MDB to a destination.
+type JMSActivationSpec struct {
+	ID                string // application/module/bean
+	DestinationRef    string // ID of a jmsQueue/jmsTopic
+	DestinationLookup string // JNDI name
+}
+
+// ChannelConfig represents the mp.messaging.* configuration of one reactive messaging channel.
+type ChannelConfig struct {
+	Direction   string // incoming, outgoing
+	Channel     string
+	Connector   string // e.g. smallrye-kafka, liberty-kafka
+	Destination string // topic, address or destination attribute (empty = channel name)
+	SourceFile  string
+	Evidence    string // file:line of the connector attribute
+}
@@ -0,0 +1,102 @@
+package model
+
+// Data access kinds describe the API a persistence access goes through.
+const (
+	DataAccessJPA  = "jpa"
+	DataAccessJDBC = "jdbc"
+)
+
+// DataAccess represents a database access detected in source code: an EntityManager
+// operation, a named or native query, or a JDBC statement with literal SQL.
+type DataAccess struct {
+	Kind       string   // jpa, jdbc
+	Operation  string   // find, persist, merge, remove, createQuery, createNamedQuery, prepareStatement, ...
+	Query      string   // Literal JPQL/SQL, or the name of a named query
+	Entities   []string // JPA entity names (argument class or parsed from JPQL)
+	Tables     []string // Table names parsed from literal SQL
+	Class      string   // Class containing the access
+	SourceFile string
+	Evidence   string // file:line
+}
+
+// NamedQuery represents a @NamedQuery or @NamedNativeQuery declared on an entity class.
+type NamedQuery struct {
+	Name       string
+	Query      string
+	Native     bool
+	Entity     string   // Declaring class
+	Entities   []string // Entity names parsed from JPQL
+	Tables     []string // Table names parsed from native SQL
+	SourceFile string
+	Evidence   string // file:line
+}
+
+// Entity represents a JPA @Entity class and the table it maps.
+type Entity struct {
+	Name       string // Entity name (@Entity(name = ...) or the class name)
+	Class      string // Simple class name
+	Package    string
+	Table      string // @Table(name = ...) or the entity name
+	Columns    []EntityColumn
+	Relations  []EntityRelation
+	Unit       string // Persistence unit managing the entity (empty if not determined)
+	SourceFile string
+	Evidence   string // file:line of the class declaration
+}
+
+// EntityColumn is a persistent field of an entity.
+type EntityColumn struct {
+	Field  string
+	Column string // @Column(name = ...) or the field name
+	Type   string // Declared Java type (simple name)
+	ID     bool   // @Id or @EmbeddedId
+}
+
+// EntityRelation is a relationship field of an entity (@OneToMany, @ManyToOne, ...).
+type EntityRelation struct {
+	Kind       string // OneToOne, OneToMany, ManyToOne, ManyToMany
+	Field      string
+	Target     string // Target entity class (simple name)
+	MappedBy   string // Owning field on the target for the inverse side
+	JoinColumn string
+}
+
+// PersistenceUnit represents a persistence-unit declared in a persistence.xml.
+type PersistenceUnit struct {
+	Name             string
+	TransactionType  string // JTA, RESOURCE_LOCAL
+	JTADataSource    string
+	NonJTADataSource string
+	Classes          []string // Listed managed classes (as written)
+
+	// Liberty dataSource the unit's data source name resolves to
+	DataSource         string // dataSource id (empty if unresolved)
+	DataSourceEvidence string
+
+	SourceFile string
+}
+
+// DataSourceName returns the data source JNDI name the unit uses, preferring the JTA one.
+func (u PersistenceUnit) DataSourceName() string {
+	if u.JTADataSource != "" {
+		return u.JTADataSource
+	}
+	return u.NonJTADataSource
+}
+
+// DataSource represents a Liberty <dataSource> definition.
+type DataSource struct {
+	ID           string // id attribute (the jndiName when no id is set)
+	JNDIName     string
+	DatabaseName string // From the vendor properties element
+	ServerName   string
+	URL          string
+}
+
+// SharedTable is a table mapped by entities of more than one service.
+type SharedTable struct {
+	Table      string
+	DataSource string   // Data source the services map it through (empty if not determined)
+	Services   []string // Sorted service names
+	Entities   []string // service:Entity (file:line)
+}
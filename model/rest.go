@@ -1,10 +1,13 @@
 package model
 
+import "strings"
+
 // RESTResource groups JAX-RS entry points by their implementation class.
 type RESTResource struct {
 	Name       string // Resource class name (e.g. ExampleApiV1)
 	SourceFile string // Java source file path
 	BasePath   string // Class-level @Path if known
+	Kind       string // jax-rs, servlet (see EntryPoint kinds)
 
 	// Phase F3.2 additions
 	Methods     []RESTMethod   // Flattened REST operations
@@ -24,6 +27,35 @@ type RESTResource struct {
 	InboundCalls  []RESTCall // Calls targeting this resource (if known)
 }
 
+// AuthAnnotations are the security annotations recorded on REST resources and methods.
+var AuthAnnotations = []string{"@RolesAllowed", AuthPermitAll, "@DenyAll", "@Authenticated", "@RequiresRole", "@Secured"}
+
+// AuthPermitAll is the only auth annotation that does not restrict callers.
+const AuthPermitAll = "@PermitAll"
+
+// RequiresAuth reports whether auth annotations restrict who may call an endpoint: any
+// annotation but @PermitAll does.
+func RequiresAuth(auth []string) bool {
+	for _, ann := range auth {
+		if ann != AuthPermitAll {
+			return true
+		}
+	}
+	return false
+}
+
+// MediaTypesKnown reports whether every @Consumes/@Produces value is a media type. Values
+// the analysis could not resolve (a constant of the application, for instance) are kept as
+// the expression written, which is not a media type.
+func MediaTypesKnown(types []string) bool {
+	for _, mt := range types {
+		if !strings.Contains(mt, "/") {
+			return false
+		}
+	}
+	return true
+}
+
 // RESTMethod represents a single REST operation mapped to a handler method.
 type RESTMethod struct {
 	HTTPMethod string // GET, POST, PUT, DELETE, etc.
@@ -31,4 +63,63 @@ type RESTMethod struct {
 	FullPath   string // BasePath + SubPath
 	Handler    string // e.g. ExampleApiV1.handleExample
 	SourceFile string
+
+	// web.xml security-constraint coverage
+	WebConstrained bool     // Covered by a security-constraint with an auth-constraint
+	WebRoles       []string // Roles required (empty when constrained means deny all)
+
+	// HTTP statuses the expanded handler flow can produce
+	Statuses []ResponseStatus
+
+	// Handler arguments bound to request parameters
+	Params []RESTParam
+
+	// Media types and auth annotations in effect for the method: method-level
+	// annotations, else the class-level ones
+	Consumes []string
+	Produces []string
+	Auth     []string
+	Roles    []string // @RolesAllowed roles; empty unless all are string literals
+}
+
+// RESTParam is a handler argument bound to a request parameter (@PathParam, @QueryParam,
+// @HeaderParam, @CookieParam, @FormParam).
+type RESTParam struct {
+	Name     string // Parameter name from the annotation
+	In       string // path, query, header, cookie, form (see ParamIn constants)
+	Type     string // Declared Java type (e.g. int, List<String>)
+	Default  string // @DefaultValue, if any
+	Evidence string // file:line of the handler declaration
+}
+
+// ParamIn values tell where a request parameter is read from.
+const (
+	ParamInPath   = "path"
+	ParamInQuery  = "query"
+	ParamInHeader = "header"
+	ParamInCookie = "cookie"
+	ParamInForm   = "form"
+)
+
+// ResponseStatus is an HTTP status a REST method can produce, with the line that produces it.
+type ResponseStatus struct {
+	Status   int    // 0 if not determined statically
+	Source   string // response, exception (see StatusSource constants)
+	Detail   string // Response expression or exception mapping
+	Evidence string // file:line
+}
+
+// StatusSource values describe how a status is produced.
+const (
+	StatusSourceResponse  = "response"  // Set through a Response builder
+	StatusSourceException = "exception" // Produced by an uncaught exception
+)
+
+// ExceptionMapper is a JAX-RS ExceptionMapper<T> provider.
+type ExceptionMapper struct {
+	Class      string // Provider class name
+	Exception  string // Mapped exception type (simple name)
+	Statuses   []int  // Statuses set in toResponse (empty if not determined)
+	SourceFile string
+	Evidence   string // Class declaration (file:line)
 }
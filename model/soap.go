@@ -0,0 +1,60 @@
+package model
+
+// SOAPEndpoint represents a JAX-WS web service implementation (@WebService).
+type SOAPEndpoint struct {
+	ServiceName       string // QName local part (serviceName attribute or <Class>Service)
+	Namespace         string // QName namespace (targetNamespace or derived from package)
+	PortName          string
+	EndpointInterface string
+	Class             string
+	SourceFile        string
+	Operations        []SOAPOperation
+	WSDLFile          string   // Matching WSDL (by service QName), if found
+	Addresses         []string // soap:address locations from the WSDL
+}
+
+// QName returns the service QName in {namespace}local form.
+func (e SOAPEndpoint) QName() string {
+	return "{" + e.Namespace + "}" + e.ServiceName
+}
+
+// SOAPOperation represents a single web service operation.
+type SOAPOperation struct {
+	Name    string // operationName or Java method name
+	Handler string // e.g. OrderServiceImpl.createOrder
+}
+
+// SOAPCall represents an outbound JAX-WS client invocation detected in source code.
+type SOAPCall struct {
+	FromService        string
+	FromClass          string
+	SourceFile         string
+	Evidence           string // file:line of the strongest signal
+	ServiceQName       string // {namespace}local, if known
+	Address            string // Literal endpoint address, if known
+	TargetService      string
+	TargetEndpoint     string // Target SOAPEndpoint ServiceName
+	Confidence         string
+	ResolutionScope    string
+	ResolutionEvidence string
+}
+
+// WSDLDefinition represents the service/port section of a WSDL document.
+type WSDLDefinition struct {
+	Path            string
+	TargetNamespace string
+	Services        []WSDLService
+}
+
+// WSDLService represents a wsdl:service element.
+type WSDLService struct {
+	Name  string
+	Ports []WSDLPort
+}
+
+// WSDLPort represents a wsdl:port and its SOAP address.
+type WSDLPort struct {
+	Name    string
+	Binding string
+	Address string
+}
@@ -0,0 +1,91 @@
+package model
+
+import (
+	"io/fs"
+	"path/filepath"
+	"strings"
+)
+
+// SourceTree is the file tree an analysis reads. Files are read from FS; Dir is the
+// directory the tree is reported as, so the path of a file in the model (and in evidence)
+// is Dir joined with its slash-separated name in FS.
+type SourceTree struct {
+	FS  fs.FS
+	Dir string
+}
+
+// Path returns the model path of a name in FS.
+func (t *SourceTree) Path(name string) string {
+	return filepath.Join(t.Dir, filepath.FromSlash(name))
+}
+
+// Name returns the name in FS of a model path.
+func (t *SourceTree) Name(path string) (string, error) {
+	rel, err := filepath.Rel(filepath.Clean(t.Dir), filepath.Clean(path))
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", &fs.PathError{Op: "open", Path: path, Err: fs.ErrNotExist}
+	}
+	return filepath.ToSlash(rel), nil
+}
+
+// Walk walks the tree below root like filepath.Walk, calling fn with model paths.
+func (t *SourceTree) Walk(root string, fn filepath.WalkFunc) error {
+	name, err := t.Name(root)
+	if err != nil {
+		return fn(root, nil, err)
+	}
+	return fs.WalkDir(t.FS, name, func(p string, d fs.DirEntry, err error) error {
+		var info fs.FileInfo
+		if d != nil {
+			if i, infoErr := d.Info(); infoErr == nil {
+				info = i
+			} else if err == nil {
+				err = infoErr
+			}
+		}
+		return fn(t.Path(p), info, err)
+	})
+}
+
+// Open opens the file at a model path.
+func (t *SourceTree) Open(path string) (fs.File, error) {
+	name, err := t.Name(path)
+	if err != nil {
+		return nil, err
+	}
+	return t.FS.Open(name)
+}
+
+// ReadFile reads the file at a model path.
+func (t *SourceTree) ReadFile(path string) ([]byte, error) {
+	name, err := t.Name(path)
+	if err != nil {
+		return nil, err
+	}
+	return fs.ReadFile(t.FS, name)
+}
+
+// Stat describes the file at a model path.
+func (t *SourceTree) Stat(path string) (fs.FileInfo, error) {
+	name, err := t.Name(path)
+	if err != nil {
+		return nil, err
+	}
+	return fs.Stat(t.FS, name)
+}
+
+// Glob returns the model paths of the files matching a pattern of model paths.
+func (t *SourceTree) Glob(pattern string) ([]string, error) {
+	name, err := t.Name(pattern)
+	if err != nil {
+		return nil, nil
+	}
+	matches, err := fs.Glob(t.FS, name)
+	if err != nil {
+		return nil, err
+	}
+	for i, m := range matches {
+		matches[i] = t.Path(m)
+	}
+	return matches, nil
+}
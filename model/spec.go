@@ -0,0 +1,64 @@
+package model
+
+// APISpec is the set of operations declared in an OpenAPI document.
+type APISpec struct {
+	Source     string   // Spec file
+	Version    string   // openapi version
+	Title      string   // info.title
+	Servers    []string // Server URLs
+	BasePath   string   // Path of the first server URL, if any
+	Operations []SpecOperation
+}
+
+// SpecOperation is an operation declared in an OpenAPI document.
+type SpecOperation struct {
+	HTTPMethod string
+	Path       string   // Path template as declared
+	Consumes   []string // Request body media types
+	Produces   []string // Response media types
+	Evidence   string   // file:line of the operation
+}
+
+// SpecDriftKind classifies a difference between the code and an OpenAPI document.
+type SpecDriftKind string
+
+const (
+	DriftMissingFromSpec  SpecDriftKind = "missing-from-spec" // Operation in code only
+	DriftMissingFromCode  SpecDriftKind = "missing-from-code" // Operation in the spec only
+	DriftMethodMismatch   SpecDriftKind = "method-mismatch"   // Path in both, HTTP methods differ
+	DriftConsumesMismatch SpecDriftKind = "consumes-mismatch" // Request media types differ
+	DriftProducesMismatch SpecDriftKind = "produces-mismatch" // Response media types differ
+)
+
+// SpecDrift is a difference between the code and an OpenAPI document.
+type SpecDrift struct {
+	Kind         SpecDriftKind
+	HTTPMethod   string // Empty for method mismatches, which concern a whole path
+	Path         string // Code path, or spec path when the operation is only in the spec
+	Handler      string // Code handler, if any
+	Details      []string
+	CodeEvidence string
+	SpecEvidence string
+}
+
+// SpecCheck is the result of comparing a service with an OpenAPI document.
+type SpecCheck struct {
+	Spec           string
+	Service        string
+	CodeOperations int
+	SpecOperations int
+	Matched        int
+	SpecPrefix     string // Prefix applied to spec paths to match code paths, if any
+	Drifts         []SpecDrift
+}
+
+// Count returns the number of drifts of a kind.
+func (c SpecCheck) Count(kind SpecDriftKind) int {
+	n := 0
+	for _, d := range c.Drifts {
+		if d.Kind == kind {
+			n++
+		}
+	}
+	return n
+}
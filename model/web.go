@@ -0,0 +1,46 @@
+package model
+
+// WebDescriptor represents a parsed WEB-INF/web.xml deployment descriptor.
+type WebDescriptor struct {
+	Path                string
+	Servlets            []Servlet
+	Filters             []ServletFilter
+	SecurityConstraints []SecurityConstraint
+	LoginConfig         LoginConfig
+
+	// JAX-RS servlet mapping (e.g. /api/* -> /api). Empty if absent or ambiguous.
+	JAXRSServlet string
+	JAXRSPrefix  string
+}
+
+// Servlet represents a servlet declaration and its url-pattern mappings.
+type Servlet struct {
+	Name        string
+	Class       string
+	URLPatterns []string
+	JAXRS       bool // Servlet dispatches to a JAX-RS application
+}
+
+// ServletFilter represents a filter declaration and its mappings.
+type ServletFilter struct {
+	Name         string
+	Class        string
+	URLPatterns  []string
+	ServletNames []string
+}
+
+// SecurityConstraint represents a web.xml security-constraint.
+type SecurityConstraint struct {
+	Name                string
+	URLPatterns         []string
+	HTTPMethods         []string // Empty means all methods
+	HTTPMethodOmissions []string
+	AuthConstraint      bool     // auth-constraint element present
+	Roles               []string // Empty with AuthConstraint means deny all
+}
+
+// LoginConfig represents the web.xml login-config element.
+type LoginConfig struct {
+	AuthMethod string
+	RealmName  string
+}
@@ -6,13 +6,18 @@ import (
 	"strings"
 )
 
-// GenerateFlowDiffMarkdown produces a markdown report for flow diffs.
-func GenerateFlowDiffMarkdown(diffs []model.FlowDiff, resourceName string) string {
+// GenerateFlowDiffMarkdown produces a markdown report for flow diffs. strict reports
+// diffs computed index by index rather than on aligned steps.
+func GenerateFlowDiffMarkdown(diffs []model.FlowDiff, resourceName string, strict bool) string {
 	var sb strings.Builder
 
 	sb.WriteString(fmt.Sprintf("# Flow Diff: %s\n\n", resourceName))
 	sb.WriteString("> **Analysis Mode:** Structural Execution-Flow Diff\n")
-	sb.WriteString("> **Comparison:** Ordered Step-by-Step\n\n")
+	if strict {
+		sb.WriteString("> **Comparison:** Ordered Step-by-Step (strict index)\n\n")
+	} else {
+		sb.WriteString("> **Comparison:** Aligned Step Sequence (longest common subsequence)\n\n")
+	}
 
 	for _, d := range diffs {
 		sb.WriteString(fmt.Sprintf("## Flow: %s\n", d.EntryPoint))
@@ -32,14 +37,50 @@ func GenerateFlowDiffMarkdown(diffs []model.FlowDiff, resourceName string) strin
 		// 4. Termination
 		renderDiffSection(&sb, "Termination", d.StepDiffs, model.FlowStepReturn, model.FlowStepUnexpanded)
 
-		// 5. Unchanged Summary (if not already mostly covered)
-		// We could add a full trace or just summarize changes.
-		// The prompt asks for these sections in this order.
+		// 5. Full trace with unchanged runs collapsed
+		renderDiffTrace(&sb, d.StepDiffs)
 	}
 
 	return sb.String()
 }
 
+// renderDiffTrace writes every step diff in flow order. Each run of unchanged steps is
+// collapsed into a single line.
+func renderDiffTrace(sb *strings.Builder, diffs []model.StepDiff) {
+	sb.WriteString("### Step Trace\n")
+	var run []model.StepDiff
+	flush := func() {
+		if len(run) == 0 {
+			return
+		}
+		first, last := run[0].After.Index, run[len(run)-1].After.Index
+		if first == last {
+			sb.WriteString(fmt.Sprintf("= 1 unchanged step (#%d)\n", first))
+		} else {
+			sb.WriteString(fmt.Sprintf("= %d unchanged steps (#%d-#%d)\n", len(run), first, last))
+		}
+		run = nil
+	}
+	for _, d := range diffs {
+		switch d.Kind {
+		case model.StepUnchanged:
+			run = append(run, d)
+			continue
+		case model.StepAdded:
+			flush()
+			sb.WriteString(fmt.Sprintf("+ #%d %s: %s\n", d.After.Index, d.After.Kind, d.After.Description))
+		case model.StepRemoved:
+			flush()
+			sb.WriteString(fmt.Sprintf("- #%d %s: %s\n", d.Before.Index, d.Before.Kind, d.Before.Description))
+		case model.StepModified:
+			flush()
+			sb.WriteString(fmt.Sprintf("~ #%d %s: %s -> %s\n", d.After.Index, d.After.Kind, d.Before.Description, d.After.Description))
+		}
+	}
+	flush()
+	sb.WriteString("\n")
+}
+
 func renderDiffSection(sb *strings.Builder, title string, diffs []model.StepDiff, kinds ...model.FlowStepKind) {
 	relevant := []model.StepDiff{}
 	for _, d := range diffs {
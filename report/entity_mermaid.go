@@ -0,0 +1,107 @@
+package report
+
+import (
+	"fmt"
+	"jz/model"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// erTypeRegex matches characters that are not allowed in an erDiagram attribute type.
+var erTypeRegex = regexp.MustCompile(`[^\w]`)
+
+// relationCardinality maps JPA relationship kinds to erDiagram relationship markers.
+var relationCardinality = map[string]string{
+	"OneToOne":   "||--||",
+	"OneToMany":  "||--o{",
+	"ManyToOne":  "}o--||",
+	"ManyToMany": "}o--o{",
+}
+
+// GenerateEntityMermaid creates an erDiagram of the JPA entities of a service. Entities are
+// labeled with their table; tables that other services map as well are marked as shared.
+// The inverse side of a bidirectional relationship is drawn once, from the owning side.
+func GenerateEntityMermaid(svc model.Service, shared []model.SharedTable) string {
+	var sb strings.Builder
+	sb.WriteString("erDiagram\n")
+	sb.WriteString(fmt.Sprintf("\t%%%% Entities of %s\n", svc.Name))
+
+	for _, e := range svc.Entities {
+		label := fmt.Sprintf("%s: %s", e.Name, e.Table)
+		var others []string
+		for _, t := range shared {
+			if strings.EqualFold(t.Table, e.Table) && slices.Contains(t.Services, svc.Name) {
+				for _, o := range otherServices(t.Services, svc.Name) {
+					if !slices.Contains(others, o) {
+						others = append(others, o)
+					}
+				}
+			}
+		}
+		if len(others) > 0 {
+			sort.Strings(others)
+			label += " (shared with " + strings.Join(others, ", ") + ")"
+		}
+		sb.WriteString(fmt.Sprintf("\t%s[\"%s\"] {\n", sanitize(e.Name), label))
+		for _, c := range e.Columns {
+			key := ""
+			if c.ID {
+				key = " PK"
+			}
+			sb.WriteString(fmt.Sprintf("\t\t%s %s%s \"%s\"\n", erTypeRegex.ReplaceAllString(c.Type, "_"), c.Field, key, c.Column))
+		}
+		sb.WriteString("\t}\n")
+	}
+
+	for _, e := range svc.Entities {
+		for _, r := range e.Relations {
+			if r.MappedBy != "" && hasOwningSide(svc.Entities, r.Target, r.MappedBy) {
+				continue
+			}
+			label := r.Field
+			if r.JoinColumn != "" {
+				label += " (" + r.JoinColumn + ")"
+			}
+			sb.WriteString(fmt.Sprintf("\t%s %s %s : \"%s\"\n", sanitize(e.Name), relationCardinality[r.Kind], sanitize(entityName(svc.Entities, r.Target)), label))
+		}
+	}
+
+	return sb.String()
+}
+
+// hasOwningSide reports whether the target entity declares the owning field of an inverse relationship.
+func hasOwningSide(entities []model.Entity, target, field string) bool {
+	for _, e := range entities {
+		if e.Class != target {
+			continue
+		}
+		for _, r := range e.Relations {
+			if r.Field == field {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// entityName returns the entity name of a class, or the class name when it is not an entity of the service.
+func entityName(entities []model.Entity, class string) string {
+	for _, e := range entities {
+		if e.Class == class {
+			return e.Name
+		}
+	}
+	return class
+}
+
+func otherServices(services []string, self string) []string {
+	var others []string
+	for _, s := range services {
+		if s != self {
+			others = append(others, s)
+		}
+	}
+	return others
+}
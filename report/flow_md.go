@@ -27,13 +27,13 @@ func GenerateFlowMarkdown(flows []model.ExecutionFlow, resourceName string, path
 		hasGuards := "No"
 		hasEarlyReturn := "No"
 		hasOutbound := "No"
-		for i, s := range f.Steps {
+		if len(earlyExits(f.Blocks)) > 0 {
+			hasEarlyReturn = "Yes"
+		}
+		for _, s := range handlerSteps(f) {
 			if s.Kind == model.FlowStepCondition {
 				hasGuards = "Yes"
 			}
-			if s.Kind == model.FlowStepReturn && i < len(f.Steps)-1 {
-				hasEarlyReturn = "Yes"
-			}
 			if s.Kind == model.FlowStepOutbound {
 				hasOutbound = "Yes"
 			}
@@ -45,7 +45,7 @@ func GenerateFlowMarkdown(flows []model.ExecutionFlow, resourceName string, path
 	// 4. Explicit "No Outbound Calls" Signal (Global)
 	totalOutbound := 0
 	for _, f := range flows {
-		for _, s := range f.Steps {
+		for _, s := range handlerSteps(f) {
 			if s.Kind == model.FlowStepOutbound {
 				totalOutbound++
 			}
@@ -69,70 +69,26 @@ func GenerateFlowMarkdown(flows []model.ExecutionFlow, resourceName string, path
 
 		// 4. Explicit "No Outbound Calls" Signal (Per-flow)
 		flowOutbound := false
-		for _, s := range f.Steps {
+		for _, s := range handlerSteps(f) {
 			if s.Kind == model.FlowStepOutbound {
 				flowOutbound = true
 				break
 			}
 		}
 
-		// 2. Logical Grouping of Steps (Visual Headers)
+		// 2. Logical Grouping of Steps (Visual Headers) with nested branches
 		currentGroup := ""
-
-		for _, s := range f.Steps {
-			newGroup := ""
-			switch s.Kind {
-			case model.FlowStepEntry:
-				newGroup = "Entry"
-			case model.FlowStepCondition:
-				newGroup = "Guard Conditions"
-			case model.FlowStepOutbound:
-				newGroup = "Outbound Calls"
-			case model.FlowStepCall:
-				newGroup = "Method Execution"
-			case model.FlowStepReturn:
-				newGroup = "Early Exit / Return"
-			case model.FlowStepUnexpanded:
-				newGroup = "Scope Limits"
-			}
-
-			if newGroup != "" && newGroup != currentGroup {
-				sb.WriteString(fmt.Sprintf("### %s\n\n", newGroup))
-				currentGroup = newGroup
-			}
-
-			kindLabel := strings.ToUpper(string(s.Kind))
-			description := s.Description
-			// 3. Gating vs Core Path Distinction
-			if s.Kind == model.FlowStepCondition {
-				description = "**Guard:** " + description
-			}
-
-			sb.WriteString(fmt.Sprintf("%d. **%s**: %s\n", s.Index, kindLabel, description))
-
-			if s.ToMethod != "" {
-				sb.WriteString(fmt.Sprintf("   - **Target:** `%s`", s.ToMethod))
-				if s.ResolutionScope != "" {
-					sb.WriteString(fmt.Sprintf(" (%s)", s.ResolutionScope))
-				}
-				sb.WriteString("\n")
-			}
-
-			sb.WriteString(fmt.Sprintf("   - **Evidence:** `%s` [confidence: %s]\n", s.Evidence, s.Confidence))
-
-			if s.ResolutionScope == model.ResolutionUnresolved && s.Kind == model.FlowStepOutbound {
-				sb.WriteString("   - ⚠️ *Note: This outbound call could not be resolved to a known resource.*\n")
-			}
-			sb.WriteString("\n")
-		}
+		renderFlowNodes(&sb, f.Blocks, "", &currentGroup)
 
 		if !flowOutbound {
 			sb.WriteString("_No outbound REST calls detected in this handler._\n\n")
 		}
 
 		// 1. Flow Completion Signaling
-		lastStep := f.Steps[len(f.Steps)-1]
-		if lastStep.Kind != model.FlowStepReturn {
+		last := f.Blocks[len(f.Blocks)-1]
+		if last.Step.Kind == model.FlowStepThrow {
+			sb.WriteString("> ⚠️ **End Note:** Flow ends by throwing an exception (see Error Statuses).\n\n")
+		} else if !endsWithReturn(last) {
 			sb.WriteString("> 💡 **End Note:** Flow may continue into helper services, injected components, or external layers not expanded in this view (reached end of analyzed handler without explicit return).\n\n")
 		} else {
 			sb.WriteString("> ✅ **End Note:** Flow completed with a detected return statement.\n\n")
@@ -146,7 +102,7 @@ func GenerateFlowMarkdown(flows []model.ExecutionFlow, resourceName string, path
 	gatingFound := false
 	sb.WriteString("### Gating & Guardrails\n")
 	for _, f := range flows {
-		for _, s := range f.Steps {
+		for _, s := range handlerSteps(f) {
 			if s.Kind == model.FlowStepCondition {
 				sb.WriteString(fmt.Sprintf("- Flow `%s` is gated by: `%s`\n", f.EntryPoint, s.Description))
 				gatingFound = true
@@ -162,11 +118,9 @@ func GenerateFlowMarkdown(flows []model.ExecutionFlow, resourceName string, path
 	exitFound := false
 	sb.WriteString("### Early Exits\n")
 	for _, f := range flows {
-		for i, s := range f.Steps {
-			if s.Kind == model.FlowStepReturn && i < len(f.Steps)-1 {
-				sb.WriteString(fmt.Sprintf("- Flow `%s` has an early exit: `%s`\n", f.EntryPoint, s.Description))
-				exitFound = true
-			}
+		for _, s := range earlyExits(f.Blocks) {
+			sb.WriteString(fmt.Sprintf("- Flow `%s` has an early exit: `%s`\n", f.EntryPoint, s.Description))
+			exitFound = true
 		}
 	}
 	if !exitFound {
@@ -174,12 +128,227 @@ func GenerateFlowMarkdown(flows []model.ExecutionFlow, resourceName string, path
 	}
 	sb.WriteString("\n")
 
+	// Error statuses produced by uncaught throws
+	statusFound := false
+	sb.WriteString("### Error Statuses\n")
+	for _, f := range flows {
+		for _, es := range f.ErrorStatuses {
+			status := "unknown"
+			if es.Status != 0 {
+				status = fmt.Sprintf("%d", es.Status)
+			}
+			sb.WriteString(fmt.Sprintf("- Flow `%s` can produce `%s`: %s (`%s`)\n", f.EntryPoint, status, es.Mapping, es.Evidence))
+			statusFound = true
+		}
+	}
+	if !statusFound {
+		sb.WriteString("- No uncaught exceptions detected.\n")
+	}
+	sb.WriteString("\n")
+
 	// Limitations Note
 	sb.WriteString("## Limitations (AST-lite)\n")
 	sb.WriteString("- Logic is extracted via line-based lexical analysis.\n")
 	sb.WriteString("- Data propagation across variables or loops is not tracked.\n")
 	sb.WriteString("- Complex boolean expressions may be truncated.\n")
-	sb.WriteString("- Only same-file internal methods are expanded.\n")
+	sb.WriteString("- Calls are expanded within the same file and through fields whose type maps to a unique implementation.\n")
+	for _, f := range flows {
+		if len(handlerSteps(f)) < len(f.Steps) {
+			sb.WriteString("- Steps of followed cross-service flows are not counted in the summary and observations.\n")
+			break
+		}
+	}
 
 	return sb.String()
 }
+
+// renderFlowNodes writes flow steps as numbered items. Top-level steps are grouped under
+// visual headers; nested steps are indented under the branch or container that holds them.
+func renderFlowNodes(sb *strings.Builder, nodes []model.FlowNode, indent string, currentGroup *string) {
+	for _, n := range nodes {
+		s := n.Step
+		if indent == "" {
+			newGroup := ""
+			switch s.Kind {
+			case model.FlowStepEntry:
+				newGroup = "Entry"
+			case model.FlowStepCondition:
+				newGroup = "Guard Conditions"
+			case model.FlowStepOutbound:
+				newGroup = "Outbound Calls"
+			case model.FlowStepCall:
+				newGroup = "Method Execution"
+			case model.FlowStepReturn:
+				newGroup = "Early Exit / Return"
+			case model.FlowStepUnexpanded:
+				newGroup = "Scope Limits"
+			case model.FlowStepTry, model.FlowStepCatch, model.FlowStepFinally, model.FlowStepThrow:
+				newGroup = "Exception Handling"
+			case model.FlowStepLoop, model.FlowStepSwitch:
+				newGroup = "Control Flow"
+			case model.FlowStepLambda:
+				newGroup = "Deferred Execution"
+			case model.FlowStepPersistence:
+				newGroup = "Persistence"
+			}
+
+			if newGroup != "" && newGroup != *currentGroup {
+				sb.WriteString(fmt.Sprintf("### %s\n\n", newGroup))
+				*currentGroup = newGroup
+			}
+		}
+
+		kindLabel := strings.ToUpper(string(s.Kind))
+		description := s.Description
+		// 3. Gating vs Core Path Distinction
+		if s.Kind == model.FlowStepCondition {
+			description = "**Guard:** " + description
+		}
+
+		sub := indent + "   "
+		sb.WriteString(fmt.Sprintf("%s%d. **%s**: %s\n", indent, s.Index, kindLabel, description))
+		if s.Kind == model.FlowStepEntry && s.Service != "" {
+			sb.WriteString(fmt.Sprintf("%s- **Service:** `%s`\n", sub, s.Service))
+		}
+
+		if s.ToMethod != "" {
+			sb.WriteString(fmt.Sprintf("%s- **Target:** `%s`", sub, s.ToMethod))
+			if s.ResolutionScope != "" {
+				sb.WriteString(fmt.Sprintf(" (%s)", s.ResolutionScope))
+			}
+			sb.WriteString("\n")
+		}
+
+		sb.WriteString(fmt.Sprintf("%s- **Evidence:** `%s` [confidence: %s]\n", sub, s.Evidence, s.Confidence))
+		if s.ResolutionEvidence != "" {
+			sb.WriteString(fmt.Sprintf("%s- **Resolution:** %s\n", sub, s.ResolutionEvidence))
+		}
+		if s.HTTPStatus != 0 {
+			sb.WriteString(fmt.Sprintf("%s- **HTTP Status:** %d\n", sub, s.HTTPStatus))
+		}
+		if s.CaughtAt != "" {
+			sb.WriteString(fmt.Sprintf("%s- **Caught at:** `%s`\n", sub, s.CaughtAt))
+		}
+		if len(s.Entities) > 0 {
+			sb.WriteString(fmt.Sprintf("%s- **Entities:** `%s`\n", sub, strings.Join(s.Entities, "`, `")))
+		}
+		if len(s.Tables) > 0 {
+			sb.WriteString(fmt.Sprintf("%s- **Tables:** `%s`\n", sub, strings.Join(s.Tables, "`, `")))
+		}
+		if s.ExecutionScope != "" {
+			sb.WriteString(fmt.Sprintf("%s- %s\n", sub, scopeNote(s)))
+		}
+
+		if s.ResolutionScope == model.ResolutionUnresolved && s.Kind == model.FlowStepOutbound {
+			sb.WriteString(fmt.Sprintf("%s- ⚠️ *Note: This outbound call could not be resolved to a known resource.*\n", sub))
+		}
+		sb.WriteString("\n")
+
+		if len(n.Children) > 0 {
+			sb.WriteString(fmt.Sprintf("%s- **%s:**\n\n", sub, blockLabel(s.Kind)))
+			renderFlowNodes(sb, n.Children, sub+"  ", currentGroup)
+		}
+		if len(n.Else) > 0 {
+			label := "Otherwise"
+			if s.Kind == model.FlowStepTry {
+				label = "Handlers"
+			}
+			sb.WriteString(fmt.Sprintf("%s- **%s:**\n\n", sub, label))
+			renderFlowNodes(sb, n.Else, sub+"  ", currentGroup)
+		}
+	}
+}
+
+// blockLabel names the nested block of a step kind.
+func blockLabel(kind model.FlowStepKind) string {
+	switch kind {
+	case model.FlowStepCondition:
+		return "Then"
+	case model.FlowStepCall:
+		return "Expanded"
+	case model.FlowStepTry:
+		return "Try"
+	case model.FlowStepSwitch:
+		return "Cases"
+	case model.FlowStepOutbound:
+		return "Followed"
+	default:
+		return "Body"
+	}
+}
+
+// handlerSteps returns the steps of a flow that run in the handler's own service, leaving
+// out the steps of followed cross-service flows.
+func handlerSteps(f model.ExecutionFlow) []model.FlowStep {
+	var steps []model.FlowStep
+	for _, s := range f.Steps {
+		if s.Service == "" {
+			steps = append(steps, s)
+		}
+	}
+	return steps
+}
+
+// scopeNote describes the execution scope of a step: the scope a lambda step opens for its
+// body, or the scope a nested step runs in.
+func scopeNote(s model.FlowStep) string {
+	if s.Kind == model.FlowStepLambda {
+		if s.ExecutionScope == model.FlowScopeAsync {
+			return "**Body scope:** async (runs outside the request thread)"
+		}
+		return "**Body scope:** lambda (may run later, repeatedly or not at all)"
+	}
+	if s.ExecutionScope == model.FlowScopeAsync {
+		return "**Scope:** in async task"
+	}
+	return "**Scope:** in lambda"
+}
+
+// endsWithReturn reports whether the node returns on every path: a return, or a condition
+// whose then- and else-branches both end with a return (e.g. a split ternary return).
+func endsWithReturn(n model.FlowNode) bool {
+	switch n.Step.Kind {
+	case model.FlowStepReturn:
+		return true
+	case model.FlowStepCondition:
+		return len(n.Children) > 0 && len(n.Else) > 0 &&
+			endsWithReturn(n.Children[len(n.Children)-1]) && endsWithReturn(n.Else[len(n.Else)-1])
+	}
+	return false
+}
+
+// earlyExits returns the returns that leave the handler before its last top-level step:
+// returns inside condition branches, switch cases, loops or exception handlers, returns inside a try block
+// followed by further steps and top-level returns followed by further steps.
+// Returns of expanded callees go back to the caller and are not exits.
+func earlyExits(nodes []model.FlowNode) []model.FlowStep {
+	var exits []model.FlowStep
+	var walk func(nodes []model.FlowNode, inBranch bool)
+	walk = func(nodes []model.FlowNode, inBranch bool) {
+		for i, n := range nodes {
+			if n.Step.Kind == model.FlowStepReturn && (inBranch || i < len(nodes)-1) {
+				exits = append(exits, n.Step)
+			}
+			switch n.Step.Kind {
+			case model.FlowStepCondition:
+				// A final condition returning on both branches ends the handler normally
+				branch := inBranch || i < len(nodes)-1 || !endsWithReturn(n)
+				walk(n.Children, branch)
+				walk(n.Else, branch)
+			case model.FlowStepTry:
+				walk(n.Children, inBranch || i < len(nodes)-1)
+				for _, h := range n.Else {
+					walk(h.Children, true)
+				}
+			case model.FlowStepLoop:
+				walk(n.Children, true)
+			case model.FlowStepSwitch:
+				for _, c := range n.Children {
+					walk(c.Children, true)
+				}
+			}
+		}
+	}
+	walk(nodes, false)
+	return exits
+}
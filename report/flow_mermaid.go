@@ -7,6 +7,11 @@ import (
 )
 
 // GenerateFlowMermaid creates a Mermaid flow diagram for execution steps.
+// Conditions fork into THEN/ELSE branches that merge into the following step;
+// returns end the flow unless they return from an expanded callee to its caller.
+// Try blocks fork into their catch handlers and join in finally; throws end the flow
+// with their HTTP status unless an enclosing catch handles them. Loops draw a loop-back
+// edge from the end of their body; switches fork into one edge per case.
 func GenerateFlowMermaid(flows []model.ExecutionFlow, resourceName string, compact bool) string {
 	var sb strings.Builder
 	sb.WriteString("graph TD\n")
@@ -15,84 +20,40 @@ func GenerateFlowMermaid(flows []model.ExecutionFlow, resourceName string, compa
 		flowID := fmt.Sprintf("Flow_%d", i)
 		sb.WriteString(fmt.Sprintf("\tsubgraph %s [%s]\n", flowID, f.EntryPoint))
 
-		var lastNode string
-		var guardChain []string
-
-		for j, s := range f.Steps {
-			// Compact guards if enabled
-			if compact && s.Kind == model.FlowStepCondition {
-				guardChain = append(guardChain, s.Description)
-				// If next step is not a guard, or this is the last step, flush the chain
-				if j == len(f.Steps)-1 || f.Steps[j+1].Kind != model.FlowStepCondition {
-					nodeID := fmt.Sprintf("F%d_G_%d", i, j)
-					label := strings.Join(guardChain, " && ")
-					if len(guardChain) > 1 {
-						label = "GUARDS: " + label
-					}
-					sb.WriteString(fmt.Sprintf("\t\t%s{{\"%s\"}}\n", nodeID, label))
-					if lastNode != "" {
-						sb.WriteString(fmt.Sprintf("\t\t%s -.->|CONDITION| %s\n", lastNode, nodeID))
-					}
-					lastNode = nodeID
-					guardChain = nil
-				}
-				continue
-			}
-
-			nodeID := fmt.Sprintf("F%d_S%d", i, j)
-			label := s.Description
-
-			// 6. Explicit flow termination nodes logic handled later
-			switch s.Kind {
-			case model.FlowStepCondition:
-				label = "{{" + label + "}}"
-			case model.FlowStepOutbound:
-				label = "[[" + label + "]]" // Double bracket for outbound
-			case model.FlowStepCall:
-				label = "[" + label + "]"
-			case model.FlowStepUnexpanded:
-				label = "[/ " + label + " /]" // Parallelogram for unexpanded/external
-			case model.FlowStepReturn:
-				label = "((" + label + "))"
-			}
-
-			sb.WriteString(fmt.Sprintf("\t\t%s(\"%s\")\n", nodeID, label))
-
-			if lastNode != "" {
-				arrow := "-->"
-				if s.Kind == model.FlowStepCondition {
-					arrow = "-.->"
-				}
-
-				edgeLabel := fmt.Sprintf("%s [%s]", strings.ToUpper(string(s.Kind)), s.Confidence)
-
-				// Special arrow for outbound calls based on resolution
-				if s.Kind == model.FlowStepOutbound {
-					switch s.ResolutionScope {
-					case model.ResolutionSameService:
-						arrow = "-->"
-					case model.ResolutionCrossService:
-						arrow = "==>"
-					default:
-						arrow = "-.->"
-					}
-				}
-
-				sb.WriteString(fmt.Sprintf("\t\t%s %s|%s| %s\n", lastNode, arrow, edgeLabel, nodeID))
+		fr := &flowMermaidRenderer{sb: &sb, flow: i, compact: compact}
+		open, exits := fr.emit(f.Blocks, nil)
+		var returns, throws []flowExit
+		for _, e := range exits {
+			if e.throw {
+				throws = append(throws, e)
+			} else {
+				returns = append(returns, e)
 			}
-			lastNode = nodeID
 		}
 
 		// 6. Explicit Flow Termination Nodes
-		if len(f.Steps) > 0 {
-			lastStep := f.Steps[len(f.Steps)-1]
+		if len(returns) > 0 {
 			termID := fmt.Sprintf("F%d_TERM", i)
-			if lastStep.Kind == model.FlowStepReturn {
-				sb.WriteString(fmt.Sprintf("\t\t%s(X \"End (Return)\")\n", termID))
-				sb.WriteString(fmt.Sprintf("\t\t%s --> %s\n", lastNode, termID))
-			} else {
-				sb.WriteString(fmt.Sprintf("\t\t%s[/ \"End (Unexpanded)\" /]\n", termID))
-				sb.WriteString(fmt.Sprintf("\t\t%s -.->|SCOPE LIMIT| %s\n", lastNode, termID))
+			sb.WriteString(fmt.Sprintf("\t\t%s(X \"End (Return)\")\n", termID))
+			for _, r := range returns {
+				fr.edge(r, termID, "-->", "")
+			}
+		}
+		if len(throws) > 0 {
+			termID := fmt.Sprintf("F%d_THROW", i)
+			sb.WriteString(fmt.Sprintf("\t\t%s(X \"End (Exception)\")\n", termID))
+			for _, t := range throws {
+				fr.edge(t, termID, "-.->", "")
+			}
+		}
+		if len(open) > 0 && len(f.Blocks) > 0 {
+			termID := fmt.Sprintf("F%d_OPEN", i)
+			if len(exits) == 0 {
+				termID = fmt.Sprintf("F%d_TERM", i)
+			}
+			sb.WriteString(fmt.Sprintf("\t\t%s[/ \"End (Unexpanded)\" /]\n", termID))
+			for _, o := range open {
+				fr.edge(o, termID, "-.->", "SCOPE LIMIT")
 			}
 		}
 
@@ -110,3 +71,270 @@ func GenerateFlowMermaid(flows []model.ExecutionFlow, resourceName string, compa
 
 	return sb.String()
 }
+
+// flowExit is a pending edge from a rendered node to whatever comes next.
+type flowExit struct {
+	from  string
+	label string // Branch label (THEN/ELSE); empty for sequential edges
+	throw bool   // Exit by an uncaught exception
+}
+
+// flowMermaidRenderer draws one flow tree.
+type flowMermaidRenderer struct {
+	sb      *strings.Builder
+	flow    int
+	compact bool
+	callee  int               // > 0 while rendering an expanded callee
+	catches map[string]string // Catch evidence -> node ID, for the enclosing try blocks
+}
+
+// emit renders a sequence of nodes entered from the given exits. It returns the exits
+// that fall through to the next step and the returns and throws that end the flow.
+func (r *flowMermaidRenderer) emit(nodes []model.FlowNode, in []flowExit) ([]flowExit, []flowExit) {
+	cur := in
+	var returns []flowExit
+
+	for _, n := range nodes {
+		s := n.Step
+		nodeID := fmt.Sprintf("F%d_S%d", r.flow, s.Index-1)
+
+		// Compact nested guards: if (a) { if (b) { ... } } renders as one guard node
+		if r.compact && s.Kind == model.FlowStepCondition {
+			var chain []string
+			for {
+				chain = append(chain, n.Step.Description)
+				if len(n.Else) == 0 && len(n.Children) == 1 && n.Children[0].Step.Kind == model.FlowStepCondition && len(n.Children[0].Else) == 0 {
+					n = n.Children[0]
+					continue
+				}
+				break
+			}
+			nodeID = fmt.Sprintf("F%d_G_%d", r.flow, s.Index-1)
+			label := strings.Join(chain, " && ")
+			if len(chain) > 1 {
+				label = "GUARDS: " + label
+			}
+			r.sb.WriteString(fmt.Sprintf("\t\t%s{{\"%s\"}}\n", nodeID, label))
+			for _, e := range cur {
+				r.edge(e, nodeID, "-.->", "CONDITION")
+			}
+			cur, returns = r.branches(n, nodeID, returns)
+			continue
+		}
+
+		label := s.Description
+
+		// 6. Explicit flow termination nodes logic handled later
+		switch s.Kind {
+		case model.FlowStepCondition, model.FlowStepSwitch:
+			label = "{{" + label + "}}"
+		case model.FlowStepOutbound:
+			label = "[[" + label + "]]" // Double bracket for outbound
+		case model.FlowStepCall:
+			label = "[" + label + "]"
+		case model.FlowStepUnexpanded:
+			label = "[/ " + label + " /]" // Parallelogram for unexpanded/external
+		case model.FlowStepReturn, model.FlowStepThrow:
+			label = "((" + label + "))"
+		case model.FlowStepTry, model.FlowStepCatch, model.FlowStepFinally, model.FlowStepLoop:
+			label = "[" + label + "]"
+		case model.FlowStepLambda:
+			label = "[" + strings.ReplaceAll(label, "\"", "'") + "]"
+		case model.FlowStepPersistence:
+			label = "[(" + strings.ReplaceAll(label, "\"", "'") + ")]" // Cylinder for database access
+		case model.FlowStepEntry:
+			if s.Service != "" {
+				label = s.Service + ": " + label
+			}
+		}
+
+		r.sb.WriteString(fmt.Sprintf("\t\t%s(\"%s\")\n", nodeID, label))
+
+		for _, e := range cur {
+			arrow := "-->"
+			if s.Kind == model.FlowStepCondition || s.Kind == model.FlowStepSwitch {
+				arrow = "-.->"
+			}
+
+			// Special arrow for outbound calls based on resolution
+			if s.Kind == model.FlowStepOutbound {
+				switch s.ResolutionScope {
+				case model.ResolutionSameService:
+					arrow = "-->"
+				case model.ResolutionCrossService, model.ResolutionExternal:
+					arrow = "==>"
+				default:
+					arrow = "-.->"
+				}
+			}
+
+			edgeLabel := fmt.Sprintf("%s [%s]", strings.ToUpper(string(s.Kind)), s.Confidence)
+			if e.label != "" {
+				edgeLabel = e.label
+			}
+			r.sb.WriteString(fmt.Sprintf("\t\t%s %s|%s| %s\n", e.from, arrow, edgeLabel, nodeID))
+		}
+
+		switch {
+		case s.Kind == model.FlowStepCondition:
+			cur, returns = r.branches(n, nodeID, returns)
+		case s.Kind == model.FlowStepTry:
+			cur, returns = r.tryBlock(n, nodeID, returns)
+		case s.Kind == model.FlowStepLoop:
+			// Body iterates and loops back; the loop exits when its condition fails
+			open, rets := r.emit(n.Children, []flowExit{{from: nodeID, label: "ITERATE"}})
+			for _, o := range open {
+				r.edge(o, nodeID, "-.->", "NEXT")
+			}
+			returns = append(returns, rets...)
+			cur = []flowExit{{from: nodeID, label: "DONE"}}
+		case s.Kind == model.FlowStepSwitch:
+			cur, returns = r.switchBlock(n, nodeID, returns)
+		case s.Kind == model.FlowStepLambda:
+			// Returns in the body return from the lambda; an async body runs apart from the
+			// handler, which continues at once
+			r.callee++
+			open, rets := r.emit(n.Children, []flowExit{{from: nodeID, label: strings.ToUpper(s.ExecutionScope)}})
+			r.callee--
+			if s.ExecutionScope == model.FlowScopeAsync || len(n.Children) == 0 {
+				cur = []flowExit{{from: nodeID}}
+			} else {
+				cur = open
+				returns = append(returns, rets...)
+			}
+		case s.Kind == model.FlowStepThrow:
+			if id, ok := r.catches[s.CaughtAt]; ok && s.CaughtAt != "" {
+				r.edge(flowExit{from: nodeID}, id, "-.->", "CAUGHT")
+			} else {
+				label := "THROW"
+				if s.HTTPStatus != 0 {
+					label = fmt.Sprintf("THROW %d", s.HTTPStatus)
+				}
+				returns = append(returns, flowExit{from: nodeID, label: label, throw: true})
+			}
+			cur = nil
+		case s.Kind == model.FlowStepOutbound && len(n.Children) > 0:
+			// The followed flow answers the call: its returns and throws continue in the caller
+			r.callee++
+			open, rets := r.emit(n.Children, []flowExit{{from: nodeID, label: "FOLLOW"}})
+			r.callee--
+			cur = open
+			for _, e := range rets {
+				cur = append(cur, flowExit{from: e.from, label: e.label})
+			}
+		case s.Kind == model.FlowStepReturn:
+			if r.callee > 0 {
+				// Returning from a callee continues in the caller
+				cur = []flowExit{{from: nodeID}}
+			} else {
+				returns = append(returns, flowExit{from: nodeID})
+				cur = nil
+			}
+		case len(n.Children) > 0:
+			if s.Kind == model.FlowStepCall {
+				r.callee++
+			}
+			open, rets := r.emit(n.Children, []flowExit{{from: nodeID}})
+			if s.Kind == model.FlowStepCall {
+				r.callee--
+			}
+			cur = open
+			returns = append(returns, rets...)
+		default:
+			cur = []flowExit{{from: nodeID}}
+		}
+	}
+
+	return cur, returns
+}
+
+// branches renders the then/else children of a condition node. A missing else-branch
+// falls through from the condition itself.
+func (r *flowMermaidRenderer) branches(n model.FlowNode, nodeID string, returns []flowExit) ([]flowExit, []flowExit) {
+	thenOpen, thenRets := r.emit(n.Children, []flowExit{{from: nodeID, label: "THEN"}})
+	elseOpen := []flowExit{{from: nodeID, label: "ELSE"}}
+	var elseRets []flowExit
+	if len(n.Else) > 0 {
+		elseOpen, elseRets = r.emit(n.Else, elseOpen)
+	}
+	returns = append(returns, thenRets...)
+	returns = append(returns, elseRets...)
+	return append(thenOpen, elseOpen...), returns
+}
+
+// switchBlock renders one branch per case, labeled with the case labels. Without a default
+// case, the switch also falls through when no case matches.
+func (r *flowMermaidRenderer) switchBlock(n model.FlowNode, nodeID string, returns []flowExit) ([]flowExit, []flowExit) {
+	var open []flowExit
+	hasDefault := false
+	for _, c := range n.Children {
+		label := strings.TrimPrefix(c.Step.Description, "Case: ")
+		if c.Step.Description == "Default" {
+			label = "default"
+			hasDefault = true
+		}
+		label = strings.NewReplacer(`"`, "'", "|", "/").Replace(label)
+		cOpen, cRets := r.emit(c.Children, []flowExit{{from: nodeID, label: label}})
+		open = append(open, cOpen...)
+		returns = append(returns, cRets...)
+	}
+	if !hasDefault {
+		open = append(open, flowExit{from: nodeID, label: "NO MATCH"})
+	}
+	return open, returns
+}
+
+// tryBlock renders the body of a try node and its handlers. Catch handlers are entered
+// from the try node; finally joins the exits of the body and of the catch handlers, or
+// hangs off the try node when all of them return or throw.
+func (r *flowMermaidRenderer) tryBlock(n model.FlowNode, nodeID string, returns []flowExit) ([]flowExit, []flowExit) {
+	if r.catches == nil {
+		r.catches = make(map[string]string)
+	}
+	var added []string
+	for _, h := range n.Else {
+		if _, ok := r.catches[h.Step.Evidence]; h.Step.Kind == model.FlowStepCatch && !ok {
+			r.catches[h.Step.Evidence] = fmt.Sprintf("F%d_S%d", r.flow, h.Step.Index-1)
+			added = append(added, h.Step.Evidence)
+		}
+	}
+	open, rets := r.emit(n.Children, []flowExit{{from: nodeID}})
+	returns = append(returns, rets...)
+	for _, e := range added {
+		delete(r.catches, e)
+	}
+
+	var finally []model.FlowNode
+	for _, h := range n.Else {
+		if h.Step.Kind == model.FlowStepFinally {
+			finally = append(finally, h)
+			continue
+		}
+		hOpen, hRets := r.emit([]model.FlowNode{h}, []flowExit{{from: nodeID, label: "EXCEPTION"}})
+		open = append(open, hOpen...)
+		returns = append(returns, hRets...)
+	}
+	if len(finally) > 0 {
+		if len(open) == 0 {
+			// Every path returns or throws; finally still runs before leaving
+			_, rets := r.emit(finally, []flowExit{{from: nodeID, label: "FINALLY"}})
+			return nil, append(returns, rets...)
+		}
+		var rets []flowExit
+		open, rets = r.emit(finally, open)
+		returns = append(returns, rets...)
+	}
+	return open, returns
+}
+
+// edge writes a pending exit edge to a fixed target.
+func (r *flowMermaidRenderer) edge(e flowExit, to, arrow, label string) {
+	if e.label != "" {
+		label = e.label
+	}
+	if label == "" {
+		r.sb.WriteString(fmt.Sprintf("\t\t%s %s %s\n", e.from, arrow, to))
+		return
+	}
+	r.sb.WriteString(fmt.Sprintf("\t\t%s %s|%s| %s\n", e.from, arrow, label, to))
+}
@@ -21,7 +21,10 @@ func GenerateMarkdown(services []model.Service, sysGraph model.SystemGraph, diag
 	// Diagnostics section
 	if !diag.HasOSGi {
 		sb.WriteString("## Diagnostics\n\n")
-		if diag.HasLibertyWAR {
+		if diag.HasEAR {
+			sb.WriteString("- EAR application descriptor (META-INF/application.xml) detected.\n")
+			sb.WriteString("- Each declared web module is modeled as its own service.\n")
+		} else if diag.HasLibertyWAR {
 			sb.WriteString("- Liberty WAR service detected.\n")
 			sb.WriteString("- OSGi bundles not found; modeled as a single Liberty service.\n")
 		} else if diag.HasLiberty {
@@ -51,6 +54,14 @@ func GenerateMarkdown(services []model.Service, sysGraph model.SystemGraph, diag
 			sb.WriteString(fmt.Sprintf("- Liberty Server: %s\n", svc.ServerName))
 		}
 
+		if svc.EnterpriseApp != "" {
+			sb.WriteString(fmt.Sprintf("- Enterprise Application: %s\n", svc.EnterpriseApp))
+		}
+
+		if svc.Application.ContextRoot != "" {
+			sb.WriteString(fmt.Sprintf("- Context Root: %s\n", svc.Application.ContextRoot))
+		}
+
 		if len(svc.Features) > 0 {
 			sb.WriteString("- Enabled Features:\n")
 			for _, f := range svc.Features {
@@ -62,6 +73,9 @@ func GenerateMarkdown(services []model.Service, sysGraph model.SystemGraph, diag
 			sb.WriteString("### REST Resources\n\n")
 			for _, res := range svc.RESTResources {
 				sb.WriteString(fmt.Sprintf("#### %s\n", res.Name))
+				if res.Kind == model.EntryPointServlet {
+					sb.WriteString("Kind: servlet\n")
+				}
 				if res.BasePath != "" {
 					sb.WriteString(fmt.Sprintf("Base path: %s\n", res.BasePath))
 				}
@@ -79,8 +93,25 @@ func GenerateMarkdown(services []model.Service, sysGraph model.SystemGraph, diag
 				}
 				sb.WriteString("\n")
 
+				webSecured := len(svc.WebApp.SecurityConstraints) > 0
 				for _, m := range res.Methods {
-					sb.WriteString(fmt.Sprintf("- %-7s %s\n", m.HTTPMethod, m.FullPath))
+					if webSecured {
+						sb.WriteString(fmt.Sprintf("- %-7s %s [web.xml: %s]\n", m.HTTPMethod, m.FullPath, webAuthLabel(m)))
+					} else {
+						sb.WriteString(fmt.Sprintf("- %-7s %s\n", m.HTTPMethod, m.FullPath))
+					}
+				}
+
+				renderMethodStatuses(&sb, res)
+
+				if webSecured {
+					constrained := 0
+					for _, m := range res.Methods {
+						if m.WebConstrained {
+							constrained++
+						}
+					}
+					sb.WriteString(fmt.Sprintf("\nAuth coverage (web.xml): %d/%d methods constrained\n", constrained, len(res.Methods)))
 				}
 
 				if len(res.HTTPMethods) > 0 {
@@ -129,6 +160,8 @@ func GenerateMarkdown(services []model.Service, sysGraph model.SystemGraph, diag
 						target := "UNRESOLVED"
 						if call.TargetService != "" {
 							target = fmt.Sprintf("%s/%s", call.TargetService, call.TargetResource)
+						} else if call.TargetSystem != "" {
+							target = fmt.Sprintf("external: %s", call.TargetSystem)
 						}
 						sb.WriteString(fmt.Sprintf("- FROM %s/%s.%s\n", call.FromService, call.FromResource, call.FromHandler))
 						sb.WriteString(fmt.Sprintf("  TO %s\n", target))
@@ -146,6 +179,44 @@ func GenerateMarkdown(services []model.Service, sysGraph model.SystemGraph, diag
 			}
 		}
 
+		// Web Descriptor (web.xml)
+		if svc.WebApp.Path != "" || len(svc.WebApp.Servlets) > 0 || len(svc.WebApp.Filters) > 0 {
+			renderWebDescriptor(&sb, svc.WebApp)
+		}
+
+		// JAX-WS (SOAP)
+		if len(svc.SOAPEndpoints) > 0 || len(svc.SOAPCalls) > 0 {
+			renderSOAP(&sb, svc)
+		}
+
+		// CDI / EJB
+		if len(svc.Beans) > 0 || len(svc.InjectionPoints) > 0 {
+			renderBeans(&sb, svc)
+		}
+
+		// Persistence
+		if len(svc.DataAccesses) > 0 || len(svc.NamedQueries) > 0 || len(svc.Entities) > 0 || len(svc.PersistenceUnits) > 0 {
+			renderPersistence(&sb, svc)
+		}
+
+		// Messaging
+		if len(svc.MessageEndpoints) > 0 {
+			sb.WriteString("### Messaging\n\n")
+			for _, ep := range svc.MessageEndpoints {
+				dest := ep.Destination
+				if dest == "" {
+					dest = "UNRESOLVED"
+				}
+				sb.WriteString(fmt.Sprintf("- %s %s %s %s: %s\n", ep.Class, ep.Role, ep.Protocol, ep.DestinationKind, dest))
+				sb.WriteString(fmt.Sprintf("  - Reference: %s (%s)\n", ep.Reference, ep.ReferenceType))
+				if ep.ResolutionEvidence != "" {
+					sb.WriteString(fmt.Sprintf("  - Resolution: %s\n", ep.ResolutionEvidence))
+				}
+				sb.WriteString(fmt.Sprintf("  - Evidence: %s [confidence: %s]\n", ep.Evidence, ep.Confidence))
+			}
+			sb.WriteString("\n")
+		}
+
 		// Phase F4: Service Boundaries
 		if len(svc.Boundaries) > 0 {
 			sb.WriteString("### Detected Service Boundaries\n\n")
@@ -181,12 +252,18 @@ func GenerateMarkdown(services []model.Service, sysGraph model.SystemGraph, diag
 			sb.WriteString(fmt.Sprintf("- Total outbound calls: %d\n", len(svc.RESTCalls)))
 			sb.WriteString(fmt.Sprintf("- Same-service resolved: %d\n", scopeCounts[model.ResolutionSameService]))
 			sb.WriteString(fmt.Sprintf("- Cross-service resolved: %d\n", scopeCounts[model.ResolutionCrossService]))
+			if n := scopeCounts[model.ResolutionExternal]; n > 0 {
+				sb.WriteString(fmt.Sprintf("- External resolved: %d\n", n))
+			}
 			sb.WriteString(fmt.Sprintf("- Unresolved: %d\n", scopeCounts[model.ResolutionUnresolved]))
 			sb.WriteString(fmt.Sprintf("- Distinct target paths: %d\n", len(paths)))
 
 			sb.WriteString("\nBreakdown:\n")
 			sb.WriteString("- Resolution scope:\n")
-			for _, s := range []string{model.ResolutionSameService, model.ResolutionCrossService, model.ResolutionUnresolved} {
+			for _, s := range []string{model.ResolutionSameService, model.ResolutionCrossService, model.ResolutionExternal, model.ResolutionUnresolved} {
+				if s == model.ResolutionExternal && scopeCounts[s] == 0 {
+					continue
+				}
 				sb.WriteString(fmt.Sprintf("  - %s: %d\n", s, scopeCounts[s]))
 			}
 
@@ -213,7 +290,11 @@ func GenerateMarkdown(services []model.Service, sysGraph model.SystemGraph, diag
 		if len(svc.EntryPoints) > 0 {
 			sb.WriteString(fmt.Sprintf("## %s\n\n", svc.Name))
 			for _, ep := range svc.EntryPoints {
-				sb.WriteString(fmt.Sprintf("- %s %s (%s)\n", ep.Method, ep.Path, ep.Handler))
+				if ep.Kind == model.EntryPointServlet {
+					sb.WriteString(fmt.Sprintf("- %s %s (%s) [servlet]\n", ep.Method, ep.Path, ep.Handler))
+				} else {
+					sb.WriteString(fmt.Sprintf("- %s %s (%s)\n", ep.Method, ep.Path, ep.Handler))
+				}
 			}
 			sb.WriteString("\n")
 		}
@@ -243,9 +324,422 @@ func GenerateMarkdown(services []model.Service, sysGraph model.SystemGraph, diag
 		}
 	}
 
+	// 6. Message Flows
+	if len(sysGraph.Channels) > 0 {
+		sb.WriteString("\n# Message Flows\n\n")
+		for _, ch := range sysGraph.Channels {
+			var producers, consumers []string
+			for _, e := range sysGraph.MessageEdges {
+				if e.Protocol != ch.Protocol || e.Channel != ch.Name {
+					continue
+				}
+				if e.Role == model.MessageProducer {
+					producers = append(producers, e.Service)
+				} else {
+					consumers = append(consumers, e.Service)
+				}
+			}
+			sb.WriteString(fmt.Sprintf("- %s %s %s: %s -> %s\n", ch.Protocol, ch.Kind, ch.Name, joinOrNone(producers), joinOrNone(consumers)))
+		}
+	}
+
+	// 7. External Systems
+	if len(sysGraph.ExternalDependencies) > 0 {
+		sb.WriteString("\n# External Systems\n\n")
+		for _, name := range sysGraph.ExternalSystems {
+			var callers []string
+			for _, d := range sysGraph.ExternalDependencies {
+				if d.System == name {
+					callers = append(callers, fmt.Sprintf("%s (%d call(s))", d.FromService, d.Calls))
+				}
+			}
+			sb.WriteString(fmt.Sprintf("- %s <- %s\n", name, strings.Join(callers, ", ")))
+		}
+	}
+
+	// 8. Shared Tables
+	if len(sysGraph.SharedTables) > 0 {
+		sb.WriteString("\n# Shared Tables\n\n")
+		sb.WriteString("Tables mapped by entities of several services couple those services through the database.\n\n")
+		for _, t := range sysGraph.SharedTables {
+			ds := t.DataSource
+			if ds == "" {
+				ds = "data source not determined"
+			}
+			sb.WriteString(fmt.Sprintf("- ⚠️ `%s` (%s): %s\n", t.Table, ds, strings.Join(t.Services, ", ")))
+			for _, e := range t.Entities {
+				sb.WriteString(fmt.Sprintf("  - %s\n", e))
+			}
+		}
+	}
+
 	return sb.String()
 }
 
+// joinOrNone joins service names for display, or returns "(none)" when empty.
+func joinOrNone(values []string) string {
+	if len(values) == 0 {
+		return "(none)"
+	}
+	return strings.Join(values, ", ")
+}
+
+// firstOrNone returns the value, or "(none)" when it is empty.
+func firstOrNone(value string) string {
+	if value == "" {
+		return "(none)"
+	}
+	return value
+}
+
+// renderWebDescriptor writes the servlet inventory and security configuration from web.xml.
+func renderWebDescriptor(sb *strings.Builder, web model.WebDescriptor) {
+	sb.WriteString("### Web Descriptor (web.xml)\n\n")
+	if web.Path != "" {
+		sb.WriteString(fmt.Sprintf("- File: %s\n", web.Path))
+	} else {
+		sb.WriteString("- File: none (annotations only)\n")
+	}
+	if web.JAXRSServlet != "" {
+		prefix := web.JAXRSPrefix
+		if prefix == "" {
+			prefix = "/"
+		}
+		sb.WriteString(fmt.Sprintf("- JAX-RS servlet: %s (prefix: %s)\n", web.JAXRSServlet, prefix))
+	}
+	if web.LoginConfig.AuthMethod != "" {
+		sb.WriteString(fmt.Sprintf("- Login config: %s", web.LoginConfig.AuthMethod))
+		if web.LoginConfig.RealmName != "" {
+			sb.WriteString(fmt.Sprintf(" (realm: %s)", web.LoginConfig.RealmName))
+		}
+		sb.WriteString("\n")
+	}
+	sb.WriteString("\n")
+
+	if len(web.Servlets) > 0 {
+		sb.WriteString("#### Servlet Endpoints\n\n")
+		for _, s := range web.Servlets {
+			class := s.Class
+			if class == "" {
+				class = "-"
+			}
+			kind := "servlet"
+			if s.JAXRS {
+				kind = "jax-rs"
+			}
+			sb.WriteString(fmt.Sprintf("- %s [%s] %s\n", s.Name, kind, class))
+			if len(s.URLPatterns) > 0 {
+				sb.WriteString(fmt.Sprintf("  - URL patterns: %s\n", strings.Join(s.URLPatterns, ", ")))
+			}
+		}
+		sb.WriteString("\n")
+	}
+
+	if len(web.Filters) > 0 {
+		sb.WriteString("#### Filters\n\n")
+		for _, f := range web.Filters {
+			sb.WriteString(fmt.Sprintf("- %s (%s)\n", f.Name, f.Class))
+			if len(f.URLPatterns) > 0 {
+				sb.WriteString(fmt.Sprintf("  - URL patterns: %s\n", strings.Join(f.URLPatterns, ", ")))
+			}
+			if len(f.ServletNames) > 0 {
+				sb.WriteString(fmt.Sprintf("  - Servlets: %s\n", strings.Join(f.ServletNames, ", ")))
+			}
+		}
+		sb.WriteString("\n")
+	}
+
+	if len(web.SecurityConstraints) > 0 {
+		sb.WriteString("#### Security Constraints\n\n")
+		for _, c := range web.SecurityConstraints {
+			name := c.Name
+			if name == "" {
+				name = "(unnamed)"
+			}
+			methods := "all methods"
+			if len(c.HTTPMethods) > 0 {
+				methods = strings.Join(c.HTTPMethods, ", ")
+			} else if len(c.HTTPMethodOmissions) > 0 {
+				methods = "all except " + strings.Join(c.HTTPMethodOmissions, ", ")
+			}
+			roles := "none (no auth-constraint)"
+			if c.AuthConstraint {
+				roles = "deny all"
+				if len(c.Roles) > 0 {
+					roles = strings.Join(c.Roles, ", ")
+				}
+			}
+			sb.WriteString(fmt.Sprintf("- %s: %s [%s]\n", name, strings.Join(c.URLPatterns, ", "), methods))
+			sb.WriteString(fmt.Sprintf("  - Roles: %s\n", roles))
+		}
+		sb.WriteString("\n")
+	}
+}
+
+// renderSOAP writes the JAX-WS endpoints and client calls of a service.
+func renderSOAP(sb *strings.Builder, svc model.Service) {
+	if len(svc.SOAPEndpoints) > 0 {
+		sb.WriteString("### SOAP Endpoints\n\n")
+		for _, ep := range svc.SOAPEndpoints {
+			sb.WriteString(fmt.Sprintf("#### %s\n", ep.ServiceName))
+			sb.WriteString(fmt.Sprintf("- QName: %s\n", ep.QName()))
+			sb.WriteString(fmt.Sprintf("- Implementation: %s (%s)\n", ep.Class, ep.SourceFile))
+			if ep.EndpointInterface != "" {
+				sb.WriteString(fmt.Sprintf("- Endpoint interface: %s\n", ep.EndpointInterface))
+			}
+			if ep.WSDLFile != "" {
+				sb.WriteString(fmt.Sprintf("- WSDL: %s\n", ep.WSDLFile))
+			}
+			for _, addr := range ep.Addresses {
+				sb.WriteString(fmt.Sprintf("- Address: %s\n", addr))
+			}
+			if len(ep.Operations) > 0 {
+				sb.WriteString("- Operations:\n")
+				for _, op := range ep.Operations {
+					sb.WriteString(fmt.Sprintf("  - %s (%s)\n", op.Name, op.Handler))
+				}
+			}
+			sb.WriteString("\n")
+		}
+	}
+
+	if len(svc.SOAPCalls) > 0 {
+		sb.WriteString("### SOAP Client Calls\n\n")
+		for i, call := range svc.SOAPCalls {
+			if i > 0 {
+				sb.WriteString("\n")
+			}
+			target := "UNRESOLVED"
+			if call.TargetService != "" {
+				target = fmt.Sprintf("%s/%s", call.TargetService, call.TargetEndpoint)
+			}
+			sb.WriteString(fmt.Sprintf("- FROM %s/%s\n", call.FromService, call.FromClass))
+			sb.WriteString(fmt.Sprintf("  TO %s\n", target))
+			if call.ServiceQName != "" {
+				sb.WriteString(fmt.Sprintf("  QName: %s\n", call.ServiceQName))
+			}
+			if call.Address != "" {
+				sb.WriteString(fmt.Sprintf("  Address: %s\n", call.Address))
+			}
+			sb.WriteString(fmt.Sprintf("  Resolution: %s\n", call.ResolutionScope))
+			sb.WriteString(fmt.Sprintf("  Confidence: %s\n", call.Confidence))
+			if call.ResolutionEvidence != "" {
+				sb.WriteString(fmt.Sprintf("  Evidence: %s\n", call.ResolutionEvidence))
+			}
+			sb.WriteString(fmt.Sprintf("  File: %s\n", call.Evidence))
+		}
+		sb.WriteString("\n")
+	}
+}
+
+// renderMethodStatuses writes the possible HTTP statuses of the resource methods as a table.
+// Nothing is written when no method has a detected status.
+func renderMethodStatuses(sb *strings.Builder, res model.RESTResource) {
+	found := false
+	for _, m := range res.Methods {
+		if len(m.Statuses) > 0 {
+			found = true
+			break
+		}
+	}
+	if !found {
+		return
+	}
+
+	sb.WriteString("\n| Method | Path | Possible Statuses | Evidence |\n")
+	sb.WriteString("| :--- | :--- | :--- | :--- |\n")
+	for _, m := range res.Methods {
+		var codes, evidence []string
+		seen := make(map[string]bool)
+		for _, st := range m.Statuses {
+			code := "?"
+			if st.Status != 0 {
+				code = fmt.Sprintf("%d", st.Status)
+			}
+			if !seen[code] {
+				seen[code] = true
+				codes = append(codes, code)
+			}
+			evidence = append(evidence, fmt.Sprintf("`%s` %s (%s)", code, st.Evidence, st.Source))
+		}
+		sb.WriteString(fmt.Sprintf("| %s | %s | %s | %s |\n", m.HTTPMethod, m.FullPath, joinOrNone(codes), strings.Join(evidence, "<br>")))
+	}
+}
+
+// renderBeans writes the CDI/EJB bean inventory and injection point resolution of a service.
+func renderBeans(sb *strings.Builder, svc model.Service) {
+	if len(svc.Beans) > 0 {
+		sb.WriteString("### Beans (CDI/EJB)\n\n")
+		for _, b := range svc.Beans {
+			line := fmt.Sprintf("- %s (%s, %s) types: %s", b.Name, b.Kind, b.Scope, strings.Join(b.Types, ", "))
+			if len(b.Qualifiers) > 0 {
+				line += " qualifiers: @" + strings.Join(b.Qualifiers, ", @")
+			}
+			if b.Alternative {
+				line += " [alternative]"
+			}
+			sb.WriteString(line + "\n")
+			sb.WriteString(fmt.Sprintf("  - Evidence: %s\n", b.Evidence))
+		}
+		sb.WriteString("\n")
+	}
+
+	if len(svc.InjectionPoints) > 0 {
+		sb.WriteString("### Injection Points\n\n")
+		var problems []model.InjectionPoint
+		for _, ip := range svc.InjectionPoints {
+			target := ip.Bean
+			switch ip.Status {
+			case model.InjectionAmbiguous:
+				target = "AMBIGUOUS (" + strings.Join(ip.Candidates, ", ") + ")"
+				problems = append(problems, ip)
+			case model.InjectionUnsatisfied:
+				target = "UNSATISFIED"
+				problems = append(problems, ip)
+			case model.InjectionExternal:
+				target = "EXTERNAL"
+			}
+			sb.WriteString(fmt.Sprintf("- %s.%s: %s -> %s (@%s)\n", ip.Class, ip.Member, ip.Type, target, ip.Annotation))
+			sb.WriteString(fmt.Sprintf("  - Resolution: %s\n", ip.Explanation))
+			sb.WriteString(fmt.Sprintf("  - Evidence: %s\n", ip.Evidence))
+		}
+		sb.WriteString("\n")
+
+		if len(problems) > 0 {
+			sb.WriteString(fmt.Sprintf("Injection problems: %d\n", len(problems)))
+			for _, ip := range problems {
+				sb.WriteString(fmt.Sprintf("- %s %s.%s: %s\n", strings.ToUpper(ip.Status), ip.Class, ip.Member, ip.Explanation))
+			}
+			sb.WriteString("\n")
+		}
+	}
+}
+
+// renderPersistence writes the entities and tables a service accesses, with the operations
+// used on them, followed by its named queries.
+func renderPersistence(sb *strings.Builder, svc model.Service) {
+	sb.WriteString("### Persistence\n\n")
+
+	type target struct {
+		name, kind string
+		ops        []string
+		evidence   []string
+	}
+	var targets []*target
+	byKey := make(map[string]*target)
+	add := func(name, kind string, da model.DataAccess) {
+		t, ok := byKey[kind+":"+name]
+		if !ok {
+			t = &target{name: name, kind: kind}
+			byKey[kind+":"+name] = t
+			targets = append(targets, t)
+		}
+		seen := false
+		for _, op := range t.ops {
+			seen = seen || op == da.Operation
+		}
+		if !seen {
+			t.ops = append(t.ops, da.Operation)
+		}
+		t.evidence = append(t.evidence, da.Evidence)
+	}
+	var undetermined []model.DataAccess
+	for _, da := range svc.DataAccesses {
+		for _, e := range da.Entities {
+			add(e, "entity", da)
+		}
+		for _, tbl := range da.Tables {
+			add(tbl, "table", da)
+		}
+		if len(da.Entities) == 0 && len(da.Tables) == 0 {
+			undetermined = append(undetermined, da)
+		}
+	}
+	sort.SliceStable(targets, func(i, j int) bool {
+		if targets[i].kind != targets[j].kind {
+			return targets[i].kind < targets[j].kind
+		}
+		return targets[i].name < targets[j].name
+	})
+
+	if len(targets) > 0 {
+		sb.WriteString("| Entity / Table | Kind | Operations | Evidence |\n")
+		sb.WriteString("| :--- | :--- | :--- | :--- |\n")
+		for _, t := range targets {
+			sb.WriteString(fmt.Sprintf("| `%s` | %s | %s | %s |\n", t.name, t.kind, strings.Join(t.ops, ", "), strings.Join(t.evidence, "<br>")))
+		}
+		sb.WriteString("\n")
+	}
+	if len(undetermined) > 0 {
+		sb.WriteString(fmt.Sprintf("Accesses with undetermined targets: %d\n", len(undetermined)))
+		for _, da := range undetermined {
+			sb.WriteString(fmt.Sprintf("- %s %s (%s)\n", da.Kind, da.Operation, da.Evidence))
+		}
+		sb.WriteString("\n")
+	}
+
+	if len(svc.Entities) > 0 {
+		sb.WriteString("#### Entities\n\n")
+		sb.WriteString("| Entity | Table | Id | Relationships | Unit | Evidence |\n")
+		sb.WriteString("| :--- | :--- | :--- | :--- | :--- | :--- |\n")
+		for _, e := range svc.Entities {
+			var ids, rels []string
+			for _, c := range e.Columns {
+				if c.ID {
+					ids = append(ids, c.Field)
+				}
+			}
+			for _, r := range e.Relations {
+				rels = append(rels, fmt.Sprintf("%s %s -> %s", r.Kind, r.Field, r.Target))
+			}
+			sb.WriteString(fmt.Sprintf("| %s | `%s` | %s | %s | %s | %s |\n", e.Name, e.Table, joinOrNone(ids), joinOrNone(rels), firstOrNone(e.Unit), e.Evidence))
+		}
+		sb.WriteString("\n")
+	}
+
+	if len(svc.PersistenceUnits) > 0 {
+		sb.WriteString("#### Persistence Units\n\n")
+		for _, u := range svc.PersistenceUnits {
+			ds := u.DataSource
+			if ds == "" {
+				ds = "UNRESOLVED"
+			}
+			name := u.Name
+			if u.TransactionType != "" {
+				name += " (" + u.TransactionType + ")"
+			}
+			sb.WriteString(fmt.Sprintf("- %s: data source %s -> %s\n", name, firstOrNone(u.DataSourceName()), ds))
+			sb.WriteString(fmt.Sprintf("  - Resolution: %s\n", u.DataSourceEvidence))
+			sb.WriteString(fmt.Sprintf("  - Evidence: %s\n", u.SourceFile))
+		}
+		sb.WriteString("\n")
+	}
+
+	if len(svc.NamedQueries) > 0 {
+		sb.WriteString("#### Named Queries\n\n")
+		for _, q := range svc.NamedQueries {
+			kind := "JPQL"
+			if q.Native {
+				kind = "SQL"
+			}
+			sb.WriteString(fmt.Sprintf("- %s on %s (%s): `%s`\n", q.Name, q.Entity, kind, q.Query))
+			sb.WriteString(fmt.Sprintf("  - Evidence: %s\n", q.Evidence))
+		}
+		sb.WriteString("\n")
+	}
+}
+
+// webAuthLabel summarizes the web.xml security coverage of a REST method.
+func webAuthLabel(m model.RESTMethod) string {
+	if !m.WebConstrained {
+		return "unconstrained"
+	}
+	if len(m.WebRoles) == 0 {
+		return "deny all"
+	}
+	return "roles " + strings.Join(m.WebRoles, ", ")
+}
+
 func sortRESTCalls(calls []model.RESTCall) {
 	sort.Slice(calls, func(i, j int) bool {
 		vi := confidenceRank(calls[i].Confidence)
@@ -3,6 +3,7 @@ package report
 import (
 	"fmt"
 	"jz/model"
+	"sort"
 	"strings"
 )
 
@@ -37,15 +38,81 @@ func GenerateSystemMermaid(services []model.Service, sysGraph model.SystemGraph)
 				label += " (WAR)"
 				break
 			}
+			if s.Name == svcName && s.Application.Type == "enterpriseApplication" {
+				label += " (EAR: " + s.EnterpriseApp + ")"
+				break
+			}
 		}
 
 		id := sanitize(svcName)
 		sb.WriteString(fmt.Sprintf("\t%s[%s]\n", id, label))
 	}
 
+	// Message channels: producer -> channel -> consumer
+	for _, ch := range sysGraph.Channels {
+		sb.WriteString(fmt.Sprintf("\t%s[/%s %s: %s/]\n", channelID(ch.Protocol, ch.Name), ch.Protocol, ch.Kind, ch.Name))
+	}
+	for _, e := range sysGraph.MessageEdges {
+		svcID := sanitize(e.Service)
+		chID := channelID(e.Protocol, e.Channel)
+		if e.Role == model.MessageProducer {
+			sb.WriteString(fmt.Sprintf("\t%s -.->|produces| %s\n", svcID, chID))
+		} else {
+			sb.WriteString(fmt.Sprintf("\t%s -.->|consumes| %s\n", chID, svcID))
+		}
+	}
+
+	// External systems: calling service -> system
+	for _, name := range sysGraph.ExternalSystems {
+		sb.WriteString(fmt.Sprintf("\t%s{{\"external: %s\"}}\n", externalID(name), name))
+	}
+	for _, d := range sysGraph.ExternalDependencies {
+		sb.WriteString(fmt.Sprintf("\t%s -->|REST x%d| %s\n", sanitize(d.FromService), d.Calls, externalID(d.System)))
+	}
+
+	// Shared tables: every mapping service -> table. Tables whose data source is not
+	// determined may appear in several groups; they are drawn once.
+	drawn := make(map[string]bool)
+	for _, t := range sysGraph.SharedTables {
+		id := tableID(t)
+		if !drawn[id] {
+			drawn[id] = true
+			label := "shared table: " + t.Table
+			if t.DataSource != "" {
+				label += " @ " + t.DataSource
+			}
+			sb.WriteString(fmt.Sprintf("\t%s[(\"%s\")]\n", id, label))
+		}
+		for _, s := range t.Services {
+			edge := fmt.Sprintf("\t%s -.->|maps| %s\n", sanitize(s), id)
+			if !drawn[edge] {
+				drawn[edge] = true
+				sb.WriteString(edge)
+			}
+		}
+	}
+
 	return sb.String()
 }
 
+// channelID creates a Mermaid identifier for a message channel node.
+func channelID(protocol, name string) string {
+	return "ch_" + protocol + "_" + sanitize(name)
+}
+
+// externalID creates a Mermaid identifier for an external system node.
+func externalID(name string) string {
+	return "ext_" + sanitize(name)
+}
+
+// tableID creates a Mermaid identifier for a shared table node.
+func tableID(t model.SharedTable) string {
+	if t.DataSource == "" {
+		return "tbl_" + sanitize(t.Table)
+	}
+	return "tbl_" + sanitize(t.DataSource) + "_" + sanitize(t.Table)
+}
+
 // GenerateComponentMermaid creates a Mermaid graph for internal component dependencies.
 func GenerateComponentMermaid(graph model.DependencyGraph) string {
 	var sb strings.Builder
@@ -109,6 +176,7 @@ func GenerateCallMermaid(services []model.Service) string {
 	// 3. Edges for calls
 	hasUnknown := false
 	hasCalls := false
+	externals := make(map[string]bool)
 	for _, svc := range services {
 		// Outbound calls are already deduplicated per service in Analyze
 		for _, res := range svc.RESTResources {
@@ -133,6 +201,11 @@ func GenerateCallMermaid(services []model.Service) string {
 					arrow = "==>" // Thick arrow for cross-service
 					scopeLabel = "cross"
 					toID = sanitize(call.TargetService + "_" + call.TargetResource)
+				case model.ResolutionExternal:
+					arrow = "==>"
+					scopeLabel = "external"
+					toID = externalID(call.TargetSystem)
+					externals[call.TargetSystem] = true
 				default:
 					scopeLabel = "unresolved"
 				}
@@ -149,12 +222,23 @@ func GenerateCallMermaid(services []model.Service) string {
 	if hasUnknown {
 		sb.WriteString("\tUNKNOWN[UNKNOWN]\n")
 	}
+	var externalNames []string
+	for name := range externals {
+		externalNames = append(externalNames, name)
+	}
+	sort.Strings(externalNames)
+	for _, name := range externalNames {
+		sb.WriteString(fmt.Sprintf("\t%s{{\"external: %s\"}}\n", externalID(name), name))
+	}
 
 	if hasCalls {
 		sb.WriteString("\n\t%% Legend:\n")
 		sb.WriteString("\t%% Solid arrow (-->)   = same-service resolution\n")
 		sb.WriteString("\t%% Thick arrow (==>)   = cross-service resolution\n")
 		sb.WriteString("\t%% Dashed arrow (-.->)  = unresolved\n")
+		if len(externalNames) > 0 {
+			sb.WriteString("\t%% Hexagon node        = registered external system (thick arrow, external scope)\n")
+		}
 		sb.WriteString("\t%% Label: METHOD [scope, confidence]\n")
 	}
 
@@ -0,0 +1,414 @@
+package report
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"jz/app"
+	"jz/model"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"go.yaml.in/yaml/v3"
+)
+
+// openAPIVersion is the OpenAPI version of generated documents.
+const openAPIVersion = "3.0.3"
+
+// operationOrder is the order of HTTP methods within a path item.
+var operationOrder = []string{"GET", "PUT", "POST", "DELETE", "OPTIONS", "HEAD", "PATCH"}
+
+// GenerateOpenAPI produces an OpenAPI 3 document of the JAX-RS resources of a service, as
+// YAML or JSON (format "yaml" or "json"). Every item is derived statically from the
+// sources: paths and path templates, HTTP methods, @PathParam/@QueryParam/@HeaderParam/
+// @CookieParam/@FormParam parameters, @Consumes/@Produces media types, auth annotations
+// and web.xml constraints as security requirements, and the statuses the handler flows
+// can produce. Servlet handlers are not described.
+func GenerateOpenAPI(svc model.Service, format string) string {
+	doc := openAPIDocument(svc)
+	if format == "json" {
+		data, _ := json.MarshalIndent(doc, "", "  ")
+		return string(data) + "\n"
+	}
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	enc.Encode(doc)
+	enc.Close()
+	return buf.String()
+}
+
+// HasOpenAPIOperations reports whether a service has REST operations for an OpenAPI document.
+func HasOpenAPIOperations(svc model.Service) bool {
+	for _, res := range svc.RESTResources {
+		for _, m := range res.Methods {
+			if isOpenAPIOperation(res, m) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func isOpenAPIOperation(res model.RESTResource, m model.RESTMethod) bool {
+	return res.Kind == model.EntryPointJAXRS && slices.Contains(operationOrder, m.HTTPMethod)
+}
+
+func openAPIDocument(svc model.Service) *orderedMap {
+	doc := newOrderedMap()
+	doc.set("openapi", openAPIVersion)
+	doc.set("info", newOrderedMap().
+		set("title", svc.Name).
+		set("version", "unversioned").
+		set("description", fmt.Sprintf("Statically derived by jz from the sources of service %s. It describes what the code declares, not a published contract; review before use.", svc.Name)).
+		set("x-jz-statically-derived", true))
+	if root := svc.Application.ContextRoot; root != "" {
+		doc.set("servers", []interface{}{newOrderedMap().set("url", root)})
+	}
+
+	scheme, schemeDef := securityScheme(svc.WebApp.LoginConfig)
+	var tags []interface{}
+	paths := newOrderedMap()
+	operations := make(map[string]*orderedMap)
+	var pathKeys []string
+	secured := false
+
+	for _, res := range svc.RESTResources {
+		hasOperations := false
+		for _, m := range res.Methods {
+			if !isOpenAPIOperation(res, m) {
+				continue
+			}
+			hasOperations = true
+			path, _, patterns := app.PathTemplate(m.FullPath)
+			item, ok := operations[path]
+			if !ok {
+				item = newOrderedMap()
+				operations[path] = item
+				pathKeys = append(pathKeys, path)
+			}
+			op := openAPIOperation(res, m, patterns)
+			if requiresAuth(m) {
+				var roles []interface{}
+				for _, r := range m.WebRoles {
+					roles = append(roles, r)
+				}
+				op.set("security", []interface{}{newOrderedMap().set(scheme, emptyList(roles))})
+				secured = true
+			}
+			item.set(strings.ToLower(m.HTTPMethod), op)
+		}
+		if hasOperations {
+			tags = append(tags, newOrderedMap().
+				set("name", res.Name).
+				set("x-jz-source", res.SourceFile))
+		}
+	}
+
+	if len(tags) > 0 {
+		doc.set("tags", tags)
+	}
+	sort.Strings(pathKeys)
+	for _, path := range pathKeys {
+		item := operations[path]
+		ordered := newOrderedMap()
+		for _, method := range operationOrder {
+			if op, ok := item.values[strings.ToLower(method)]; ok {
+				ordered.set(strings.ToLower(method), op)
+			}
+		}
+		paths.set(path, ordered)
+	}
+	doc.set("paths", paths)
+	if secured {
+		doc.set("components", newOrderedMap().
+			set("securitySchemes", newOrderedMap().set(scheme, schemeDef)))
+	}
+	return doc
+}
+
+func openAPIOperation(res model.RESTResource, m model.RESTMethod, patterns map[string]string) *orderedMap {
+	op := newOrderedMap()
+	op.set("tags", []interface{}{res.Name})
+	op.set("operationId", strings.ReplaceAll(m.Handler, ".", "_"))
+	op.set("x-jz-handler", m.Handler)
+	op.set("x-jz-source", m.SourceFile)
+
+	// Parameters: path templates first, in path order, then the other annotated arguments
+	var params []interface{}
+	var formParams []model.RESTParam
+	_, names, _ := app.PathTemplate(m.FullPath)
+	for _, name := range names {
+		p := model.RESTParam{Name: name, In: model.ParamInPath}
+		for _, declared := range m.Params {
+			if declared.In == model.ParamInPath && declared.Name == name {
+				p = declared
+			}
+		}
+		schema := javaTypeSchema(p.Type)
+		if pattern := patterns[name]; pattern != "" {
+			// pattern only applies to strings; the template expression is kept either way
+			if schema.values["type"] == "string" {
+				schema.set("pattern", pattern)
+			} else {
+				schema.set("x-jz-pattern", pattern)
+			}
+		}
+		params = append(params, openAPIParameter(p, schema, true))
+	}
+	for _, p := range m.Params {
+		switch p.In {
+		case model.ParamInQuery, model.ParamInHeader, model.ParamInCookie:
+			params = append(params, openAPIParameter(p, javaTypeSchema(p.Type), false))
+		case model.ParamInForm:
+			formParams = append(formParams, p)
+		}
+	}
+	if len(params) > 0 {
+		op.set("parameters", params)
+	}
+
+	// Request body
+	switch {
+	case len(formParams) > 0:
+		properties := newOrderedMap()
+		for _, p := range formParams {
+			properties.set(p.Name, javaTypeSchema(p.Type))
+		}
+		mediaType := "application/x-www-form-urlencoded"
+		if slices.Contains(m.Consumes, "multipart/form-data") {
+			mediaType = "multipart/form-data"
+		}
+		op.set("requestBody", newOrderedMap().set("content", newOrderedMap().
+			set(mediaType, newOrderedMap().set("schema", newOrderedMap().
+				set("type", "object").
+				set("properties", properties)))))
+	case len(knownMediaTypes(m.Consumes)) > 0 && (m.HTTPMethod == "POST" || m.HTTPMethod == "PUT" || m.HTTPMethod == "PATCH"):
+		content := newOrderedMap()
+		for _, mt := range knownMediaTypes(m.Consumes) {
+			content.set(mt, newOrderedMap())
+		}
+		op.set("requestBody", newOrderedMap().set("content", content))
+	}
+
+	op.set("responses", openAPIResponses(m))
+	return op
+}
+
+func openAPIParameter(p model.RESTParam, schema *orderedMap, required bool) *orderedMap {
+	if p.Default != "" {
+		schema.set("default", typedDefault(p.Default, schema.values["type"]))
+	}
+	param := newOrderedMap().
+		set("name", p.Name).
+		set("in", p.In)
+	if required {
+		param.set("required", true)
+	}
+	param.set("schema", schema)
+	if p.Evidence != "" {
+		param.set("x-jz-evidence", p.Evidence)
+	}
+	return param
+}
+
+// openAPIResponses describes the statuses found for a method, merging the evidence of each
+// status. Without statuses a default response states that none was determined.
+func openAPIResponses(m model.RESTMethod) *orderedMap {
+	details := make(map[int][]string)
+	evidence := make(map[int][]interface{})
+	var codes []int
+	for _, s := range m.Statuses {
+		if s.Status == 0 {
+			continue
+		}
+		if _, ok := details[s.Status]; !ok {
+			codes = append(codes, s.Status)
+		}
+		if !slices.Contains(details[s.Status], s.Detail) {
+			details[s.Status] = append(details[s.Status], s.Detail)
+		}
+		evidence[s.Status] = append(evidence[s.Status], s.Evidence)
+	}
+	sort.Ints(codes)
+
+	responses := newOrderedMap()
+	if len(codes) == 0 {
+		responses.set("default", newOrderedMap().
+			set("description", "Response status not determined statically"))
+		return responses
+	}
+	for _, code := range codes {
+		r := newOrderedMap().set("description", strings.Join(details[code], "; "))
+		if produces := knownMediaTypes(m.Produces); code >= 200 && code < 300 && code != 204 && len(produces) > 0 {
+			content := newOrderedMap()
+			for _, mt := range produces {
+				content.set(mt, newOrderedMap())
+			}
+			r.set("content", content)
+		}
+		r.set("x-jz-evidence", evidence[code])
+		responses.set(strconv.Itoa(code), r)
+	}
+	return responses
+}
+
+// knownMediaTypes drops the media type expressions the analysis could not resolve, which
+// are not valid content keys.
+func knownMediaTypes(types []string) []string {
+	var known []string
+	for _, mt := range types {
+		if model.MediaTypesKnown([]string{mt}) {
+			known = append(known, mt)
+		}
+	}
+	return known
+}
+
+func requiresAuth(m model.RESTMethod) bool {
+	return m.WebConstrained || model.RequiresAuth(m.Auth)
+}
+
+// securityScheme maps the web.xml login-config to an OpenAPI security scheme. Without a
+// known mechanism the scheme is a placeholder that only records that authentication is
+// required.
+func securityScheme(lc model.LoginConfig) (string, *orderedMap) {
+	switch strings.ToUpper(lc.AuthMethod) {
+	case "BASIC":
+		return "basicAuth", newOrderedMap().set("type", "http").set("scheme", "basic")
+	case "DIGEST":
+		return "digestAuth", newOrderedMap().set("type", "http").set("scheme", "digest")
+	case "FORM":
+		return "formLogin", newOrderedMap().
+			set("type", "apiKey").
+			set("in", "cookie").
+			set("name", "JSESSIONID").
+			set("description", "web.xml FORM login; the session cookie carries the authentication")
+	}
+	return "containerAuth", newOrderedMap().
+		set("type", "http").
+		set("scheme", "basic").
+		set("description", "Authentication is required (auth annotations or web.xml security constraints) but the mechanism was not determined statically; the scheme type is a placeholder")
+}
+
+// javaTypeSchema maps a declared Java parameter type to a JSON schema.
+func javaTypeSchema(javaType string) *orderedMap {
+	t := strings.TrimSpace(javaType)
+	if strings.HasSuffix(t, "[]") {
+		return newOrderedMap().set("type", "array").set("items", javaTypeSchema(strings.TrimSuffix(t, "[]")))
+	}
+	if open := strings.Index(t, "<"); open >= 0 && strings.HasSuffix(t, ">") {
+		switch simple := t[:open]; simple {
+		case "List", "Set", "SortedSet", "Collection", "java.util.List", "java.util.Set":
+			return newOrderedMap().set("type", "array").set("items", javaTypeSchema(t[open+1:len(t)-1]))
+		case "Optional", "java.util.Optional":
+			return javaTypeSchema(t[open+1 : len(t)-1])
+		}
+	}
+	if dot := strings.LastIndex(t, "."); dot >= 0 {
+		t = t[dot+1:]
+	}
+	switch t {
+	case "int", "Integer", "short", "Short", "byte", "Byte":
+		return newOrderedMap().set("type", "integer").set("format", "int32")
+	case "long", "Long", "BigInteger":
+		return newOrderedMap().set("type", "integer").set("format", "int64")
+	case "float", "Float":
+		return newOrderedMap().set("type", "number").set("format", "float")
+	case "double", "Double":
+		return newOrderedMap().set("type", "number").set("format", "double")
+	case "BigDecimal":
+		return newOrderedMap().set("type", "number")
+	case "boolean", "Boolean":
+		return newOrderedMap().set("type", "boolean")
+	case "UUID":
+		return newOrderedMap().set("type", "string").set("format", "uuid")
+	case "LocalDate":
+		return newOrderedMap().set("type", "string").set("format", "date")
+	case "LocalDateTime", "OffsetDateTime", "ZonedDateTime", "Instant", "Date":
+		return newOrderedMap().set("type", "string").set("format", "date-time")
+	}
+	return newOrderedMap().set("type", "string")
+}
+
+// typedDefault converts a @DefaultValue literal to the type of its schema when it parses.
+func typedDefault(value string, schemaType interface{}) interface{} {
+	switch schemaType {
+	case "integer":
+		if n, err := strconv.Atoi(value); err == nil {
+			return n
+		}
+	case "number":
+		if f, err := strconv.ParseFloat(value, 64); err == nil {
+			return f
+		}
+	case "boolean":
+		if b, err := strconv.ParseBool(value); err == nil {
+			return b
+		}
+	}
+	return value
+}
+
+func emptyList(values []interface{}) []interface{} {
+	if values == nil {
+		return []interface{}{}
+	}
+	return values
+}
+
+// orderedMap is a document mapping that keeps its keys in insertion order, so generated
+// documents are deterministic and read in the order of the OpenAPI specification. Values
+// are strings, ints, float64s, bools, []interface{} and *orderedMap.
+type orderedMap struct {
+	keys   []string
+	values map[string]interface{}
+}
+
+func newOrderedMap() *orderedMap {
+	return &orderedMap{values: make(map[string]interface{})}
+}
+
+func (m *orderedMap) set(key string, value interface{}) *orderedMap {
+	if _, ok := m.values[key]; !ok {
+		m.keys = append(m.keys, key)
+	}
+	m.values[key] = value
+	return m
+}
+
+func (m *orderedMap) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, k := range m.keys {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		key, _ := json.Marshal(k)
+		value, err := json.Marshal(m.values[k])
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(key)
+		buf.WriteByte(':')
+		buf.Write(value)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// MarshalYAML encodes the mapping as a YAML mapping node in key order; the encoder quotes
+// and escapes keys and values as needed.
+func (m *orderedMap) MarshalYAML() (interface{}, error) {
+	node := &yaml.Node{Kind: yaml.MappingNode}
+	for _, k := range m.keys {
+		var value yaml.Node
+		if err := value.Encode(m.values[k]); err != nil {
+			return nil, err
+		}
+		node.Content = append(node.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: k}, &value)
+	}
+	return node, nil
+}
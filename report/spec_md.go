@@ -0,0 +1,95 @@
+package report
+
+import (
+	"fmt"
+	"jz/model"
+	"strings"
+)
+
+// GenerateSpecCheckMarkdown produces a markdown report of the drift between the code of a
+// service and an OpenAPI document.
+func GenerateSpecCheckMarkdown(c model.SpecCheck) string {
+	var sb strings.Builder
+
+	sb.WriteString("# OpenAPI Drift\n\n")
+	sb.WriteString("> **Analysis Mode:** Code vs OpenAPI Spec\n")
+	sb.WriteString(fmt.Sprintf("> **Service:** `%s`\n", c.Service))
+	sb.WriteString(fmt.Sprintf("> **Spec:** `%s`\n", c.Spec))
+	if c.SpecPrefix != "" {
+		sb.WriteString(fmt.Sprintf("> **Spec Path Prefix:** `%s`\n", c.SpecPrefix))
+	}
+	sb.WriteString("\n")
+
+	sb.WriteString("## Summary\n\n")
+	sb.WriteString("| Operations | Count |\n")
+	sb.WriteString("| :--- | :---: |\n")
+	sb.WriteString(fmt.Sprintf("| In code | %d |\n", c.CodeOperations))
+	sb.WriteString(fmt.Sprintf("| In spec | %d |\n", c.SpecOperations))
+	sb.WriteString(fmt.Sprintf("| Matched | %d |\n", c.Matched))
+	sb.WriteString(fmt.Sprintf("| Missing from spec | %d |\n", c.Count(model.DriftMissingFromSpec)))
+	sb.WriteString(fmt.Sprintf("| Missing from code | %d |\n", c.Count(model.DriftMissingFromCode)))
+	sb.WriteString(fmt.Sprintf("| Method mismatches | %d |\n", c.Count(model.DriftMethodMismatch)))
+	sb.WriteString(fmt.Sprintf("| Media type mismatches | %d |\n", c.Count(model.DriftConsumesMismatch)+c.Count(model.DriftProducesMismatch)))
+	sb.WriteString("\n")
+
+	if len(c.Drifts) == 0 {
+		sb.WriteString("> ✅ **The spec matches the code.**\n")
+		return sb.String()
+	}
+	sb.WriteString(fmt.Sprintf("> ⚠️ **Drift detected:** %d difference(s) between the code and the spec.\n\n", len(c.Drifts)))
+
+	renderDrifts := func(title string, kinds ...model.SpecDriftKind) {
+		var drifts []model.SpecDrift
+		for _, d := range c.Drifts {
+			if containsDriftKind(kinds, d.Kind) {
+				drifts = append(drifts, d)
+			}
+		}
+		if len(drifts) == 0 {
+			return
+		}
+		sb.WriteString(fmt.Sprintf("## %s\n\n", title))
+		for _, d := range drifts {
+			label := d.Path
+			if d.HTTPMethod != "" {
+				label = d.HTTPMethod + " " + d.Path
+			}
+			line := fmt.Sprintf("- `%s`", label)
+			switch d.Kind {
+			case model.DriftConsumesMismatch:
+				line += " request body"
+			case model.DriftProducesMismatch:
+				line += " responses"
+			}
+			if d.Handler != "" {
+				line += fmt.Sprintf(" → `%s`", d.Handler)
+			}
+			sb.WriteString(line + "\n")
+			for _, detail := range d.Details {
+				sb.WriteString(fmt.Sprintf("  - %s\n", detail))
+			}
+			if d.CodeEvidence != "" {
+				sb.WriteString(fmt.Sprintf("  - Code: %s\n", d.CodeEvidence))
+			}
+			if d.SpecEvidence != "" {
+				sb.WriteString(fmt.Sprintf("  - Spec: %s\n", d.SpecEvidence))
+			}
+		}
+		sb.WriteString("\n")
+	}
+	renderDrifts("Missing from Spec", model.DriftMissingFromSpec)
+	renderDrifts("Missing from Code", model.DriftMissingFromCode)
+	renderDrifts("Method Mismatches", model.DriftMethodMismatch)
+	renderDrifts("Media Type Mismatches", model.DriftConsumesMismatch, model.DriftProducesMismatch)
+
+	return sb.String()
+}
+
+func containsDriftKind(kinds []model.SpecDriftKind, kind model.SpecDriftKind) bool {
+	for _, k := range kinds {
+		if k == kind {
+			return true
+		}
+	}
+	return false
+}
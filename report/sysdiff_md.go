@@ -0,0 +1,148 @@
+package report
+
+import (
+	"fmt"
+	"jz/model"
+	"strings"
+)
+
+// GenerateSystemDiffMarkdown produces a markdown report of the structural differences
+// between two analyses.
+func GenerateSystemDiffMarkdown(d model.SystemDiff, pathA, pathB string) string {
+	var sb strings.Builder
+
+	sb.WriteString("# System Diff\n\n")
+	sb.WriteString("> **Analysis Mode:** Structural System Diff\n")
+	sb.WriteString(fmt.Sprintf("> **Base:** `%s`\n", pathA))
+	sb.WriteString(fmt.Sprintf("> **Head:** `%s`\n\n", pathB))
+
+	if d.Empty() {
+		sb.WriteString("No structural differences detected between the analyses.\n")
+		return sb.String()
+	}
+
+	// Summary
+	sb.WriteString("## Summary\n\n")
+	sb.WriteString("| Area | Added | Removed | Changed |\n")
+	sb.WriteString("| :--- | :---: | :---: | :---: |\n")
+	writeCounts := func(area string, kinds []model.ChangeKind) {
+		counts := make(map[model.ChangeKind]int)
+		for _, k := range kinds {
+			counts[k]++
+		}
+		sb.WriteString(fmt.Sprintf("| %s | %d | %d | %d |\n", area, counts[model.ChangeAdded], counts[model.ChangeRemoved], counts[model.ChangeChanged]))
+	}
+	writeCounts("Services", itemKinds(d.Services))
+	var endpointKinds []model.ChangeKind
+	for _, c := range d.Endpoints {
+		endpointKinds = append(endpointKinds, c.Kind)
+	}
+	writeCounts("Endpoints", endpointKinds)
+	writeCounts("DS Components", itemKinds(d.Components))
+	writeCounts("Dependencies", edgeKinds(d.Dependencies))
+	writeCounts("Liberty Features", itemKinds(d.Features))
+	writeCounts("REST Calls", edgeKinds(d.Calls))
+	sb.WriteString("\n")
+
+	if n := d.BreakingChanges(); n > 0 {
+		sb.WriteString(fmt.Sprintf("> ⚠️ **Breaking API changes:** %d endpoint change(s) break existing clients.\n\n", n))
+	} else {
+		sb.WriteString("> ✅ **No breaking API changes detected.**\n\n")
+	}
+
+	renderItemChanges(&sb, "Services", d.Services)
+
+	if len(d.Endpoints) > 0 {
+		sb.WriteString("## Endpoints\n\n")
+		for _, c := range d.Endpoints {
+			impact := "non-breaking"
+			if c.Breaking {
+				impact = "**BREAKING**"
+			}
+			switch c.Kind {
+			case model.ChangeAdded:
+				sb.WriteString(fmt.Sprintf("+ Added %s: %s\n", endpointLabel(*c.After), impact))
+			case model.ChangeRemoved:
+				sb.WriteString(fmt.Sprintf("- Removed %s: %s\n", endpointLabel(*c.Before), impact))
+			case model.ChangeChanged:
+				sb.WriteString(fmt.Sprintf("~ Changed %s: %s\n", endpointLabel(*c.Before), impact))
+				for _, detail := range c.Details {
+					sb.WriteString(fmt.Sprintf("  - %s\n", detail))
+				}
+			}
+			for _, reason := range c.BreakingReasons {
+				sb.WriteString(fmt.Sprintf("  - ⚠️ %s\n", reason))
+			}
+		}
+		sb.WriteString("\n")
+	}
+
+	renderItemChanges(&sb, "DS Components", d.Components)
+	renderEdgeChanges(&sb, "Dependencies", d.Dependencies)
+	renderItemChanges(&sb, "Liberty Features", d.Features)
+	renderEdgeChanges(&sb, "REST Calls", d.Calls)
+
+	return sb.String()
+}
+
+// endpointLabel names an endpoint as "METHOD /path (service: Handler)".
+func endpointLabel(e model.Endpoint) string {
+	return fmt.Sprintf("`%s %s` (%s: %s)", e.HTTPMethod, e.Path, e.Service, e.Handler)
+}
+
+func renderItemChanges(sb *strings.Builder, title string, changes []model.ItemChange) {
+	if len(changes) == 0 {
+		return
+	}
+	sb.WriteString(fmt.Sprintf("## %s\n\n", title))
+	for _, c := range changes {
+		name := c.Name
+		if c.Service != "" {
+			name = c.Service + ": " + name
+		}
+		switch c.Kind {
+		case model.ChangeAdded:
+			sb.WriteString(fmt.Sprintf("+ Added %s\n", name))
+		case model.ChangeRemoved:
+			sb.WriteString(fmt.Sprintf("- Removed %s\n", name))
+		case model.ChangeChanged:
+			sb.WriteString(fmt.Sprintf("~ Changed %s\n", name))
+			for _, detail := range c.Details {
+				sb.WriteString(fmt.Sprintf("  - %s\n", detail))
+			}
+		}
+	}
+	sb.WriteString("\n")
+}
+
+func renderEdgeChanges(sb *strings.Builder, title string, changes []model.EdgeChange) {
+	if len(changes) == 0 {
+		return
+	}
+	sb.WriteString(fmt.Sprintf("## %s\n\n", title))
+	for _, c := range changes {
+		edge := fmt.Sprintf("%s -> %s [%s: %s]", c.From, c.To, c.Type, c.Label)
+		if c.Kind == model.ChangeAdded {
+			sb.WriteString(fmt.Sprintf("+ Added %s\n", edge))
+		} else {
+			sb.WriteString(fmt.Sprintf("- Removed %s\n", edge))
+		}
+	}
+	sb.WriteString("\n")
+}
+
+func itemKinds(changes []model.ItemChange) []model.ChangeKind {
+	var kinds []model.ChangeKind
+	for _, c := range changes {
+		kinds = append(kinds, c.Kind)
+	}
+	return kinds
+}
+
+func edgeKinds(changes []model.EdgeChange) []model.ChangeKind {
+	var kinds []model.ChangeKind
+	for _, c := range changes {
+		kinds = append(kinds, c.Kind)
+	}
+	return kinds
+}
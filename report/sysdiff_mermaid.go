@@ -0,0 +1,113 @@
+package report
+
+import (
+	"fmt"
+	"jz/model"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// GenerateSystemDiffMermaid creates a Mermaid graph of the services of both analyses and
+// the dependency, message and REST call edges added (green) or removed (red, dashed)
+// between them. Added and removed services are highlighted the same way.
+func GenerateSystemDiffMermaid(d model.SystemDiff, graphA, graphB model.SystemGraph) string {
+	var sb strings.Builder
+	sb.WriteString("graph TD\n")
+
+	changed := make(map[string]model.ChangeKind)
+	for _, c := range d.Services {
+		changed[c.Name] = c.Kind
+	}
+	var services []string
+	for _, name := range append(append([]string{}, graphA.Services...), graphB.Services...) {
+		if !slices.Contains(services, name) {
+			services = append(services, name)
+		}
+	}
+	sort.Strings(services)
+	for _, name := range services {
+		id := sanitize(name)
+		sb.WriteString(fmt.Sprintf("\t%s[%s]\n", id, name))
+		if kind, ok := changed[name]; ok {
+			sb.WriteString(fmt.Sprintf("\tclass %s %s\n", id, kind))
+		}
+	}
+
+	// Channel nodes of changed message edges
+	var channels []string
+	for _, e := range d.Dependencies {
+		if e.Type != "message" {
+			continue
+		}
+		for _, end := range []string{e.From, e.To} {
+			if strings.Contains(end, ":") && !slices.Contains(channels, end) {
+				channels = append(channels, end)
+			}
+		}
+	}
+	for _, ch := range channels {
+		protocol, name, _ := strings.Cut(ch, ":")
+		sb.WriteString(fmt.Sprintf("\t%s[/%s: %s/]\n", channelID(protocol, name), protocol, name))
+	}
+	nodeID := func(end string) string {
+		if protocol, name, ok := strings.Cut(end, ":"); ok {
+			return channelID(protocol, name)
+		}
+		return sanitize(end)
+	}
+
+	// Edges, styled by index
+	var added, removed []int
+	link := 0
+	writeEdge := func(e model.EdgeChange, from, to, label string) {
+		arrow := "-->"
+		if e.Kind == model.ChangeRemoved {
+			arrow = "-.->"
+			removed = append(removed, link)
+		} else {
+			added = append(added, link)
+		}
+		label = strings.NewReplacer(`"`, "'", "|", "/").Replace(label)
+		sb.WriteString(fmt.Sprintf("\t%s %s|\"%s %s\"| %s\n", from, arrow, changeSign(e.Kind), label, to))
+		link++
+	}
+	for _, e := range d.Dependencies {
+		writeEdge(e, nodeID(e.From), nodeID(e.To), e.Label)
+	}
+	for _, e := range d.Calls {
+		fromSvc, fromRes, _ := strings.Cut(e.From, "/")
+		toSvc, toRes, _ := strings.Cut(e.To, "/")
+		writeEdge(e, sanitize(fromSvc), sanitize(toSvc), fmt.Sprintf("%s: %s -> %s", e.Label, fromRes, toRes))
+	}
+
+	sb.WriteString("\tclassDef added fill:#dfd,stroke:#2a2\n")
+	sb.WriteString("\tclassDef removed fill:#fdd,stroke:#c22,stroke-dasharray:5 5\n")
+	if len(added) > 0 {
+		sb.WriteString(fmt.Sprintf("\tlinkStyle %s stroke:#2a2,stroke-width:2px\n", joinInts(added)))
+	}
+	if len(removed) > 0 {
+		sb.WriteString(fmt.Sprintf("\tlinkStyle %s stroke:#c22,stroke-width:2px\n", joinInts(removed)))
+	}
+
+	return sb.String()
+}
+
+// changeSign returns the diff marker of a change kind.
+func changeSign(kind model.ChangeKind) string {
+	switch kind {
+	case model.ChangeAdded:
+		return "+"
+	case model.ChangeRemoved:
+		return "-"
+	}
+	return "~"
+}
+
+func joinInts(values []int) string {
+	var parts []string
+	for _, v := range values {
+		parts = append(parts, fmt.Sprintf("%d", v))
+	}
+	return strings.Join(parts, ",")
+}
@@ -0,0 +1,518 @@
+package scan
+
+import (
+	"encoding/xml"
+	"fmt"
+	"jz/model"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// BeanInventory holds the CDI/EJB declarations found in a source tree.
+type BeanInventory struct {
+	Beans           []model.Bean // Annotated beans, EJBs and producers
+	PlainClasses    []model.Bean // Concrete classes without a bean-defining annotation
+	InjectionPoints []model.InjectionPoint
+	Qualifiers      map[string]bool // Annotation types declared with @Qualifier
+	DeclaredTypes   map[string]bool // Every class, interface and enum declared in the sources
+}
+
+var (
+	typeDeclRegex   = regexp.MustCompile(`(?:^|\s)(class|interface|enum|@interface)\s+(\w+)`)
+	extendsRegex    = regexp.MustCompile(`\bextends\s+([\w.]+)`)
+	implementsRegex = regexp.MustCompile(`\bimplements\s+([^{]+)`)
+	memberDeclRegex = regexp.MustCompile(`([\w.]+(?:<.*>)?(?:\[\])*)\s+(\w+)\s*(?:[;=(]|$)`)
+	leadingAnnRegex = regexp.MustCompile(`^@([\w.]+)`)
+)
+
+// scopeAnnotations are bean-defining annotations mapped to the bean kind.
+var scopeAnnotations = map[string]string{
+	"ApplicationScoped":  model.BeanCDI,
+	"RequestScoped":      model.BeanCDI,
+	"SessionScoped":      model.BeanCDI,
+	"ConversationScoped": model.BeanCDI,
+	"Dependent":          model.BeanCDI,
+	"Stateless":          model.BeanEJB,
+	"Stateful":           model.BeanEJB,
+	"Singleton":          model.BeanCDI, // javax.inject.Singleton; javax.ejb.Singleton is detected by import
+}
+
+// builtinInjectionTypes are provided by the container and never resolved against source beans.
+var builtinInjectionTypes = map[string]bool{
+	"Event": true, "Instance": true, "Provider": true, "BeanManager": true, "InjectionPoint": true,
+}
+
+// nonBeanInjectionAnnotations mark injection points served by other mechanisms (config, messaging, JWT, REST clients).
+var nonBeanInjectionAnnotations = map[string]bool{
+	"ConfigProperty": true, "Channel": true, "Claim": true, "RestClient": true, "Context": true,
+}
+
+// ScanBeans recursively walks the rootDir and extracts CDI managed beans, EJB session
+// beans, @Produces producers and @Inject/@EJB injection points (fields, constructor and
+// initializer method parameters). Qualifiers are the annotation types declared in the
+// sources with @Qualifier, plus @Named. Resolution is performed by the caller.
+func ScanBeans(tree *model.SourceTree, rootDir string) (BeanInventory, error) {
+	inv := BeanInventory{
+		Qualifiers:    make(map[string]bool),
+		DeclaredTypes: make(map[string]bool),
+	}
+
+	err := tree.Walk(rootDir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() || !strings.HasSuffix(path, ".java") {
+			return nil
+		}
+		// We ignore file reading errors to prevent stopping the entire walk
+		scanBeanFile(tree, path, &inv)
+		return nil
+	})
+	if err != nil {
+		return inv, err
+	}
+
+	// Qualifiers are only known once every @interface has been seen.
+	keep := func(anns []string) []string {
+		var q []string
+		for _, a := range anns {
+			if strings.HasPrefix(a, "Named(") || inv.Qualifiers[a] {
+				q = append(q, a)
+			}
+		}
+		return q
+	}
+	for i := range inv.Beans {
+		inv.Beans[i].Qualifiers = keep(inv.Beans[i].Qualifiers)
+	}
+	for i := range inv.InjectionPoints {
+		inv.InjectionPoints[i].Qualifiers = keep(inv.InjectionPoints[i].Qualifiers)
+	}
+
+	return inv, nil
+}
+
+// beanAnnotation is a flattened annotation with its simple name.
+type beanAnnotation struct {
+	name string
+	text string
+}
+
+func scanBeanFile(tree *model.SourceTree, filePath string, inv *BeanInventory) error {
+	lines, err := readLines(tree, filePath)
+	if err != nil {
+		return err
+	}
+
+	var pkg, className string
+	var pending []beanAnnotation
+	var pendingLine int
+	ejbSingleton := false
+	depth := 0
+
+	for i := 0; i < len(lines); i++ {
+		line := strings.TrimSpace(lines[i])
+		if line == "" || strings.HasPrefix(line, "//") || strings.HasPrefix(line, "*") || strings.HasPrefix(line, "/*") {
+			continue
+		}
+		if strings.HasPrefix(line, "package ") {
+			pkg = strings.TrimSuffix(strings.TrimSpace(strings.TrimPrefix(line, "package ")), ";")
+			continue
+		}
+		if strings.HasPrefix(line, "import ") {
+			if strings.Contains(line, "ejb.Singleton") {
+				ejbSingleton = true
+			}
+			continue
+		}
+
+		// Leading annotations (possibly several per line, possibly multi-line)
+		if strings.HasPrefix(line, "@") && !strings.HasPrefix(line, "@interface") {
+			if len(pending) == 0 {
+				pendingLine = i + 1
+			}
+			text, end := collectAnnotation(lines, i)
+			anns, rest := splitLeadingAnnotations(strings.TrimSpace(text))
+			pending = append(pending, anns...)
+			i = end
+			if rest == "" {
+				continue
+			}
+			line = rest
+		}
+		if len(pending) == 0 {
+			pendingLine = i + 1
+		}
+
+		lineDepth := depth
+		depth += strings.Count(line, "{") - strings.Count(line, "}")
+
+		if m := typeDeclRegex.FindStringSubmatch(line); m != nil && !strings.Contains(line, "new ") && !strings.Contains(line, "(") {
+			inv.DeclaredTypes[m[2]] = true
+			if m[1] == "@interface" && hasAnnotation(pending, "Qualifier") {
+				inv.Qualifiers[m[2]] = true
+			}
+			if className == "" && m[1] == "class" {
+				className = m[2]
+				if bean, ok := classBean(line, m[2], pkg, pending, ejbSingleton); ok {
+					bean.SourceFile = filePath
+					bean.Evidence = fmt.Sprintf("%s:%d", filePath, pendingLine)
+					if bean.Scope == "" {
+						inv.PlainClasses = append(inv.PlainClasses, bean)
+					} else {
+						inv.Beans = append(inv.Beans, bean)
+					}
+				}
+			}
+			pending = nil
+			continue
+		}
+
+		// Members of the top-level class only
+		if className == "" || lineDepth != 1 {
+			pending = nil
+			continue
+		}
+
+		evidence := fmt.Sprintf("%s:%d", filePath, pendingLine)
+		switch {
+		case hasAnnotation(pending, "Inject") || hasAnnotation(pending, "EJB"):
+			annotation := "Inject"
+			beanName := ""
+			if a, ok := findAnnotation(pending, "EJB"); ok {
+				annotation = "EJB"
+				beanName = annotationAttrs(a.text)["beanName"]
+			}
+			if strings.Contains(line, "(") {
+				// Constructor or initializer method: every parameter is an injection point
+				sig, end := collectUntil(lines, i, line, ")")
+				i = end
+				depth += countBraces(sig) - countBraces(line)
+				for _, param := range splitParams(sig) {
+					anns, rest := splitLeadingAnnotations(param)
+					if ip, ok := injectionPoint(rest, anns, className, annotation, beanName); ok {
+						ip.SourceFile, ip.Evidence = filePath, evidence
+						inv.InjectionPoints = append(inv.InjectionPoints, ip)
+					}
+				}
+			} else if ip, ok := injectionPoint(line, pending, className, annotation, beanName); ok {
+				ip.SourceFile, ip.Evidence = filePath, evidence
+				inv.InjectionPoints = append(inv.InjectionPoints, ip)
+			}
+		case hasProducesCDI(pending):
+			if typ, member, ok := parseMember(line); ok {
+				qualifiers := annotationNames(pending)
+				for k, q := range qualifiers {
+					if q == "Named()" {
+						qualifiers[k] = "Named(" + producerName(member) + ")"
+					}
+				}
+				inv.Beans = append(inv.Beans, model.Bean{
+					Name:          className + "." + member,
+					Class:         className,
+					QualifiedName: qualify(pkg, className),
+					Kind:          model.BeanProducer,
+					Scope:         producerScope(pending),
+					Types:         []string{typ},
+					Qualifiers:    qualifiers,
+					Alternative:   hasAnnotation(pending, "Alternative") && hasAnnotation(pending, "Priority"),
+					SourceFile:    filePath,
+					Evidence:      evidence,
+				})
+			}
+		}
+		pending = nil
+	}
+
+	return nil
+}
+
+// classBean builds a bean from a top-level class declaration. Abstract classes,
+// decorators, interceptors and vetoed classes are not beans.
+func classBean(line, name, pkg string, anns []beanAnnotation, ejbSingleton bool) (model.Bean, bool) {
+	if strings.Contains(line, "abstract ") {
+		return model.Bean{}, false
+	}
+	for _, skip := range []string{"Decorator", "Interceptor", "Vetoed"} {
+		if hasAnnotation(anns, skip) {
+			return model.Bean{}, false
+		}
+	}
+
+	bean := model.Bean{
+		Name:          name,
+		Class:         name,
+		QualifiedName: qualify(pkg, name),
+		Kind:          model.BeanCDI,
+		Types:         []string{name},
+		Alternative:   hasAnnotation(anns, "Alternative") && hasAnnotation(anns, "Priority"),
+	}
+	for _, a := range anns {
+		if kind, ok := scopeAnnotations[a.name]; ok {
+			bean.Scope = a.name
+			bean.Kind = kind
+			if a.name == "Singleton" && ejbSingleton {
+				bean.Kind = model.BeanEJB
+			}
+			if bean.Kind == model.BeanEJB {
+				if n := annotationAttrs(a.text)["name"]; n != "" {
+					bean.Name = n
+				}
+			}
+		}
+	}
+
+	if m := extendsRegex.FindStringSubmatch(line); m != nil {
+		bean.Types = append(bean.Types, simpleTypeName(m[1]))
+	}
+	if m := implementsRegex.FindStringSubmatch(line); m != nil {
+		for _, iface := range splitParams(m[1]) {
+			if t := simpleTypeName(iface); t != "" {
+				bean.Types = append(bean.Types, t)
+			}
+		}
+	}
+
+	bean.Qualifiers = annotationNames(anns)
+	for k, q := range bean.Qualifiers {
+		if q == "Named()" {
+			bean.Qualifiers[k] = "Named(" + decapitalize(name) + ")"
+		}
+	}
+	return bean, true
+}
+
+// injectionPoint builds an injection point from a "Type name" declaration.
+func injectionPoint(decl string, anns []beanAnnotation, className, annotation, beanName string) (model.InjectionPoint, bool) {
+	for _, a := range anns {
+		if nonBeanInjectionAnnotations[a.name] {
+			return model.InjectionPoint{}, false
+		}
+	}
+	typ, member, ok := parseMember(decl)
+	if !ok || builtinInjectionTypes[typ] {
+		return model.InjectionPoint{}, false
+	}
+
+	qualifiers := annotationNames(anns)
+	for k, q := range qualifiers {
+		if q == "Named()" {
+			qualifiers[k] = "Named(" + member + ")"
+		}
+	}
+	return model.InjectionPoint{
+		Class:      className,
+		Member:     member,
+		Type:       typ,
+		Qualifiers: qualifiers,
+		Annotation: annotation,
+		BeanName:   beanName,
+	}, true
+}
+
+// parseMember extracts the declared type and name from a field, parameter or method declaration.
+func parseMember(decl string) (string, string, bool) {
+	decl = strings.TrimSpace(decl)
+	for _, mod := range []string{"public ", "protected ", "private ", "static ", "final ", "transient ", "volatile ", "synchronized "} {
+		decl = strings.ReplaceAll(decl, mod, "")
+	}
+	m := memberDeclRegex.FindStringSubmatch(strings.TrimSpace(decl))
+	if m == nil || m[1] == "void" || m[1] == "return" {
+		return "", "", false
+	}
+	return simpleTypeName(m[1]), m[2], true
+}
+
+// splitLeadingAnnotations removes the annotations at the start of text and returns them
+// with the remaining declaration.
+func splitLeadingAnnotations(text string) ([]beanAnnotation, string) {
+	var anns []beanAnnotation
+	text = strings.TrimSpace(text)
+	for strings.HasPrefix(text, "@") && !strings.HasPrefix(text, "@interface") {
+		m := leadingAnnRegex.FindStringSubmatch(text)
+		if m == nil {
+			break
+		}
+		end := len(m[0])
+		if strings.HasPrefix(strings.TrimLeft(text[end:], " "), "(") {
+			open := strings.Index(text[end:], "(") + end
+			depth := 0
+			for k := open; k < len(text); k++ {
+				if text[k] == '(' {
+					depth++
+				} else if text[k] == ')' {
+					depth--
+					if depth == 0 {
+						end = k + 1
+						break
+					}
+				}
+			}
+			if depth != 0 {
+				end = len(text)
+			}
+		}
+		parts := strings.Split(m[1], ".")
+		anns = append(anns, beanAnnotation{name: parts[len(parts)-1], text: text[:end]})
+		text = strings.TrimSpace(text[end:])
+	}
+	return anns, text
+}
+
+// annotationNames returns the simple names of the annotations, rendering @Named as
+// Named(value); an empty value is filled in by the caller.
+func annotationNames(anns []beanAnnotation) []string {
+	var names []string
+	for _, a := range anns {
+		if a.name == "Named" {
+			value := ""
+			if q := quotedRegex.FindStringSubmatch(a.text); q != nil {
+				value = q[1]
+			}
+			names = append(names, "Named("+value+")")
+			continue
+		}
+		names = append(names, a.name)
+	}
+	return names
+}
+
+func hasAnnotation(anns []beanAnnotation, name string) bool {
+	_, ok := findAnnotation(anns, name)
+	return ok
+}
+
+func findAnnotation(anns []beanAnnotation, name string) (beanAnnotation, bool) {
+	for _, a := range anns {
+		if a.name == name {
+			return a, true
+		}
+	}
+	return beanAnnotation{}, false
+}
+
+// hasProducesCDI reports a CDI @Produces (JAX-RS @Produces always carries media types).
+func hasProducesCDI(anns []beanAnnotation) bool {
+	a, ok := findAnnotation(anns, "Produces")
+	return ok && !strings.Contains(a.text, "(")
+}
+
+func producerScope(anns []beanAnnotation) string {
+	for _, a := range anns {
+		if _, ok := scopeAnnotations[a.name]; ok {
+			return a.name
+		}
+	}
+	return "Dependent"
+}
+
+// producerName derives the default @Named name of a producer (getPrice -> price).
+func producerName(member string) string {
+	if strings.HasPrefix(member, "get") && len(member) > 3 {
+		return decapitalize(member[3:])
+	}
+	return member
+}
+
+// collectUntil joins lines starting at start until the closing token has been seen
+// at parenthesis depth zero.
+func collectUntil(lines []string, start int, first, token string) (string, int) {
+	text := first
+	for i := start; i < len(lines); i++ {
+		if i > start {
+			text += " " + strings.TrimSpace(lines[i])
+		}
+		if strings.Count(text, "(") <= strings.Count(text, ")") && strings.Contains(text, token) {
+			return text, i
+		}
+	}
+	return text, len(lines) - 1
+}
+
+// splitParams returns the comma-separated parts of the outermost parenthesized list
+// (or of the whole text if it has none), ignoring commas inside generics.
+func splitParams(text string) []string {
+	if open := strings.Index(text, "("); open >= 0 {
+		closeIdx := strings.LastIndex(text, ")")
+		if closeIdx > open {
+			text = text[open+1 : closeIdx]
+		}
+	}
+	var parts []string
+	depth, startIdx := 0, 0
+	for k, r := range text {
+		switch r {
+		case '<', '(':
+			depth++
+		case '>', ')':
+			depth--
+		case ',':
+			if depth == 0 {
+				parts = append(parts, strings.TrimSpace(text[startIdx:k]))
+				startIdx = k + 1
+			}
+		}
+	}
+	if last := strings.TrimSpace(text[startIdx:]); last != "" {
+		parts = append(parts, last)
+	}
+	return parts
+}
+
+// simpleTypeName strips package, generics and array markers (java.util.List<Foo> -> List).
+func simpleTypeName(t string) string {
+	t = strings.TrimSpace(t)
+	if idx := strings.Index(t, "<"); idx >= 0 {
+		t = t[:idx]
+	}
+	t = strings.TrimSuffix(strings.Fields(t + " ")[0], "[]")
+	parts := strings.Split(t, ".")
+	return parts[len(parts)-1]
+}
+
+func countBraces(text string) int {
+	return strings.Count(text, "{") - strings.Count(text, "}")
+}
+
+func qualify(pkg, name string) string {
+	if pkg == "" {
+		return name
+	}
+	return pkg + "." + name
+}
+
+func decapitalize(name string) string {
+	if name == "" {
+		return name
+	}
+	return strings.ToLower(name[:1]) + name[1:]
+}
+
+// ScanBeansXML returns the effective bean discovery mode (all, annotated, none) of a beans.xml.
+// A beans.xml without a version or with an empty body is an explicit (all) bean archive.
+func ScanBeansXML(tree *model.SourceTree, path string) (string, error) {
+	data, err := tree.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	if strings.TrimSpace(string(data)) == "" {
+		return "all", nil
+	}
+
+	var beans struct {
+		Version string `xml:"version,attr"`
+		Mode    string `xml:"bean-discovery-mode,attr"`
+	}
+	if err := xml.Unmarshal(data, &beans); err != nil {
+		return "", err
+	}
+	switch {
+	case beans.Mode != "":
+		return beans.Mode, nil
+	case beans.Version == "":
+		return "all", nil
+	default:
+		return "annotated", nil
+	}
+}
@@ -4,15 +4,14 @@ import (
 	"encoding/xml"
 	"io"
 	"jz/model"
-	"os"
 )
 
 // ScanDSComponents parses a list of Service-Component XML files and returns their metadata.
-func ScanDSComponents(paths []string) ([]model.DSComponent, error) {
+func ScanDSComponents(tree *model.SourceTree, paths []string) ([]model.DSComponent, error) {
 	var results []model.DSComponent
 
 	for _, path := range paths {
-		comp, err := parseDSFile(path)
+		comp, err := parseDSFile(tree, path)
 		// We ignore file read/parse errors to correctly handle cases where files might be missing or invalid
 		// but we still want to proceed with the others.
 		// Ignore individual file errors and continue processing other components.
@@ -49,8 +48,8 @@ type xmlReference struct {
 	Interface string `xml:"interface,attr"`
 }
 
-func parseDSFile(path string) (model.DSComponent, error) {
-	f, err := os.Open(path)
+func parseDSFile(tree *model.SourceTree, path string) (model.DSComponent, error) {
+	f, err := tree.Open(path)
 	if err != nil {
 		return model.DSComponent{}, err
 	}
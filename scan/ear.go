@@ -0,0 +1,81 @@
+package scan
+
+import (
+	"encoding/xml"
+	"io"
+	"jz/model"
+	"path/filepath"
+	"strings"
+)
+
+// ScanApplicationXML parses a Java EE META-INF/application.xml deployment descriptor.
+// Module paths are not resolved here; only the declared URIs and context roots are extracted.
+func ScanApplicationXML(tree *model.SourceTree, path string) (model.EnterpriseApp, error) {
+	f, err := tree.Open(path)
+	if err != nil {
+		return model.EnterpriseApp{}, err
+	}
+	defer f.Close()
+
+	data, err := io.ReadAll(f)
+	if err != nil {
+		return model.EnterpriseApp{}, err
+	}
+
+	var xa xmlEARApplication
+	if err := xml.Unmarshal(data, &xa); err != nil {
+		return model.EnterpriseApp{}, err
+	}
+
+	ear := model.EnterpriseApp{
+		Name:           strings.TrimSpace(xa.ApplicationName),
+		ApplicationXML: path,
+		RootPath:       filepath.Dir(filepath.Dir(path)),
+		Modules:        make([]model.EARModule, 0),
+	}
+	if ear.Name == "" {
+		ear.Name = strings.TrimSpace(xa.DisplayName)
+	}
+	if ear.Name == "" {
+		ear.Name = filepath.Base(ear.RootPath)
+	}
+
+	for _, m := range xa.Modules {
+		switch {
+		case m.Web.WebURI != "":
+			ear.Modules = append(ear.Modules, model.EARModule{
+				Type:        "web",
+				URI:         strings.TrimSpace(m.Web.WebURI),
+				ContextRoot: strings.TrimSpace(m.Web.ContextRoot),
+			})
+		case m.EJB != "":
+			ear.Modules = append(ear.Modules, model.EARModule{Type: "ejb", URI: strings.TrimSpace(m.EJB)})
+		case m.Java != "":
+			ear.Modules = append(ear.Modules, model.EARModule{Type: "java", URI: strings.TrimSpace(m.Java)})
+		case m.Connector != "":
+			ear.Modules = append(ear.Modules, model.EARModule{Type: "connector", URI: strings.TrimSpace(m.Connector)})
+		}
+	}
+
+	return ear, nil
+}
+
+// XML mapping structs
+
+type xmlEARApplication struct {
+	ApplicationName string         `xml:"application-name"`
+	DisplayName     string         `xml:"display-name"`
+	Modules         []xmlEARModule `xml:"module"`
+}
+
+type xmlEARModule struct {
+	Web       xmlEARWeb `xml:"web"`
+	EJB       string    `xml:"ejb"`
+	Java      string    `xml:"java"`
+	Connector string    `xml:"connector"`
+}
+
+type xmlEARWeb struct {
+	WebURI      string `xml:"web-uri"`
+	ContextRoot string `xml:"context-root"`
+}
@@ -0,0 +1,234 @@
+package scan
+
+import (
+	"encoding/xml"
+	"fmt"
+	"jz/model"
+	"os"
+	"regexp"
+	"strings"
+)
+
+var (
+	genericArgsRegex  = regexp.MustCompile(`<\s*([\w.]+)(?:\s*,\s*([\w.]+))?\s*>`)
+	targetEntityRegex = regexp.MustCompile(`\btargetEntity\s*=\s*([\w.]+)\.class`)
+)
+
+// relationAnnotations are the JPA relationship annotations.
+var relationAnnotations = []string{"OneToOne", "OneToMany", "ManyToOne", "ManyToMany"}
+
+// ScanEntities recursively walks the rootDir and extracts JPA @Entity classes with their
+// table, persistent fields and relationships.
+//
+// Limitations (AST-lite):
+//   - Only fields of the entity class itself are read (no @MappedSuperclass or @Embeddable).
+//   - Property (getter) access mappings are not read.
+func ScanEntities(tree *model.SourceTree, rootDir string) ([]model.Entity, error) {
+	var entities []model.Entity
+
+	err := tree.Walk(rootDir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() || !strings.HasSuffix(path, ".java") {
+			return nil
+		}
+		e, ok, err := scanEntityFile(tree, path)
+		// We ignore file reading errors to prevent stopping the entire walk
+		if err == nil && ok {
+			entities = append(entities, e)
+		}
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+	return entities, nil
+}
+
+func scanEntityFile(tree *model.SourceTree, filePath string) (model.Entity, bool, error) {
+	lines, err := readLines(tree, filePath)
+	if err != nil {
+		return model.Entity{}, false, err
+	}
+
+	e := model.Entity{SourceFile: filePath}
+	var pending []beanAnnotation
+	var pendingLine int
+	depth := 0
+
+	for i := 0; i < len(lines); i++ {
+		line := strings.TrimSpace(lines[i])
+		if line == "" || strings.HasPrefix(line, "//") || strings.HasPrefix(line, "*") || strings.HasPrefix(line, "/*") {
+			continue
+		}
+		if strings.HasPrefix(line, "package ") {
+			e.Package = strings.TrimSuffix(strings.TrimSpace(strings.TrimPrefix(line, "package ")), ";")
+			continue
+		}
+		if strings.HasPrefix(line, "import ") {
+			continue
+		}
+
+		if strings.HasPrefix(line, "@") && !strings.HasPrefix(line, "@interface") {
+			if len(pending) == 0 {
+				pendingLine = i + 1
+			}
+			text, end := collectAnnotation(lines, i)
+			anns, rest := splitLeadingAnnotations(strings.TrimSpace(text))
+			pending = append(pending, anns...)
+			i = end
+			if rest == "" {
+				continue
+			}
+			line = rest
+		}
+		if len(pending) == 0 {
+			pendingLine = i + 1
+		}
+
+		lineDepth := depth
+		depth += countBraces(line)
+
+		if e.Class == "" {
+			m := typeDeclRegex.FindStringSubmatch(line)
+			if m == nil {
+				pending = nil
+				continue
+			}
+			ann, ok := findAnnotation(pending, "Entity")
+			if m[1] != "class" || !ok {
+				return e, false, nil
+			}
+			e.Class = m[2]
+			e.Name = firstNonEmpty(annotationAttrs(ann.text)["name"], e.Class)
+			e.Table = e.Name
+			if t, ok := findAnnotation(pending, "Table"); ok {
+				e.Table = firstNonEmpty(annotationAttrs(t.text)["name"], e.Table)
+			}
+			e.Evidence = fmt.Sprintf("%s:%d", filePath, pendingLine)
+			pending = nil
+			continue
+		}
+
+		// Fields of the entity class only
+		if lineDepth != 1 || strings.Contains(line, "(") || !strings.HasSuffix(line, ";") {
+			pending = nil
+			continue
+		}
+		decl := line
+		if strings.Contains(decl, "static ") || strings.Contains(decl, "transient ") || hasAnnotation(pending, "Transient") {
+			pending = nil
+			continue
+		}
+		if eq := strings.Index(decl, "="); eq >= 0 {
+			decl = decl[:eq] + ";"
+		}
+		typ, field, ok := parseRawMember(decl)
+		if !ok {
+			pending = nil
+			continue
+		}
+
+		if rel, ok := entityRelation(pending, typ, field); ok {
+			e.Relations = append(e.Relations, rel)
+			pending = nil
+			continue
+		}
+		col := model.EntityColumn{
+			Field:  field,
+			Column: field,
+			Type:   simpleTypeName(typ),
+			ID:     hasAnnotation(pending, "Id") || hasAnnotation(pending, "EmbeddedId"),
+		}
+		if c, ok := findAnnotation(pending, "Column"); ok {
+			col.Column = firstNonEmpty(annotationAttrs(c.text)["name"], field)
+		}
+		e.Columns = append(e.Columns, col)
+		pending = nil
+	}
+
+	return e, e.Class != "", nil
+}
+
+// parseRawMember returns the declared type (with generics) and the name of a field declaration.
+func parseRawMember(decl string) (string, string, bool) {
+	for _, mod := range []string{"public ", "protected ", "private ", "final ", "volatile "} {
+		decl = strings.ReplaceAll(decl, mod, "")
+	}
+	m := memberDeclRegex.FindStringSubmatch(strings.TrimSpace(decl))
+	if m == nil {
+		return "", "", false
+	}
+	return m[1], m[2], true
+}
+
+// entityRelation builds the relationship of a field carrying a relationship annotation.
+// The target is the targetEntity attribute, the element type of a collection (the value
+// type of a map) or the field type.
+func entityRelation(anns []beanAnnotation, typ, field string) (model.EntityRelation, bool) {
+	for _, kind := range relationAnnotations {
+		a, ok := findAnnotation(anns, kind)
+		if !ok {
+			continue
+		}
+		rel := model.EntityRelation{
+			Kind:     kind,
+			Field:    field,
+			Target:   simpleTypeName(typ),
+			MappedBy: annotationAttrs(a.text)["mappedBy"],
+		}
+		if g := genericArgsRegex.FindStringSubmatch(typ); g != nil {
+			rel.Target = simpleTypeName(firstNonEmpty(g[2], g[1]))
+		}
+		if t := targetEntityRegex.FindStringSubmatch(a.text); t != nil {
+			rel.Target = simpleTypeName(t[1])
+		}
+		if j, ok := findAnnotation(anns, "JoinColumn"); ok {
+			rel.JoinColumn = annotationAttrs(j.text)["name"]
+		}
+		return rel, true
+	}
+	return model.EntityRelation{}, false
+}
+
+// ScanPersistenceXML parses a JPA persistence.xml file and returns its persistence units.
+func ScanPersistenceXML(tree *model.SourceTree, path string) ([]model.PersistenceUnit, error) {
+	data, err := tree.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var xp xmlPersistence
+	if err := xml.Unmarshal(data, &xp); err != nil {
+		return nil, err
+	}
+
+	var units []model.PersistenceUnit
+	for _, u := range xp.Units {
+		units = append(units, model.PersistenceUnit{
+			Name:             u.Name,
+			TransactionType:  u.TransactionType,
+			JTADataSource:    strings.TrimSpace(u.JTADataSource),
+			NonJTADataSource: strings.TrimSpace(u.NonJTADataSource),
+			Classes:          trimAll(u.Classes),
+			SourceFile:       path,
+		})
+	}
+	return units, nil
+}
+
+// XML mapping structs
+
+type xmlPersistence struct {
+	Units []xmlPersistenceUnit `xml:"persistence-unit"`
+}
+
+type xmlPersistenceUnit struct {
+	Name             string   `xml:"name,attr"`
+	TransactionType  string   `xml:"transaction-type,attr"`
+	JTADataSource    string   `xml:"jta-data-source"`
+	NonJTADataSource string   `xml:"non-jta-data-source"`
+	Classes          []string `xml:"class"`
+}
@@ -0,0 +1,107 @@
+package scan
+
+import (
+	"fmt"
+	"jz/model"
+	"os"
+	"regexp"
+	"strings"
+)
+
+var (
+	exceptionMapperRegex = regexp.MustCompile(`\bExceptionMapper\s*<\s*([\w.]+)\s*>`)
+	toResponseRegex      = regexp.MustCompile(`\bResponse\s+toResponse\s*\(`)
+)
+
+// ScanExceptionMappers recursively walks the rootDir and extracts JAX-RS ExceptionMapper<T>
+// providers. The statuses are the ones set by Response builders in toResponse; a mapper
+// that derives the status at runtime (e.g. e.getResponse()) has no statuses.
+func ScanExceptionMappers(tree *model.SourceTree, rootDir string) ([]model.ExceptionMapper, error) {
+	var mappers []model.ExceptionMapper
+
+	err := tree.Walk(rootDir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() || !strings.HasSuffix(path, ".java") {
+			return nil
+		}
+		m, ok, err := scanExceptionMapperFile(tree, path)
+		// We ignore file reading errors to prevent stopping the entire walk
+		if err == nil && ok {
+			mappers = append(mappers, m)
+		}
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+	return mappers, nil
+}
+
+func scanExceptionMapperFile(tree *model.SourceTree, filePath string) (model.ExceptionMapper, bool, error) {
+	lines, err := readLines(tree, filePath)
+	if err != nil {
+		return model.ExceptionMapper{}, false, err
+	}
+
+	m := model.ExceptionMapper{SourceFile: filePath}
+	depth := 0
+	bodyDepth := -1 // Depth outside the toResponse body while inside it
+
+	for i, raw := range lines {
+		line := strings.TrimSpace(raw)
+		if strings.HasPrefix(line, "//") || strings.HasPrefix(line, "*") || strings.HasPrefix(line, "/*") {
+			continue
+		}
+		lineDepth := depth
+		depth += countBraces(line)
+
+		if m.Class == "" {
+			t := typeDeclRegex.FindStringSubmatch(line)
+			if t == nil || t[1] != "class" {
+				continue
+			}
+			// The implements clause may follow on the next lines
+			header := line
+			for k := i + 1; !strings.Contains(header, "{") && k < len(lines); k++ {
+				header += " " + strings.TrimSpace(lines[k])
+			}
+			em := exceptionMapperRegex.FindStringSubmatch(header)
+			if em == nil {
+				return m, false, nil
+			}
+			m.Class = t[2]
+			m.Exception = simpleTypeName(em[1])
+			m.Evidence = fmt.Sprintf("%s:%d", filePath, i+1)
+			continue
+		}
+
+		if bodyDepth < 0 && toResponseRegex.MatchString(line) {
+			bodyDepth = lineDepth
+		}
+		if bodyDepth < 0 {
+			continue
+		}
+		for _, status := range ResponseStatuses(line) {
+			if !containsInt(m.Statuses, status) {
+				m.Statuses = append(m.Statuses, status)
+			}
+		}
+		if depth <= bodyDepth && strings.Contains(line, "}") {
+			bodyDepth = -1
+		}
+	}
+
+	return m, m.Class != "", nil
+}
+
+func containsInt(values []int, v int) bool {
+	for _, x := range values {
+		if x == v {
+			return true
+		}
+	}
+	return false
+}
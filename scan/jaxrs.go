@@ -1,18 +1,20 @@
 package scan
 
 import (
-	"bufio"
+	"fmt"
 	"jz/model"
 	"os"
-	"path/filepath"
+	"regexp"
+	"slices"
+	"sort"
 	"strings"
 )
 
 // Scan recursively walks the rootDir and extracts JAX-RS entry points.
-func Scan(rootDir string) ([]model.EntryPoint, error) {
+func Scan(tree *model.SourceTree, rootDir string) ([]model.EntryPoint, error) {
 	var entryPoints []model.EntryPoint
 
-	err := filepath.Walk(rootDir, func(path string, info os.FileInfo, err error) error {
+	err := tree.Walk(rootDir, func(path string, info os.FileInfo, err error) error {
 		if err != nil {
 			return err
 		}
@@ -23,7 +25,7 @@ func Scan(rootDir string) ([]model.EntryPoint, error) {
 			return nil
 		}
 
-		eps, err := scanFile(path)
+		eps, err := scanFile(tree, path)
 		// We ignore file reading errors to prevent stopping the entire walk
 		if err == nil {
 			entryPoints = append(entryPoints, eps...)
@@ -38,14 +40,11 @@ func Scan(rootDir string) ([]model.EntryPoint, error) {
 	return entryPoints, nil
 }
 
-func scanFile(filePath string) ([]model.EntryPoint, error) {
-	f, err := os.Open(filePath)
+func scanFile(tree *model.SourceTree, filePath string) ([]model.EntryPoint, error) {
+	lines, err := readLines(tree, filePath)
 	if err != nil {
 		return nil, err
 	}
-	defer f.Close()
-
-	scanner := bufio.NewScanner(f)
 
 	var entryPoints []model.EntryPoint
 
@@ -59,8 +58,12 @@ func scanFile(filePath string) ([]model.EntryPoint, error) {
 
 	var hasHTTPMethod bool
 
-	for scanner.Scan() {
-		line := strings.TrimSpace(scanner.Text())
+	// Media types and auth annotations: class-level values are the defaults of every
+	// method, method-level values replace them
+	var class, method jaxrsAnnotations
+
+	for i := 0; i < len(lines); i++ {
+		line := strings.TrimSpace(lines[i])
 		if line == "" {
 			continue
 		}
@@ -82,6 +85,15 @@ func scanFile(filePath string) ([]model.EntryPoint, error) {
 			continue
 		}
 
+		// Media types and auth annotations of the class or of the next method
+		if className == "" {
+			if class.add(line) {
+				continue
+			}
+		} else if method.add(line) {
+			continue
+		}
+
 		// 3. HTTP Methods
 		// @GET, @POST, etc.
 		if isHTTPMethod(line) {
@@ -132,27 +144,228 @@ func scanFile(filePath string) ([]model.EntryPoint, error) {
 
 					fullPath := buildPath(classPath, methodPath)
 
+					// Parameters may span several lines; they are consumed here so that
+					// @PathParam lines are not taken for method-level @Path annotations.
+					signature, end := collectUntil(lines, i, line, ")")
 					ep := model.EntryPoint{
 						Method:     methodProto,
 						Path:       fullPath,
 						Handler:    className + "." + methodName,
 						SourceFile: filePath,
+						Kind:       model.EntryPointJAXRS,
+						Params:     requestParams(signature[idx:], fmt.Sprintf("%s:%d", filePath, i+1)),
+						Consumes:   orDefault(method.consumes, class.consumes),
+						Produces:   orDefault(method.produces, class.produces),
+					}
+					// The roles go with the auth annotations they were declared with
+					ep.Auth, ep.Roles = class.auth, class.roles
+					if len(method.auth) > 0 {
+						ep.Auth, ep.Roles = method.auth, method.roles
 					}
 					entryPoints = append(entryPoints, ep)
+					i = end
 				}
 
 				// Reset method state
 				methodPath = ""
 				methodProto = ""
+				method = jaxrsAnnotations{}
 
 				hasHTTPMethod = false
 			}
+		} else if className != "" && isMemberDeclaration(line) {
+			// Annotations of other members do not carry over to the next resource method
+			method = jaxrsAnnotations{}
 		}
 	}
 
 	return entryPoints, nil
 }
 
+// jaxrsAnnotations are the @Consumes/@Produces media types, auth annotations and
+// @RolesAllowed roles declared on a resource class or method.
+type jaxrsAnnotations struct {
+	consumes, produces, auth, roles []string
+}
+
+// add records the annotation a line holds, and reports whether it was one.
+func (a *jaxrsAnnotations) add(line string) bool {
+	switch {
+	case strings.HasPrefix(line, "@Consumes"):
+		a.consumes = appendMediaTypes(a.consumes, line)
+		return true
+	case strings.HasPrefix(line, "@Produces"):
+		a.produces = appendMediaTypes(a.produces, line)
+		return true
+	}
+	for _, ann := range model.AuthAnnotations {
+		if line == ann || strings.HasPrefix(line, ann+"(") || strings.HasPrefix(line, ann+" ") {
+			if !slices.Contains(a.auth, ann) {
+				a.auth = append(a.auth, ann)
+				sort.Strings(a.auth)
+			}
+			if ann == "@RolesAllowed" {
+				a.roles = allowedRoles(line)
+			}
+			return true
+		}
+	}
+	return false
+}
+
+// mediaTypeConstants are the media types of the javax/jakarta.ws.rs.core.MediaType
+// constants (the *_TYPE variants name the same types).
+var mediaTypeConstants = map[string]string{
+	"APPLICATION_ATOM_XML":        "application/atom+xml",
+	"APPLICATION_FORM_URLENCODED": "application/x-www-form-urlencoded",
+	"APPLICATION_JSON":            "application/json",
+	"APPLICATION_JSON_PATCH_JSON": "application/json-patch+json",
+	"APPLICATION_OCTET_STREAM":    "application/octet-stream",
+	"APPLICATION_SVG_XML":         "application/svg+xml",
+	"APPLICATION_XHTML_XML":       "application/xhtml+xml",
+	"APPLICATION_XML":             "application/xml",
+	"MULTIPART_FORM_DATA":         "multipart/form-data",
+	"SERVER_SENT_EVENTS":          "text/event-stream",
+	"TEXT_HTML":                   "text/html",
+	"TEXT_PLAIN":                  "text/plain",
+	"TEXT_XML":                    "text/xml",
+	"WILDCARD":                    "*/*",
+}
+
+// mediaTypeArgRegex matches the values of a @Consumes, @Produces or @RolesAllowed
+// annotation: string literals and constant references.
+var mediaTypeArgRegex = regexp.MustCompile(`"([^"]*)"|([A-Za-z_][\w.]*)`)
+
+// appendMediaTypes adds the media types of a @Consumes or @Produces line, lowercased and
+// sorted. MediaType constants are mapped to their types; other expressions are kept as
+// written, as opaque values (see model.MediaTypesKnown), so that the annotation is not
+// mistaken for an absent one.
+func appendMediaTypes(types []string, line string) []string {
+	start, end := strings.Index(line, "("), strings.LastIndex(line, ")")
+	if start < 0 || end < start {
+		return types
+	}
+	add := func(mt string) {
+		if mt != "" && !slices.Contains(types, mt) {
+			types = append(types, mt)
+		}
+	}
+	for _, m := range mediaTypeArgRegex.FindAllStringSubmatch(line[start+1:end], -1) {
+		if m[2] == "" {
+			for _, mt := range strings.Split(m[1], ",") {
+				add(strings.ToLower(strings.TrimSpace(mt)))
+			}
+			continue
+		}
+		if m[2] == "value" {
+			continue
+		}
+		// MediaType.APPLICATION_JSON, its qualified form, or a static import
+		qualifier, name := "", m[2]
+		if i := strings.LastIndex(m[2], "."); i >= 0 {
+			qualifier, name = m[2][:i], m[2][i+1:]
+		}
+		mt, ok := mediaTypeConstants[strings.TrimSuffix(name, "_TYPE")]
+		if ok && (qualifier == "" || qualifier == "MediaType" || strings.HasSuffix(qualifier, ".MediaType")) {
+			add(mt)
+		} else {
+			add(m[2])
+		}
+	}
+	sort.Strings(types)
+	return types
+}
+
+// allowedRoles returns the roles of a @RolesAllowed line, sorted. Roles are only known
+// when every value is a string literal: nil is returned when one is a constant reference.
+func allowedRoles(line string) []string {
+	start, end := strings.Index(line, "("), strings.LastIndex(line, ")")
+	if start < 0 || end < start {
+		return nil
+	}
+	var roles []string
+	for _, m := range mediaTypeArgRegex.FindAllStringSubmatch(line[start+1:end], -1) {
+		switch {
+		case m[2] == "value":
+		case m[2] != "":
+			return nil
+		case !slices.Contains(roles, m[1]):
+			roles = append(roles, m[1])
+		}
+	}
+	sort.Strings(roles)
+	return roles
+}
+
+func orDefault(values, defaults []string) []string {
+	if len(values) > 0 {
+		return values
+	}
+	return defaults
+}
+
+// isMemberDeclaration reports whether a line declares a field or method of a class.
+func isMemberDeclaration(line string) bool {
+	if strings.HasPrefix(line, "@") {
+		return false
+	}
+	return strings.HasSuffix(line, ";") || strings.HasSuffix(line, "{") ||
+		strings.HasPrefix(line, "public ") || strings.HasPrefix(line, "protected ") || strings.HasPrefix(line, "private ")
+}
+
+// paramAnnotations map the JAX-RS parameter annotations to where the value is read from.
+var paramAnnotations = map[string]string{
+	"PathParam":   model.ParamInPath,
+	"QueryParam":  model.ParamInQuery,
+	"HeaderParam": model.ParamInHeader,
+	"CookieParam": model.ParamInCookie,
+	"FormParam":   model.ParamInForm,
+}
+
+// requestParams returns the annotated request parameters of the parameter list that text
+// starts with. Arguments without a parameter annotation (entity bodies, @Context,
+// @BeanParam) are skipped.
+func requestParams(text, evidence string) []model.RESTParam {
+	depth := 0
+	for k, r := range text {
+		if r == '(' {
+			depth++
+		} else if r == ')' {
+			depth--
+			if depth == 0 {
+				text = text[:k+1]
+				break
+			}
+		}
+	}
+	var params []model.RESTParam
+	for _, decl := range splitParams(text) {
+		anns, rest := splitLeadingAnnotations(decl)
+		var param model.RESTParam
+		for _, a := range anns {
+			q := quotedRegex.FindStringSubmatch(a.text)
+			if q == nil {
+				continue
+			}
+			if in, ok := paramAnnotations[a.name]; ok {
+				param.Name, param.In = q[1], in
+			} else if a.name == "DefaultValue" {
+				param.Default = q[1]
+			}
+		}
+		if param.Name == "" {
+			continue
+		}
+		fields := strings.Fields(strings.TrimPrefix(rest, "final "))
+		if len(fields) > 1 {
+			param.Type = strings.Join(fields[:len(fields)-1], " ")
+		}
+		param.Evidence = evidence
+		params = append(params, param)
+	}
+	return params
+}
+
 func extractPath(line string) string {
 	// @Path("/foo") or @Path(value = "/foo")
 	// Simple string extraction: content between quotes
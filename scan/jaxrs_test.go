@@ -0,0 +1,47 @@
+package scan
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestAppendMediaTypes(t *testing.T) {
+	tests := []struct {
+		line string
+		want []string
+	}{
+		{`@Produces("application/json")`, []string{"application/json"}},
+		{`@Produces("application/json, text/plain")`, []string{"application/json", "text/plain"}},
+		{`@Produces({"Application/XML", "application/json"})`, []string{"application/json", "application/xml"}},
+		{`@Produces(MediaType.APPLICATION_JSON)`, []string{"application/json"}},
+		{`@Consumes(value = MediaType.APPLICATION_FORM_URLENCODED)`, []string{"application/x-www-form-urlencoded"}},
+		{`@Produces({MediaType.TEXT_PLAIN, "text/csv"})`, []string{"text/csv", "text/plain"}},
+		{`@Produces(jakarta.ws.rs.core.MediaType.APPLICATION_XML_TYPE)`, []string{"application/xml"}},
+		{`@Produces(APPLICATION_JSON)`, []string{"application/json"}},
+		{`@Produces(Types.ORDER_V2)`, []string{"Types.ORDER_V2"}},
+		{`@Produces`, nil},
+	}
+	for _, tt := range tests {
+		if got := appendMediaTypes(nil, tt.line); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("appendMediaTypes(%s) = %v, want %v", tt.line, got, tt.want)
+		}
+	}
+}
+
+func TestAllowedRoles(t *testing.T) {
+	tests := []struct {
+		line string
+		want []string
+	}{
+		{`@RolesAllowed("admin")`, []string{"admin"}},
+		{`@RolesAllowed({"user", "admin"})`, []string{"admin", "user"}},
+		{`@RolesAllowed(value = {"user"})`, []string{"user"}},
+		{`@RolesAllowed({Roles.ADMIN, "user"})`, nil},
+		{`@RolesAllowed`, nil},
+	}
+	for _, tt := range tests {
+		if got := allowedRoles(tt.line); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("allowedRoles(%s) = %v, want %v", tt.line, got, tt.want)
+		}
+	}
+}
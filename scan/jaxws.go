@@ -0,0 +1,444 @@
+package scan
+
+import (
+	"bufio"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"jz/model"
+	"os"
+	"regexp"
+	"strings"
+)
+
+var (
+	annotationAttrRegex   = regexp.MustCompile(`(\w+)\s*=\s*"([^"]*)"`)
+	qnameRegex            = regexp.MustCompile(`new\s+QName\(\s*"([^"]*)"\s*,\s*"([^"]*)"\s*\)`)
+	newInstanceRegex      = regexp.MustCompile(`new\s+(\w+)\s*\(`)
+	serviceRefRegex       = regexp.MustCompile(`@WebServiceRef\(\s*(?:value\s*=\s*)?(\w+)\.class`)
+	portAcquireRegex      = regexp.MustCompile(`\.get(?:\w*Port)\s*\(`)
+	httpLiteralRegex      = regexp.MustCompile(`"(https?://[^"]*)"`)
+	webMethodExcludeRegex = regexp.MustCompile(`\bexclude\s*=\s*true\b`)
+)
+
+// ScanSOAPEndpoints recursively walks the rootDir and extracts JAX-WS @WebService implementations.
+//
+// Limitations (AST-lite):
+//   - Only implementation classes are reported; SEI interfaces are skipped.
+//   - Every public non-static method is an operation unless annotated with
+//     @WebMethod(exclude = true) (JAX-WS 2.x default); @WebMethod only renames it.
+//   - Only literal annotation attributes are used.
+func ScanSOAPEndpoints(tree *model.SourceTree, rootDir string) ([]model.SOAPEndpoint, error) {
+	var endpoints []model.SOAPEndpoint
+
+	err := tree.Walk(rootDir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() || !strings.HasSuffix(path, ".java") {
+			return nil
+		}
+		ep, ok, err := scanSOAPEndpointFile(tree, path)
+		// We ignore file reading errors to prevent stopping the entire walk
+		if err == nil && ok {
+			endpoints = append(endpoints, ep)
+		}
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+	return endpoints, nil
+}
+
+func scanSOAPEndpointFile(tree *model.SourceTree, filePath string) (model.SOAPEndpoint, bool, error) {
+	lines, err := readLines(tree, filePath)
+	if err != nil {
+		return model.SOAPEndpoint{}, false, err
+	}
+
+	var pkg, className string
+	var annotation string
+	var found bool
+	var depth int
+
+	type candidate struct {
+		method    string
+		operation string
+	}
+	var methods []candidate
+	var pendingOpName string
+	var pendingExclude bool
+
+	for i := 0; i < len(lines); i++ {
+		line := strings.TrimSpace(lines[i])
+		if line == "" {
+			continue
+		}
+
+		if strings.HasPrefix(line, "package ") {
+			pkg = strings.TrimSuffix(strings.TrimSpace(strings.TrimPrefix(line, "package ")), ";")
+			continue
+		}
+
+		if className == "" {
+			if line == "@WebService" || strings.HasPrefix(line, "@WebService(") {
+				found = true
+				annotation, i = collectAnnotation(lines, i)
+				continue
+			}
+			if found && strings.Contains(line, "interface ") {
+				return model.SOAPEndpoint{}, false, nil
+			}
+			if found && strings.Contains(line, "class ") {
+				parts := strings.Fields(line)
+				for j, p := range parts {
+					if p == "class" && j+1 < len(parts) {
+						className = strings.Split(strings.Split(parts[j+1], "{")[0], "<")[0]
+					}
+				}
+			}
+			depth += strings.Count(line, "{") - strings.Count(line, "}")
+			continue
+		}
+
+		// Method-level annotations and declarations (class body only)
+		if depth == 1 {
+			if strings.HasPrefix(line, "@WebMethod") {
+				var text string
+				text, i = collectAnnotation(lines, i)
+				pendingOpName = annotationAttrs(text)["operationName"]
+				pendingExclude = webMethodExcludeRegex.MatchString(text)
+				continue
+			}
+			if strings.HasPrefix(line, "public ") && strings.Contains(line, "(") && !strings.HasSuffix(line, ";") &&
+				!strings.Contains(line, " static ") && !strings.Contains(line, " class ") {
+				pre := strings.Fields(line[:strings.Index(line, "(")])
+				if len(pre) > 0 {
+					name := pre[len(pre)-1]
+					if name != className && !pendingExclude {
+						opName := name
+						if pendingOpName != "" {
+							opName = pendingOpName
+						}
+						methods = append(methods, candidate{method: name, operation: opName})
+					}
+				}
+				pendingOpName = ""
+				pendingExclude = false
+			}
+		}
+		depth += strings.Count(line, "{") - strings.Count(line, "}")
+	}
+
+	if !found || className == "" {
+		return model.SOAPEndpoint{}, false, nil
+	}
+
+	attrs := annotationAttrs(annotation)
+	ep := model.SOAPEndpoint{
+		ServiceName:       attrs["serviceName"],
+		Namespace:         attrs["targetNamespace"],
+		PortName:          attrs["portName"],
+		EndpointInterface: attrs["endpointInterface"],
+		Class:             className,
+		SourceFile:        filePath,
+	}
+	if ep.ServiceName == "" {
+		ep.ServiceName = className + "Service"
+	}
+	if ep.Namespace == "" {
+		ep.Namespace = namespaceFromPackage(pkg)
+	}
+
+	for _, m := range methods {
+		ep.Operations = append(ep.Operations, model.SOAPOperation{
+			Name:    m.operation,
+			Handler: className + "." + m.method,
+		})
+	}
+
+	return ep, true, nil
+}
+
+// ScanSOAPClients recursively walks the rootDir and extracts JAX-WS client invocations.
+//
+// A file is considered a client when it acquires a port (getPort(...) or a generated
+// get<Name>Port() accessor) or declares a @WebServiceRef. The target is identified by
+// a literal new QName(ns, local), a reference to a @WebServiceClient stub, or a literal
+// http(s) endpoint address. Generated stubs themselves are not reported as calls.
+func ScanSOAPClients(tree *model.SourceTree, rootDir string) ([]model.SOAPCall, error) {
+	var files []string
+	err := tree.Walk(rootDir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if !info.IsDir() && strings.HasSuffix(path, ".java") {
+			files = append(files, path)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Pass 1: generated client stubs (class name -> service QName)
+	stubs := make(map[string]string)
+	stubFiles := make(map[string]bool)
+	for _, path := range files {
+		lines, err := readLines(tree, path)
+		if err != nil {
+			continue
+		}
+		var pkg string
+		for i := 0; i < len(lines); i++ {
+			line := strings.TrimSpace(lines[i])
+			if strings.HasPrefix(line, "package ") {
+				pkg = strings.TrimSuffix(strings.TrimSpace(strings.TrimPrefix(line, "package ")), ";")
+			}
+			if !strings.HasPrefix(line, "@WebServiceClient") {
+				continue
+			}
+			var text string
+			text, i = collectAnnotation(lines, i)
+			attrs := annotationAttrs(text)
+			for j := i + 1; j < len(lines); j++ {
+				if name := declaredClassName(lines[j]); name != "" {
+					ns := attrs["targetNamespace"]
+					if ns == "" {
+						ns = namespaceFromPackage(pkg)
+					}
+					local := attrs["name"]
+					if local == "" {
+						local = name
+					}
+					stubs[name] = "{" + ns + "}" + local
+					stubFiles[path] = true
+					break
+				}
+			}
+			break
+		}
+	}
+
+	// Pass 2: client usages
+	var calls []model.SOAPCall
+	for _, path := range files {
+		if stubFiles[path] {
+			continue
+		}
+		lines, err := readLines(tree, path)
+		if err != nil {
+			continue
+		}
+
+		var className string
+		var hasPort bool
+		var qnames []string
+		qnameLines := make(map[string]int)
+		var addresses []string
+		addressLines := make(map[string]int)
+
+		addQName := func(q string, line int) {
+			if _, ok := qnameLines[q]; !ok {
+				qnames = append(qnames, q)
+				qnameLines[q] = line
+			}
+		}
+
+		for i, raw := range lines {
+			line := strings.TrimSpace(raw)
+			if className == "" {
+				className = declaredClassName(line)
+			}
+			if portAcquireRegex.MatchString(line) {
+				hasPort = true
+			}
+			if m := serviceRefRegex.FindStringSubmatch(line); m != nil {
+				hasPort = true
+				if qname, ok := stubs[m[1]]; ok {
+					addQName(qname, i+1)
+				}
+			}
+			for _, m := range qnameRegex.FindAllStringSubmatch(line, -1) {
+				addQName("{"+m[1]+"}"+m[2], i+1)
+			}
+			for _, m := range newInstanceRegex.FindAllStringSubmatch(line, -1) {
+				if qname, ok := stubs[m[1]]; ok {
+					addQName(qname, i+1)
+				}
+			}
+			if strings.Contains(line, "ENDPOINT_ADDRESS_PROPERTY") || strings.Contains(line, "Service.create(") {
+				// QName namespaces are URIs too; they are not endpoint addresses
+				withoutQNames := qnameRegex.ReplaceAllString(line, "")
+				for _, m := range httpLiteralRegex.FindAllStringSubmatch(withoutQNames, -1) {
+					addr := strings.TrimSuffix(strings.TrimSuffix(m[1], "?wsdl"), "?WSDL")
+					if _, ok := addressLines[addr]; !ok {
+						addresses = append(addresses, addr)
+						addressLines[addr] = i + 1
+					}
+				}
+			}
+		}
+
+		if !hasPort || (len(qnames) == 0 && len(addresses) == 0) {
+			continue
+		}
+
+		// An address is only attributed to a QName when it is the single literal address in the file
+		address := ""
+		if len(addresses) == 1 {
+			address = addresses[0]
+		}
+
+		if len(qnames) > 0 {
+			for _, q := range qnames {
+				calls = append(calls, model.SOAPCall{
+					FromClass:    className,
+					SourceFile:   path,
+					Evidence:     fmt.Sprintf("%s:%d", path, qnameLines[q]),
+					ServiceQName: q,
+					Address:      address,
+					Confidence:   model.ConfidenceHigh,
+				})
+			}
+			continue
+		}
+		for _, a := range addresses {
+			calls = append(calls, model.SOAPCall{
+				FromClass:  className,
+				SourceFile: path,
+				Evidence:   fmt.Sprintf("%s:%d", path, addressLines[a]),
+				Address:    a,
+				Confidence: model.ConfidenceMedium,
+			})
+		}
+	}
+
+	return calls, nil
+}
+
+// ScanWSDL parses the service and port definitions of a WSDL 1.1 document.
+func ScanWSDL(tree *model.SourceTree, path string) (model.WSDLDefinition, error) {
+	f, err := tree.Open(path)
+	if err != nil {
+		return model.WSDLDefinition{}, err
+	}
+	defer f.Close()
+
+	data, err := io.ReadAll(f)
+	if err != nil {
+		return model.WSDLDefinition{}, err
+	}
+
+	var xd xmlWSDLDefinitions
+	if err := xml.Unmarshal(data, &xd); err != nil {
+		return model.WSDLDefinition{}, err
+	}
+
+	def := model.WSDLDefinition{
+		Path:            path,
+		TargetNamespace: xd.TargetNamespace,
+	}
+	for _, s := range xd.Services {
+		svc := model.WSDLService{Name: s.Name}
+		for _, p := range s.Ports {
+			svc.Ports = append(svc.Ports, model.WSDLPort{
+				Name:    p.Name,
+				Binding: p.Binding,
+				Address: strings.TrimSpace(p.Address.Location),
+			})
+		}
+		def.Services = append(def.Services, svc)
+	}
+
+	return def, nil
+}
+
+// collectAnnotation joins a (possibly multi-line) annotation starting at lines[start]
+// and returns the flattened text and the index of its last line.
+func collectAnnotation(lines []string, start int) (string, int) {
+	var sb strings.Builder
+	depth := 0
+	for i := start; i < len(lines); i++ {
+		line := strings.TrimSpace(lines[i])
+		sb.WriteString(line)
+		sb.WriteString(" ")
+		depth += strings.Count(line, "(") - strings.Count(line, ")")
+		if depth <= 0 {
+			return sb.String(), i
+		}
+	}
+	return sb.String(), len(lines) - 1
+}
+
+func annotationAttrs(text string) map[string]string {
+	attrs := make(map[string]string)
+	for _, m := range annotationAttrRegex.FindAllStringSubmatch(text, -1) {
+		if _, ok := attrs[m[1]]; !ok {
+			attrs[m[1]] = m[2]
+		}
+	}
+	return attrs
+}
+
+// declaredClassName returns the class name declared on the line, if any.
+func declaredClassName(line string) string {
+	parts := strings.Fields(line)
+	for i, p := range parts {
+		if p == "class" && i+1 < len(parts) {
+			return strings.Split(strings.Split(parts[i+1], "{")[0], "<")[0]
+		}
+	}
+	return ""
+}
+
+// namespaceFromPackage derives the default JAX-WS target namespace (com.example.ws -> http://ws.example.com/).
+func namespaceFromPackage(pkg string) string {
+	if pkg == "" {
+		return ""
+	}
+	parts := strings.Split(pkg, ".")
+	for i, j := 0, len(parts)-1; i < j; i, j = i+1, j-1 {
+		parts[i], parts[j] = parts[j], parts[i]
+	}
+	return "http://" + strings.Join(parts, ".") + "/"
+}
+
+func readLines(tree *model.SourceTree, path string) ([]string, error) {
+	f, err := tree.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var lines []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+	}
+	return lines, scanner.Err()
+}
+
+// XML mapping structs
+
+type xmlWSDLDefinitions struct {
+	TargetNamespace string           `xml:"targetNamespace,attr"`
+	Services        []xmlWSDLService `xml:"service"`
+}
+
+type xmlWSDLService struct {
+	Name  string        `xml:"name,attr"`
+	Ports []xmlWSDLPort `xml:"port"`
+}
+
+type xmlWSDLPort struct {
+	Name    string         `xml:"name,attr"`
+	Binding string         `xml:"binding,attr"`
+	Address xmlWSDLAddress `xml:"address"`
+}
+
+type xmlWSDLAddress struct {
+	Location string `xml:"location,attr"`
+}
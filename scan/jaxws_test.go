@@ -0,0 +1,75 @@
+package scan
+
+import (
+	"reflect"
+	"testing"
+	"testing/fstest"
+)
+
+func TestScanSOAPEndpointOperations(t *testing.T) {
+	tests := []struct {
+		name   string
+		source string
+		want   []string // operation=handler
+	}{
+		{
+			name: "every public method",
+			source: `package shop;
+
+@WebService
+public class BillingImpl {
+    public String invoice(String order) { return ""; }
+    public String status() { return "ok"; }
+    String internal() { return ""; }
+    public static BillingImpl create() { return new BillingImpl(); }
+}`,
+			want: []string{"invoice=BillingImpl.invoice", "status=BillingImpl.status"},
+		},
+		{
+			name: "annotated and plain public methods",
+			source: `package shop;
+
+@WebService(serviceName = "Billing")
+public class BillingImpl {
+    @WebMethod(operationName = "CreateInvoice")
+    public String invoice(String order) { return ""; }
+
+    public String status() { return "ok"; }
+
+    @WebMethod(exclude = true)
+    public void reset() { }
+}`,
+			want: []string{"CreateInvoice=BillingImpl.invoice", "status=BillingImpl.status"},
+		},
+		{
+			name: "exclude false",
+			source: `package shop;
+
+@WebService
+public class BillingImpl {
+    @WebMethod(exclude = false)
+    public void reset() { }
+}`,
+			want: []string{"reset=BillingImpl.reset"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tree := NewTree(fstest.MapFS{"src/shop/BillingImpl.java": {Data: []byte(tt.source)}}, ".", TreeOptions{})
+			eps, err := ScanSOAPEndpoints(tree, ".")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(eps) != 1 {
+				t.Fatalf("%d endpoints, want 1", len(eps))
+			}
+			var got []string
+			for _, op := range eps[0].Operations {
+				got = append(got, op.Name+"="+op.Handler)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("operations %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,187 @@
+package scan
+
+import (
+	"fmt"
+	"jz/model"
+	"os"
+	"regexp"
+	"strings"
+)
+
+var (
+	activationPropRegex = regexp.MustCompile(`propertyName\s*=\s*"([^"]*)"\s*,\s*propertyValue\s*=\s*"([^"]*)"`)
+	resourceLookupRegex = regexp.MustCompile(`@Resource\s*\(.*\b(?:lookup|mappedName|name)\s*=\s*"([^"]*)"`)
+	destinationDeclRe   = regexp.MustCompile(`\b(Queue|Topic|Destination)\s+(\w+)\s*[;=]`)
+	createDestRegex     = regexp.MustCompile(`(\w+)\s*=\s*[\w.]+\.create(Queue|Topic)\(\s*"([^"]*)"\s*\)`)
+	lookupDestRegex     = regexp.MustCompile(`(\w+)\s*=\s*(?:\(\s*(Queue|Topic|Destination)\s*\))?\s*[\w.]+\.lookup\(\s*"([^"]*)"\s*\)`)
+	producerSendRegex   = regexp.MustCompile(`createProducer\(\s*\)\s*(?:\.\w+\([^()]*\)\s*)*\.send\(\s*([^,]+?)\s*,`)
+	classicProducerRe   = regexp.MustCompile(`createProducer\(\s*(\w+)\s*\)`)
+	inlineCreateDestRe  = regexp.MustCompile(`^[\w.]+\.create(Queue|Topic)\(\s*"([^"]*)"\s*\)$`)
+)
+
+// jmsDestinationRef is a destination variable whose identity is known from a literal.
+type jmsDestinationRef struct {
+	reference     string
+	referenceType string // name, jndi
+	kind          string // queue, topic
+}
+
+// ScanJMS recursively walks the rootDir and extracts JMS message producers and consumers.
+//
+// Consumers are @MessageDriven beans; the destination comes from the
+// destinationLookup/destination activation properties or mappedName. If none is
+// present, the bean is reported with an activation-spec reference to be resolved
+// against server.xml.
+//
+// Producers are send sites (JMSContext.createProducer().send(dest, ...) or
+// Session.createProducer(dest)) whose destination is an inline createQueue("...")
+// or a variable bound in the same file to a literal via @Resource(lookup = "..."),
+// createQueue/createTopic("...") or lookup("..."). Other send sites are skipped.
+func ScanJMS(tree *model.SourceTree, rootDir string) ([]model.MessageEndpoint, error) {
+	var endpoints []model.MessageEndpoint
+
+	err := tree.Walk(rootDir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() || !strings.HasSuffix(path, ".java") {
+			return nil
+		}
+		eps, err := scanJMSFile(tree, path)
+		// We ignore file reading errors to prevent stopping the entire walk
+		if err == nil {
+			endpoints = append(endpoints, eps...)
+		}
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+	return endpoints, nil
+}
+
+func scanJMSFile(tree *model.SourceTree, filePath string) ([]model.MessageEndpoint, error) {
+	lines, err := readLines(tree, filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	var endpoints []model.MessageEndpoint
+	var className string
+	var mdbAnnotation string
+	var mdbLine int
+	var pendingResource string
+
+	refs := make(map[string]jmsDestinationRef)
+
+	for i := 0; i < len(lines); i++ {
+		line := strings.TrimSpace(lines[i])
+		if line == "" {
+			continue
+		}
+
+		if className == "" {
+			if strings.HasPrefix(line, "@MessageDriven") {
+				mdbLine = i + 1
+				mdbAnnotation, i = collectAnnotation(lines, i)
+				continue
+			}
+			className = declaredClassName(line)
+			continue
+		}
+
+		// Destination bindings
+		if m := resourceLookupRegex.FindStringSubmatch(line); m != nil {
+			pendingResource = m[1]
+		}
+		if m := destinationDeclRe.FindStringSubmatch(line); m != nil && pendingResource != "" {
+			refs[m[2]] = jmsDestinationRef{reference: pendingResource, referenceType: "jndi", kind: destinationKind(m[1])}
+			pendingResource = ""
+		} else if !strings.HasPrefix(line, "@") {
+			pendingResource = ""
+		}
+		if m := createDestRegex.FindStringSubmatch(line); m != nil {
+			refs[m[1]] = jmsDestinationRef{reference: m[3], referenceType: "name", kind: strings.ToLower(m[2])}
+		}
+		if m := lookupDestRegex.FindStringSubmatch(line); m != nil {
+			refs[m[1]] = jmsDestinationRef{reference: m[3], referenceType: "jndi", kind: destinationKind(m[2])}
+		}
+
+		// Send sites
+		var destExpr string
+		if m := producerSendRegex.FindStringSubmatch(line); m != nil {
+			destExpr = m[1]
+		} else if m := classicProducerRe.FindStringSubmatch(line); m != nil {
+			destExpr = m[1]
+		}
+		if destExpr == "" {
+			continue
+		}
+
+		var ref jmsDestinationRef
+		if m := inlineCreateDestRe.FindStringSubmatch(destExpr); m != nil {
+			ref = jmsDestinationRef{reference: m[2], referenceType: "name", kind: strings.ToLower(m[1])}
+		} else if r, ok := refs[destExpr]; ok {
+			ref = r
+		} else {
+			continue
+		}
+
+		endpoints = append(endpoints, model.MessageEndpoint{
+			Class:           className,
+			Role:            model.MessageProducer,
+			Protocol:        model.ProtocolJMS,
+			DestinationKind: ref.kind,
+			Reference:       ref.reference,
+			ReferenceType:   ref.referenceType,
+			SourceFile:      filePath,
+			Evidence:        fmt.Sprintf("%s:%d", filePath, i+1),
+			Confidence:      model.ConfidenceHigh,
+		})
+	}
+
+	if mdbAnnotation != "" && className != "" {
+		props := make(map[string]string)
+		for _, m := range activationPropRegex.FindAllStringSubmatch(mdbAnnotation, -1) {
+			props[m[1]] = m[2]
+		}
+		attrs := annotationAttrs(mdbAnnotation)
+
+		consumer := model.MessageEndpoint{
+			Class:           className,
+			Role:            model.MessageConsumer,
+			Protocol:        model.ProtocolJMS,
+			DestinationKind: destinationKind(props["destinationType"]),
+			SourceFile:      filePath,
+			Evidence:        fmt.Sprintf("%s:%d", filePath, mdbLine),
+			Confidence:      model.ConfidenceHigh,
+		}
+		switch {
+		case props["destinationLookup"] != "":
+			consumer.Reference, consumer.ReferenceType = props["destinationLookup"], "jndi"
+		case props["destination"] != "":
+			consumer.Reference, consumer.ReferenceType = props["destination"], "name"
+			if strings.Contains(props["destination"], "/") {
+				consumer.ReferenceType = "jndi"
+			}
+		case attrs["mappedName"] != "":
+			consumer.Reference, consumer.ReferenceType = attrs["mappedName"], "jndi"
+		default:
+			consumer.Reference, consumer.ReferenceType = className, "activation-spec"
+			consumer.Confidence = model.ConfidenceMedium
+		}
+		endpoints = append(endpoints, consumer)
+	}
+
+	return endpoints, nil
+}
+
+// destinationKind maps a JMS type name (Queue, javax.jms.Topic, ...) to queue or topic.
+// Unknown or generic destinations default to queue.
+func destinationKind(typeName string) string {
+	if strings.HasSuffix(typeName, "Topic") {
+		return "topic"
+	}
+	return "queue"
+}
@@ -4,12 +4,12 @@ import (
 	"encoding/xml"
 	"io"
 	"jz/model"
-	"os"
+	"strings"
 )
 
 // ScanLiberty parses a WebSphere Liberty server.xml file and extracts configuration.
-func ScanLiberty(path string) (model.LibertyServer, error) {
-	f, err := os.Open(path)
+func ScanLiberty(tree *model.SourceTree, path string) (model.LibertyServer, error) {
+	f, err := tree.Open(path)
 	if err != nil {
 		return model.LibertyServer{}, err
 	}
@@ -52,6 +52,19 @@ func ScanLiberty(path string) (model.LibertyServer, error) {
 		})
 	}
 
+	// Process EnterpriseApplications
+	for _, earApp := range xs.EnterpriseApplications {
+		id := earApp.ID
+		if id == "" {
+			id = earApp.Name
+		}
+		server.DeployedApps = append(server.DeployedApps, model.LibertyApp{
+			ID:       id,
+			Location: earApp.Location,
+			Type:     "enterpriseApplication",
+		})
+	}
+
 	// Process WebApplications
 	for _, webApp := range xs.WebApplications {
 		server.DeployedApps = append(server.DeployedApps, model.LibertyApp{
@@ -62,9 +75,57 @@ func ScanLiberty(path string) (model.LibertyServer, error) {
 		})
 	}
 
+	// Process JMS destinations
+	for _, q := range xs.JMSQueues {
+		server.JMSDestinations = append(server.JMSDestinations, model.JMSDestination{
+			ID:           q.ID,
+			JNDIName:     q.JNDIName,
+			Kind:         "queue",
+			PhysicalName: firstNonEmpty(q.WasJms.QueueName, q.WmqJms.BaseQueueName),
+		})
+	}
+	for _, t := range xs.JMSTopics {
+		server.JMSDestinations = append(server.JMSDestinations, model.JMSDestination{
+			ID:           t.ID,
+			JNDIName:     t.JNDIName,
+			Kind:         "topic",
+			PhysicalName: firstNonEmpty(t.WasJms.TopicName, t.WmqJms.BaseTopicName),
+		})
+	}
+	for _, as := range xs.ActivationSpecs {
+		server.ActivationSpecs = append(server.ActivationSpecs, model.JMSActivationSpec{
+			ID:                as.ID,
+			DestinationRef:    firstNonEmpty(as.WasJms.DestinationRef, as.WmqJms.DestinationRef),
+			DestinationLookup: firstNonEmpty(as.WasJms.DestinationLookup, as.WmqJms.DestinationLookup),
+		})
+	}
+
+	// Process JDBC data sources (vendor properties: properties, properties.postgresql, ...)
+	for _, ds := range xs.DataSources {
+		d := model.DataSource{ID: firstNonEmpty(ds.ID, ds.JNDIName), JNDIName: ds.JNDIName}
+		for _, p := range ds.Properties {
+			if p.XMLName.Local != "properties" && !strings.HasPrefix(p.XMLName.Local, "properties.") {
+				continue
+			}
+			d.DatabaseName = firstNonEmpty(d.DatabaseName, p.DatabaseName)
+			d.ServerName = firstNonEmpty(d.ServerName, p.ServerName)
+			d.URL = firstNonEmpty(d.URL, p.URL, p.URLLower)
+		}
+		server.DataSources = append(server.DataSources, d)
+	}
+
 	return server, nil
 }
 
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
 // XML mapping structs
 
 type xmlServer struct {
@@ -72,6 +133,14 @@ type xmlServer struct {
 	FeatureManagers []xmlFeatureManager `xml:"featureManager"`
 	Applications    []xmlApplication    `xml:"application"`
 	WebApplications []xmlWebApplication `xml:"webApplication"`
+
+	EnterpriseApplications []xmlEnterpriseApplication `xml:"enterpriseApplication"`
+
+	JMSQueues       []xmlJMSDestination `xml:"jmsQueue"`
+	JMSTopics       []xmlJMSDestination `xml:"jmsTopic"`
+	ActivationSpecs []xmlJMSDestination `xml:"jmsActivationSpec"`
+
+	DataSources []xmlDataSource `xml:"dataSource"`
 }
 
 type xmlFeatureManager struct {
@@ -88,3 +157,39 @@ type xmlWebApplication struct {
 	Location    string `xml:"location,attr"`
 	ContextRoot string `xml:"contextRoot,attr"`
 }
+
+type xmlEnterpriseApplication struct {
+	ID       string `xml:"id,attr"`
+	Name     string `xml:"name,attr"`
+	Location string `xml:"location,attr"`
+}
+
+type xmlJMSDestination struct {
+	ID       string      `xml:"id,attr"`
+	JNDIName string      `xml:"jndiName,attr"`
+	WasJms   xmlJMSProps `xml:"properties.wasJms"`
+	WmqJms   xmlJMSProps `xml:"properties.wmqJms"`
+}
+
+type xmlJMSProps struct {
+	QueueName         string `xml:"queueName,attr"`
+	TopicName         string `xml:"topicName,attr"`
+	BaseQueueName     string `xml:"baseQueueName,attr"`
+	BaseTopicName     string `xml:"baseTopicName,attr"`
+	DestinationRef    string `xml:"destinationRef,attr"`
+	DestinationLookup string `xml:"destinationLookup,attr"`
+}
+
+type xmlDataSource struct {
+	ID         string            `xml:"id,attr"`
+	JNDIName   string            `xml:"jndiName,attr"`
+	Properties []xmlDSProperties `xml:",any"`
+}
+
+type xmlDSProperties struct {
+	XMLName      xml.Name
+	DatabaseName string `xml:"databaseName,attr"`
+	ServerName   string `xml:"serverName,attr"`
+	URL          string `xml:"URL,attr"`
+	URLLower     string `xml:"url,attr"`
+}
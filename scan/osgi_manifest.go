@@ -2,6 +2,7 @@ package scan
 
 import (
 	"bufio"
+	"jz/model"
 	"os"
 	"path/filepath"
 	"strings"
@@ -16,10 +17,10 @@ type OSGIBundle struct {
 }
 
 // ScanOSGi recursively walks the rootDir and extracts OSGi bundle metadata.
-func ScanOSGi(rootDir string) ([]OSGIBundle, error) {
+func ScanOSGi(tree *model.SourceTree, rootDir string) ([]OSGIBundle, error) {
 	var bundles []OSGIBundle
 
-	err := filepath.Walk(rootDir, func(path string, info os.FileInfo, err error) error {
+	err := tree.Walk(rootDir, func(path string, info os.FileInfo, err error) error {
 		if err != nil {
 			return err
 		}
@@ -27,7 +28,7 @@ func ScanOSGi(rootDir string) ([]OSGIBundle, error) {
 			return nil
 		}
 		if strings.ToUpper(filepath.Base(path)) == "MANIFEST.MF" {
-			bundle, err := parseManifest(path)
+			bundle, err := parseManifest(tree, path)
 			if err != nil {
 				// Ignore parsing errors, just like jaxrs scanner
 				return nil
@@ -46,8 +47,8 @@ func ScanOSGi(rootDir string) ([]OSGIBundle, error) {
 	return bundles, nil
 }
 
-func parseManifest(path string) (OSGIBundle, error) {
-	f, err := os.Open(path)
+func parseManifest(tree *model.SourceTree, path string) (OSGIBundle, error) {
+	f, err := tree.Open(path)
 	if err != nil {
 		return OSGIBundle{}, err
 	}
@@ -0,0 +1,361 @@
+package scan
+
+import (
+	"fmt"
+	"jz/model"
+	"os"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+var (
+	entityManagerDeclRegex = regexp.MustCompile(`\bEntityManager\s+(\w+)\s*[;=,)]`)
+	entityManagerOpRegex   = regexp.MustCompile(`\b(\w+)\s*\.\s*(find|getReference|persist|merge|remove|refresh|detach|createQuery|createNamedQuery|createNativeQuery)\s*\(`)
+	jdbcCallRegex          = regexp.MustCompile(`\.\s*(prepareStatement|prepareCall|executeQuery|executeUpdate|addBatch)\s*\(\s*"`)
+	classLiteralRegex      = regexp.MustCompile(`\b([A-Z]\w*)\.class\b`)
+	newObjectRegex         = regexp.MustCompile(`^new\s+([A-Z]\w*)`)
+	identifierRegex        = regexp.MustCompile(`^[a-z_]\w*$`)
+	sqlTargetRegex         = regexp.MustCompile(`(?i)\b(?:FROM|JOIN(?:\s+FETCH)?|UPDATE|INTO)\s+([A-Za-z_][\w.$]*)`)
+	namedQueryRegex        = regexp.MustCompile(`@(NamedQuery|NamedNativeQuery)\s*\(`)
+	queryNameAttrRegex     = regexp.MustCompile(`\bname\s*=\s*"([^"]*)"`)
+	queryAttrRegex         = regexp.MustCompile(`\bquery\s*=\s*((?:"(?:[^"\\]|\\.)*"\s*\+?\s*)+)`)
+)
+
+// sqlKeywords are words that follow FROM/JOIN/INTO in SQL without naming a table.
+var sqlKeywords = map[string]bool{"SELECT": true, "LATERAL": true, "DUAL": true}
+
+// ScanDataAccess recursively walks the rootDir and extracts database accesses: operations on
+// EntityManager variables and JDBC statements with literal SQL.
+//
+// Limitations (AST-lite):
+//   - EntityManager receivers must be declared in the same file (field, parameter or local).
+//   - Only string literals on the line of the call are read as JPQL/SQL.
+func ScanDataAccess(tree *model.SourceTree, rootDir string) ([]model.DataAccess, error) {
+	var accesses []model.DataAccess
+
+	err := tree.Walk(rootDir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() || !strings.HasSuffix(path, ".java") {
+			return nil
+		}
+		found, err := scanDataAccessFile(tree, path)
+		// We ignore file reading errors to prevent stopping the entire walk
+		if err == nil {
+			accesses = append(accesses, found...)
+		}
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+	return accesses, nil
+}
+
+func scanDataAccessFile(tree *model.SourceTree, filePath string) ([]model.DataAccess, error) {
+	lines, err := readLines(tree, filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	var accesses []model.DataAccess
+	entityManagers := EntityManagerNames(lines)
+	className := ""
+	for i, raw := range lines {
+		line := strings.TrimSpace(raw)
+		if strings.HasPrefix(line, "//") || strings.HasPrefix(line, "*") || strings.HasPrefix(line, "/*") {
+			continue
+		}
+		if className == "" {
+			if t := typeDeclRegex.FindStringSubmatch(line); t != nil {
+				className = t[2]
+			}
+		}
+		for _, a := range ParseDataAccess(line, lines, entityManagers) {
+			a.Class = className
+			a.SourceFile = filePath
+			a.Evidence = fmt.Sprintf("%s:%d", filePath, i+1)
+			accesses = append(accesses, a)
+		}
+	}
+	return accesses, nil
+}
+
+// EntityManagerNames returns the names of the EntityManager fields, parameters and locals
+// declared in a source file.
+func EntityManagerNames(lines []string) []string {
+	var names []string
+	for _, line := range lines {
+		for _, m := range entityManagerDeclRegex.FindAllStringSubmatch(line, -1) {
+			if !slices.Contains(names, m[1]) {
+				names = append(names, m[1])
+			}
+		}
+	}
+	return names
+}
+
+// ParseDataAccess returns the database accesses on a line: operations on one of the given
+// EntityManager variables and JDBC calls taking literal SQL. lines is the enclosing file,
+// used to find the declared type of a variable passed to persist, merge or remove.
+// Kind, Operation, Query, Entities and Tables are set.
+func ParseDataAccess(line string, lines []string, entityManagers []string) []model.DataAccess {
+	var accesses []model.DataAccess
+	type hit struct {
+		pos    int
+		access model.DataAccess
+	}
+	var hits []hit
+
+	for _, m := range entityManagerOpRegex.FindAllStringSubmatchIndex(line, -1) {
+		if !slices.Contains(entityManagers, line[m[2]:m[3]]) {
+			continue
+		}
+		op := line[m[4]:m[5]]
+		args := callArguments(line, m[1])
+		first := strings.TrimSpace(firstArgument(args))
+		a := model.DataAccess{Kind: model.DataAccessJPA, Operation: op}
+
+		switch op {
+		case "find", "getReference":
+			if c := classLiteralRegex.FindStringSubmatch(first); c != nil {
+				a.Entities = []string{c[1]}
+			}
+		case "persist", "merge", "remove", "refresh", "detach":
+			if n := newObjectRegex.FindStringSubmatch(first); n != nil {
+				a.Entities = []string{n[1]}
+			} else if identifierRegex.MatchString(first) {
+				if t := variableType(lines, first); t != "" {
+					a.Entities = []string{t}
+				}
+			}
+		case "createQuery":
+			a.Query = stringLiterals(first)
+			a.Entities = SQLTargets(a.Query, true)
+		case "createNamedQuery":
+			a.Query = stringLiterals(first)
+		case "createNativeQuery":
+			a.Query = stringLiterals(first)
+			a.Tables = SQLTargets(a.Query, false)
+		}
+		// A result class (createQuery(jpql, Order.class)) names the entity when the query does not
+		if len(a.Entities) == 0 && op != "createNativeQuery" {
+			if c := classLiteralRegex.FindStringSubmatch(args); c != nil {
+				a.Entities = []string{c[1]}
+			}
+		}
+		hits = append(hits, hit{m[0], a})
+	}
+
+	for _, m := range jdbcCallRegex.FindAllStringSubmatchIndex(line, -1) {
+		open := strings.Index(line[m[0]:], "(") + m[0] + 1
+		query := stringLiterals(firstArgument(callArguments(line, open)))
+		hits = append(hits, hit{m[0], model.DataAccess{
+			Kind:      model.DataAccessJDBC,
+			Operation: line[m[2]:m[3]],
+			Query:     query,
+			Tables:    SQLTargets(query, false),
+		}})
+	}
+
+	for i := 1; i < len(hits); i++ {
+		for j := i; j > 0 && hits[j].pos < hits[j-1].pos; j-- {
+			hits[j], hits[j-1] = hits[j-1], hits[j]
+		}
+	}
+	for _, h := range hits {
+		accesses = append(accesses, h.access)
+	}
+	return accesses
+}
+
+// SQLTargets returns the tables (SQL) or entities (JPQL) a query names after FROM, JOIN,
+// UPDATE and INTO, in order of appearance. JPQL path joins (JOIN o.items) are skipped.
+func SQLTargets(query string, jpql bool) []string {
+	var targets []string
+	for _, m := range sqlTargetRegex.FindAllStringSubmatch(query, -1) {
+		name := m[1]
+		if sqlKeywords[strings.ToUpper(name)] || (jpql && strings.Contains(name, ".")) {
+			continue
+		}
+		if !slices.Contains(targets, name) {
+			targets = append(targets, name)
+		}
+	}
+	return targets
+}
+
+// ScanNamedQueries recursively walks the rootDir and extracts @NamedQuery and
+// @NamedNativeQuery declarations with literal names and queries.
+func ScanNamedQueries(tree *model.SourceTree, rootDir string) ([]model.NamedQuery, error) {
+	var queries []model.NamedQuery
+
+	err := tree.Walk(rootDir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() || !strings.HasSuffix(path, ".java") {
+			return nil
+		}
+		found, err := scanNamedQueryFile(tree, path)
+		// We ignore file reading errors to prevent stopping the entire walk
+		if err == nil {
+			queries = append(queries, found...)
+		}
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+	return queries, nil
+}
+
+func scanNamedQueryFile(tree *model.SourceTree, filePath string) ([]model.NamedQuery, error) {
+	lines, err := readLines(tree, filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	var queries []model.NamedQuery
+	className := ""
+	for i := 0; i < len(lines); i++ {
+		line := strings.TrimSpace(lines[i])
+		if strings.HasPrefix(line, "//") || strings.HasPrefix(line, "*") || strings.HasPrefix(line, "/*") {
+			continue
+		}
+		if t := typeDeclRegex.FindStringSubmatch(line); t != nil && !strings.HasPrefix(line, "@") {
+			className = t[2]
+			for q := range queries {
+				if queries[q].Entity == "" {
+					queries[q].Entity = className
+				}
+			}
+			continue
+		}
+		m := namedQueryRegex.FindStringSubmatchIndex(line)
+		if m == nil {
+			continue
+		}
+		// Several annotations may share the lines of a @NamedQueries list
+		text, end := collectAnnotation(append([]string{line[m[0]:]}, lines[i+1:]...), 0)
+		for _, ann := range namedQueryRegex.FindAllStringSubmatchIndex(text, -1) {
+			body := callArguments(text, ann[1])
+			name := queryNameAttrRegex.FindStringSubmatch(body)
+			query := queryAttrRegex.FindStringSubmatch(body)
+			if name == nil {
+				continue
+			}
+			nq := model.NamedQuery{
+				Name:       name[1],
+				Native:     text[ann[2]:ann[3]] == "NamedNativeQuery",
+				SourceFile: filePath,
+				Evidence:   fmt.Sprintf("%s:%d", filePath, i+1),
+			}
+			if query != nil {
+				nq.Query = stringLiterals(query[1])
+			}
+			if nq.Native {
+				nq.Tables = SQLTargets(nq.Query, false)
+			} else {
+				nq.Entities = SQLTargets(nq.Query, true)
+			}
+			queries = append(queries, nq)
+		}
+		i += end
+	}
+	for q := range queries {
+		if queries[q].Entity == "" {
+			queries[q].Entity = className
+		}
+	}
+	return queries, nil
+}
+
+// callArguments returns the text between the parenthesis opened just before open and its
+// matching closing parenthesis (or the end of the text).
+func callArguments(text string, open int) string {
+	depth := 0
+	var quote byte
+	for i := open; i < len(text); i++ {
+		c := text[i]
+		if quote != 0 {
+			if c == '\\' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+			continue
+		}
+		switch c {
+		case '"', '\'':
+			quote = c
+		case '(':
+			depth++
+		case ')':
+			if depth == 0 {
+				return text[open:i]
+			}
+			depth--
+		}
+	}
+	return text[open:]
+}
+
+// firstArgument returns the first top-level argument of an argument list.
+func firstArgument(args string) string {
+	depth := 0
+	var quote byte
+	for i := 0; i < len(args); i++ {
+		c := args[i]
+		if quote != 0 {
+			if c == '\\' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+			continue
+		}
+		switch c {
+		case '"', '\'':
+			quote = c
+		case '(', '[', '{':
+			depth++
+		case ')', ']', '}':
+			depth--
+		case ',':
+			if depth == 0 {
+				return args[:i]
+			}
+		}
+	}
+	return args
+}
+
+// stringLiterals concatenates the string literals of an expression ("SELECT o " + "FROM Order o").
+func stringLiterals(expr string) string {
+	var sb strings.Builder
+	for _, lit := range stringLiteralRegex.FindAllString(expr, -1) {
+		sb.WriteString(strings.ReplaceAll(lit[1:len(lit)-1], `\"`, `"`))
+	}
+	return strings.Join(strings.Fields(sb.String()), " ")
+}
+
+// variableType returns the declared type of a variable or parameter in a file, when all
+// its declarations agree.
+func variableType(lines []string, name string) string {
+	decl := regexp.MustCompile(`\b([A-Z]\w*)(?:<[^>]*>)?\s+` + regexp.QuoteMeta(name) + `\s*[=;,)]`)
+	found := ""
+	for _, line := range lines {
+		for _, m := range decl.FindAllStringSubmatch(line, -1) {
+			if found != "" && found != m[1] {
+				return ""
+			}
+			found = m[1]
+		}
+	}
+	return found
+}
@@ -0,0 +1,232 @@
+package scan
+
+import (
+	"bufio"
+	"fmt"
+	"jz/model"
+	"os"
+	"regexp"
+	"strings"
+)
+
+var (
+	incomingRegex       = regexp.MustCompile(`@Incoming\(\s*(?:value\s*=\s*)?"([^"]*)"`)
+	outgoingRegex       = regexp.MustCompile(`@Outgoing\(\s*(?:value\s*=\s*)?"([^"]*)"`)
+	channelRegex        = regexp.MustCompile(`@Channel\(\s*(?:value\s*=\s*)?"([^"]*)"`)
+	producerRecordRegex = regexp.MustCompile(`new\s+ProducerRecord\s*(?:<[^>]*>)?\(\s*"([^"]*)"`)
+	subscribeRegex      = regexp.MustCompile(`\.subscribe\(\s*(?:Arrays\.asList|Collections\.singletonList|List\.of|Set\.of)\(([^)]*)\)`)
+)
+
+// ScanReactiveMessaging recursively walks the rootDir and extracts MicroProfile
+// Reactive Messaging channels and plain Kafka client usage.
+//
+//   - @Incoming("ch") methods and @Channel("ch") Multi/Publisher injections consume ch.
+//   - @Outgoing("ch") methods and @Channel("ch") Emitter injections produce to ch.
+//   - new ProducerRecord<>("topic", ...) produces and consumer.subscribe(List.of("topic"))
+//     consumes a literal Kafka topic.
+//
+// Channel names are mapped to connectors and topics by the caller using mp.messaging.* config.
+func ScanReactiveMessaging(tree *model.SourceTree, rootDir string) ([]model.MessageEndpoint, error) {
+	var endpoints []model.MessageEndpoint
+
+	err := tree.Walk(rootDir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() || !strings.HasSuffix(path, ".java") {
+			return nil
+		}
+		eps, err := scanReactiveFile(tree, path)
+		// We ignore file reading errors to prevent stopping the entire walk
+		if err == nil {
+			endpoints = append(endpoints, eps...)
+		}
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+	return endpoints, nil
+}
+
+func scanReactiveFile(tree *model.SourceTree, filePath string) ([]model.MessageEndpoint, error) {
+	lines, err := readLines(tree, filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	var endpoints []model.MessageEndpoint
+	var className string
+
+	channel := func(role, name string, line int) model.MessageEndpoint {
+		return model.MessageEndpoint{
+			Class:           className,
+			Role:            role,
+			Protocol:        model.ProtocolReactive,
+			DestinationKind: "channel",
+			Reference:       name,
+			ReferenceType:   "channel",
+			SourceFile:      filePath,
+			Evidence:        fmt.Sprintf("%s:%d", filePath, line),
+			Confidence:      model.ConfidenceHigh,
+		}
+	}
+	topic := func(role, name string, line int) model.MessageEndpoint {
+		return model.MessageEndpoint{
+			Class:           className,
+			Role:            role,
+			Protocol:        model.ProtocolKafka,
+			DestinationKind: "topic",
+			Reference:       name,
+			ReferenceType:   "name",
+			SourceFile:      filePath,
+			Evidence:        fmt.Sprintf("%s:%d", filePath, line),
+			Confidence:      model.ConfidenceHigh,
+		}
+	}
+
+	for i := 0; i < len(lines); i++ {
+		line := strings.TrimSpace(lines[i])
+		if line == "" || strings.HasPrefix(line, "//") || strings.HasPrefix(line, "*") {
+			continue
+		}
+		if className == "" {
+			className = declaredClassName(line)
+			continue
+		}
+
+		if m := incomingRegex.FindStringSubmatch(line); m != nil {
+			endpoints = append(endpoints, channel(model.MessageConsumer, m[1], i+1))
+		}
+		if m := outgoingRegex.FindStringSubmatch(line); m != nil {
+			endpoints = append(endpoints, channel(model.MessageProducer, m[1], i+1))
+		}
+		if m := channelRegex.FindStringSubmatch(line); m != nil {
+			// The injected type may follow on the same line or after further annotations.
+			decl := line
+			for k := i + 1; k < len(lines) && !strings.Contains(decl, ";"); k++ {
+				decl += " " + strings.TrimSpace(lines[k])
+			}
+			switch {
+			case strings.Contains(decl, "Emitter"):
+				endpoints = append(endpoints, channel(model.MessageProducer, m[1], i+1))
+			case strings.Contains(decl, "Multi<") || strings.Contains(decl, "Publisher<"):
+				endpoints = append(endpoints, channel(model.MessageConsumer, m[1], i+1))
+			}
+		}
+
+		if m := producerRecordRegex.FindStringSubmatch(line); m != nil {
+			endpoints = append(endpoints, topic(model.MessageProducer, m[1], i+1))
+		}
+		if m := subscribeRegex.FindStringSubmatch(line); m != nil {
+			for _, q := range quotedRegex.FindAllStringSubmatch(m[1], -1) {
+				endpoints = append(endpoints, topic(model.MessageConsumer, q[1], i+1))
+			}
+		}
+	}
+
+	return endpoints, nil
+}
+
+// ScanMessagingConfig parses mp.messaging.incoming|outgoing.<channel>.<attribute>
+// entries from a MicroProfile Config properties file. Only the connector and the
+// topic/address/destination attributes are kept. Profile-prefixed keys (%dev.)
+// are ignored.
+func ScanMessagingConfig(tree *model.SourceTree, path string) ([]model.ChannelConfig, error) {
+	f, err := tree.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	type channelKey struct {
+		direction, channel string
+	}
+	configs := make(map[channelKey]*model.ChannelConfig)
+	var order []channelKey
+
+	scanner := bufio.NewScanner(f)
+	lineNum := 0
+	for scanner.Scan() {
+		lineNum++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, "!") {
+			continue
+		}
+
+		key, value, ok := splitProperty(line)
+		if !ok || !strings.HasPrefix(key, "mp.messaging.") {
+			continue
+		}
+		direction, channel, attr, ok := splitChannelKey(strings.TrimPrefix(key, "mp.messaging."))
+		if !ok {
+			continue
+		}
+
+		k := channelKey{direction, channel}
+		cfg, exists := configs[k]
+		if !exists {
+			cfg = &model.ChannelConfig{Direction: direction, Channel: channel, SourceFile: path}
+			configs[k] = cfg
+			order = append(order, k)
+		}
+		switch attr {
+		case "connector":
+			cfg.Connector = value
+			cfg.Evidence = fmt.Sprintf("%s:%d", path, lineNum)
+		case "topic", "address", "destination":
+			cfg.Destination = value
+		}
+	}
+
+	var result []model.ChannelConfig
+	for _, k := range order {
+		result = append(result, *configs[k])
+	}
+	return result, scanner.Err()
+}
+
+// splitProperty splits a properties line on the first '=', ':' or whitespace separator.
+func splitProperty(line string) (string, string, bool) {
+	idx := strings.IndexAny(line, "=: \t")
+	if idx <= 0 {
+		return "", "", false
+	}
+	key := strings.TrimSpace(line[:idx])
+	value := strings.TrimSpace(line[idx+1:])
+	value = strings.TrimSpace(strings.TrimLeft(value, "=:"))
+	return key, value, true
+}
+
+// splitChannelKey splits "incoming.<channel>.<attribute>" where the channel may be
+// quoted to contain dots ("incoming.\"my.channel\".topic").
+func splitChannelKey(key string) (direction, channel, attr string, ok bool) {
+	dot := strings.Index(key, ".")
+	if dot < 0 {
+		return "", "", "", false
+	}
+	direction, rest := key[:dot], key[dot+1:]
+	if direction != "incoming" && direction != "outgoing" {
+		return "", "", "", false
+	}
+
+	if strings.HasPrefix(rest, `"`) {
+		end := strings.Index(rest[1:], `"`)
+		if end < 0 {
+			return "", "", "", false
+		}
+		channel = rest[1 : end+1]
+		rest = strings.TrimPrefix(rest[end+2:], ".")
+	} else {
+		dot = strings.Index(rest, ".")
+		if dot < 0 {
+			return "", "", "", false
+		}
+		channel, rest = rest[:dot], rest[dot+1:]
+	}
+	if channel == "" || rest == "" {
+		return "", "", "", false
+	}
+	return direction, channel, rest, true
+}
@@ -0,0 +1,177 @@
+package scan
+
+import (
+	"bufio"
+	"jz/model"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// ServletClass describes a Java class that participates in the Servlet API,
+// either as an HttpServlet (entry point) or as an annotated filter.
+type ServletClass struct {
+	Name          string // Simple class name
+	QualifiedName string // Package-qualified class name
+	SourceFile    string
+	Annotated     bool     // @WebServlet or @WebFilter present
+	Filter        bool     // @WebFilter
+	ServletName   string   // name/filterName attribute
+	URLPatterns   []string // From the annotation only
+	ServletNames  []string // @WebFilter servletNames
+	Handlers      []string // Overridden do* / service methods
+	ExtendsHTTP   bool     // Directly extends HttpServlet
+}
+
+var (
+	servletHandlerRegex = regexp.MustCompile(`\bvoid\s+(doGet|doPost|doPut|doDelete|doHead|doOptions|doTrace|service)\s*\(`)
+	annotationNameRegex = regexp.MustCompile(`\b(?:name|filterName)\s*=\s*"([^"]*)"`)
+	initParamRegex      = regexp.MustCompile(`@WebInitParam\s*\([^)]*\)`)
+	servletNamesRegex   = regexp.MustCompile(`servletNames\s*=\s*(\{[^}]*\}|"[^"]*")`)
+	quotedRegex         = regexp.MustCompile(`"([^"]*)"`)
+)
+
+// ScanServlets recursively walks the rootDir and extracts Servlet API classes.
+// A class is reported when it is annotated with @WebServlet/@WebFilter, directly
+// extends HttpServlet, or overrides a do* handler taking an HttpServletRequest.
+// URL mappings declared in web.xml are resolved by the caller.
+func ScanServlets(tree *model.SourceTree, rootDir string) ([]ServletClass, error) {
+	var classes []ServletClass
+
+	err := tree.Walk(rootDir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() || !strings.HasSuffix(path, ".java") {
+			return nil
+		}
+
+		sc, ok, err := scanServletFile(tree, path)
+		// We ignore file reading errors to prevent stopping the entire walk
+		if err == nil && ok {
+			classes = append(classes, sc)
+		}
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return classes, nil
+}
+
+func scanServletFile(tree *model.SourceTree, filePath string) (ServletClass, bool, error) {
+	f, err := tree.Open(filePath)
+	if err != nil {
+		return ServletClass{}, false, err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	sc := ServletClass{SourceFile: filePath}
+
+	var pkg string
+	var annotation strings.Builder
+	var inAnnotation bool
+	var annotationDepth int
+	var usesServletRequest bool
+
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+
+		if strings.HasPrefix(line, "package ") {
+			pkg = strings.TrimSuffix(strings.TrimSpace(strings.TrimPrefix(line, "package ")), ";")
+			continue
+		}
+
+		// 1. Accumulate @WebServlet / @WebFilter (may span multiple lines)
+		if sc.Name == "" && (strings.HasPrefix(line, "@WebServlet") || strings.HasPrefix(line, "@WebFilter")) {
+			sc.Annotated = true
+			sc.Filter = strings.HasPrefix(line, "@WebFilter")
+			inAnnotation = true
+			annotationDepth = 0
+		}
+		if inAnnotation {
+			annotation.WriteString(line)
+			annotation.WriteString(" ")
+			annotationDepth += strings.Count(line, "(") - strings.Count(line, ")")
+			if annotationDepth <= 0 {
+				inAnnotation = false
+			}
+			continue
+		}
+
+		// 2. Class declaration (first top-level class only)
+		if sc.Name == "" && strings.Contains(line, "class ") {
+			parts := strings.Fields(line)
+			for i, p := range parts {
+				if p == "class" && i+1 < len(parts) {
+					sc.Name = strings.Split(parts[i+1], "{")[0]
+					sc.Name = strings.Split(sc.Name, "<")[0]
+				}
+				if p == "extends" && i+1 < len(parts) {
+					parent := strings.Split(parts[i+1], "{")[0]
+					sc.ExtendsHTTP = parent == "HttpServlet" || strings.HasSuffix(parent, ".HttpServlet")
+				}
+			}
+			continue
+		}
+
+		// 3. Handler overrides
+		if sc.Name != "" {
+			if m := servletHandlerRegex.FindStringSubmatch(line); m != nil && !strings.HasSuffix(line, ";") {
+				sc.Handlers = append(sc.Handlers, m[1])
+				if strings.Contains(line, "HttpServletRequest") {
+					usesServletRequest = true
+				}
+			}
+		}
+	}
+
+	if sc.Name == "" {
+		return ServletClass{}, false, nil
+	}
+	if !sc.Annotated && !sc.ExtendsHTTP && !usesServletRequest {
+		return ServletClass{}, false, nil
+	}
+
+	sc.QualifiedName = sc.Name
+	if pkg != "" {
+		sc.QualifiedName = pkg + "." + sc.Name
+	}
+
+	if sc.Annotated {
+		parseServletAnnotation(&sc, annotation.String())
+	}
+
+	return sc, true, nil
+}
+
+// parseServletAnnotation extracts the name, url patterns and servlet names
+// from a flattened @WebServlet/@WebFilter annotation. Only literal strings are used.
+func parseServletAnnotation(sc *ServletClass, text string) {
+	text = initParamRegex.ReplaceAllString(text, "")
+
+	if m := annotationNameRegex.FindStringSubmatch(text); m != nil {
+		sc.ServletName = m[1]
+		text = strings.Replace(text, m[0], "", 1)
+	}
+
+	if m := servletNamesRegex.FindStringSubmatch(text); m != nil {
+		for _, q := range quotedRegex.FindAllStringSubmatch(m[1], -1) {
+			sc.ServletNames = append(sc.ServletNames, q[1])
+		}
+		text = strings.Replace(text, m[0], "", 1)
+	}
+
+	for _, q := range quotedRegex.FindAllStringSubmatch(text, -1) {
+		p := strings.TrimSpace(q[1])
+		if strings.HasPrefix(p, "/") || strings.HasPrefix(p, "*.") {
+			sc.URLPatterns = append(sc.URLPatterns, p)
+		}
+	}
+}
@@ -0,0 +1,122 @@
+package scan
+
+import (
+	"regexp"
+	"sort"
+	"strconv"
+)
+
+var (
+	statusLiteralRegex  = regexp.MustCompile(`\bstatus\(\s*([1-5]\d\d)\s*[),]`)
+	statusConstRegex    = regexp.MustCompile(`\bStatus\.([A-Z_]+)\b`)
+	responseBuilderRe   = regexp.MustCompile(`\bResponse\s*\.\s*(ok|created|accepted|noContent|notModified|seeOther|temporaryRedirect|notAcceptable|serverError)\s*\(`)
+	integerLiteralRegex = regexp.MustCompile(`(?:^|[\s(,])([1-5]\d\d)\s*(?:[),]|$)`)
+	stringLiteralRegex  = regexp.MustCompile(`"(?:[^"\\]|\\.)*"`)
+)
+
+// StatusCodes maps javax/jakarta.ws.rs.core.Response.Status constants to their codes.
+var StatusCodes = map[string]int{
+	"OK":                              200,
+	"CREATED":                         201,
+	"ACCEPTED":                        202,
+	"NO_CONTENT":                      204,
+	"RESET_CONTENT":                   205,
+	"PARTIAL_CONTENT":                 206,
+	"MOVED_PERMANENTLY":               301,
+	"FOUND":                           302,
+	"SEE_OTHER":                       303,
+	"NOT_MODIFIED":                    304,
+	"USE_PROXY":                       305,
+	"TEMPORARY_REDIRECT":              307,
+	"PERMANENT_REDIRECT":              308,
+	"BAD_REQUEST":                     400,
+	"UNAUTHORIZED":                    401,
+	"PAYMENT_REQUIRED":                402,
+	"FORBIDDEN":                       403,
+	"NOT_FOUND":                       404,
+	"METHOD_NOT_ALLOWED":              405,
+	"NOT_ACCEPTABLE":                  406,
+	"PROXY_AUTHENTICATION_REQUIRED":   407,
+	"REQUEST_TIMEOUT":                 408,
+	"CONFLICT":                        409,
+	"GONE":                            410,
+	"LENGTH_REQUIRED":                 411,
+	"PRECONDITION_FAILED":             412,
+	"REQUEST_ENTITY_TOO_LARGE":        413,
+	"REQUEST_URI_TOO_LONG":            414,
+	"UNSUPPORTED_MEDIA_TYPE":          415,
+	"REQUESTED_RANGE_NOT_SATISFIABLE": 416,
+	"EXPECTATION_FAILED":              417,
+	"PRECONDITION_REQUIRED":           428,
+	"TOO_MANY_REQUESTS":               429,
+	"REQUEST_HEADER_FIELDS_TOO_LARGE": 431,
+	"INTERNAL_SERVER_ERROR":           500,
+	"NOT_IMPLEMENTED":                 501,
+	"BAD_GATEWAY":                     502,
+	"SERVICE_UNAVAILABLE":             503,
+	"GATEWAY_TIMEOUT":                 504,
+	"HTTP_VERSION_NOT_SUPPORTED":      505,
+	"NETWORK_AUTHENTICATION_REQUIRED": 511,
+}
+
+// responseBuilderStatuses maps the Response shortcut builders to the status they set.
+var responseBuilderStatuses = map[string]int{
+	"ok":                200,
+	"created":           201,
+	"accepted":          202,
+	"noContent":         204,
+	"seeOther":          303,
+	"notModified":       304,
+	"temporaryRedirect": 307,
+	"notAcceptable":     406,
+	"serverError":       500,
+}
+
+// ResponseStatuses returns the HTTP statuses a line sets on a JAX-RS Response:
+// Response.status(404), Response.status(Status.NOT_FOUND), Response.ok(), Response.noContent()
+// and similar builders. Statuses are returned in order of appearance, without duplicates.
+func ResponseStatuses(line string) []int {
+	line = stringLiteralRegex.ReplaceAllString(line, `""`)
+
+	type hit struct{ pos, status int }
+	var hits []hit
+	for _, m := range statusLiteralRegex.FindAllStringSubmatchIndex(line, -1) {
+		code, _ := strconv.Atoi(line[m[2]:m[3]])
+		hits = append(hits, hit{m[0], code})
+	}
+	for _, m := range statusConstRegex.FindAllStringSubmatchIndex(line, -1) {
+		if code, ok := StatusCodes[line[m[2]:m[3]]]; ok {
+			hits = append(hits, hit{m[0], code})
+		}
+	}
+	for _, m := range responseBuilderRe.FindAllStringSubmatchIndex(line, -1) {
+		hits = append(hits, hit{m[0], responseBuilderStatuses[line[m[2]:m[3]]]})
+	}
+
+	sort.SliceStable(hits, func(i, j int) bool { return hits[i].pos < hits[j].pos })
+
+	var statuses []int
+	seen := make(map[int]bool)
+	for _, h := range hits {
+		if !seen[h.status] {
+			seen[h.status] = true
+			statuses = append(statuses, h.status)
+		}
+	}
+	return statuses
+}
+
+// ArgumentStatus returns the status passed in constructor arguments such as
+// WebApplicationException(404), ("msg", Status.CONFLICT) or (Response.status(409).build()).
+// It returns 0 when no status is visible.
+func ArgumentStatus(args string) int {
+	args = stringLiteralRegex.ReplaceAllString(args, `""`)
+	if statuses := ResponseStatuses(args); len(statuses) > 0 {
+		return statuses[0]
+	}
+	if m := integerLiteralRegex.FindStringSubmatch(args); m != nil {
+		code, _ := strconv.Atoi(m[1])
+		return code
+	}
+	return 0
+}
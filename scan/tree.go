@@ -0,0 +1,218 @@
+package scan
+
+import (
+	"archive/zip"
+	"bytes"
+	"errors"
+	"io"
+	"io/fs"
+	"jz/model"
+	"os"
+	"path"
+	"strings"
+	"sync"
+	"time"
+)
+
+// archiveExtensions are the zip-based Java archives whose contents are scanned.
+var archiveExtensions = []string{".jar", ".war", ".ear"}
+
+// dependencyDirs are the directories holding dependency and build-output archives
+// (WEB-INF/lib, an EAR lib, Maven target, Gradle build). Their archives are third-party
+// libraries or copies of the sources, and are skipped unless TreeOptions asks for them.
+var dependencyDirs = []string{"lib", "target", "build"}
+
+// TreeOptions select the archives a source tree descends into.
+type TreeOptions struct {
+	DependencyArchives bool // Also descend into the archives of dependencyDirs
+}
+
+// OpenTree returns the source tree of a directory on disk.
+func OpenTree(dir string, opts TreeOptions) *model.SourceTree {
+	return NewTree(os.DirFS(dir), dir, opts)
+}
+
+// NewTree returns a source tree over fsys, reported as dir. JAR, WAR and EAR archives in
+// fsys, including archives nested in archives, appear as directories holding their
+// entries, so scanners find the descriptors and sources packaged in them. Archives are
+// opened when first descended into.
+func NewTree(fsys fs.FS, dir string, opts TreeOptions) *model.SourceTree {
+	return &model.SourceTree{FS: newArchiveFS(fsys, opts), Dir: dir}
+}
+
+// archiveFS is a file system that presents the zip archives of its base as directories.
+type archiveFS struct {
+	base fs.FS
+	opts TreeOptions
+
+	mu       sync.Mutex
+	archives map[string]*archiveFS // by name in base; nil when the file is not a readable zip
+}
+
+func newArchiveFS(base fs.FS, opts TreeOptions) *archiveFS {
+	return &archiveFS{base: base, opts: opts, archives: make(map[string]*archiveFS)}
+}
+
+func isArchiveName(name string) bool {
+	ext := strings.ToLower(path.Ext(name))
+	for _, e := range archiveExtensions {
+		if ext == e {
+			return true
+		}
+	}
+	return false
+}
+
+// scanned reports whether the archive at name in base is descended into: dependency and
+// build-output archives are left as files unless the options include them.
+func (a *archiveFS) scanned(name string) bool {
+	if !isArchiveName(name) {
+		return false
+	}
+	if a.opts.DependencyArchives {
+		return true
+	}
+	for _, dir := range strings.Split(path.Dir(name), "/") {
+		for _, d := range dependencyDirs {
+			if strings.EqualFold(dir, d) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// archive returns the file system of the archive at name in base, or nil when name is not
+// a scanned regular file holding a readable zip (an exploded "app.war" directory, for
+// instance). Archives on disk are read in place; nested archives are read into memory.
+func (a *archiveFS) archive(name string) *archiveFS {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if z, ok := a.archives[name]; ok {
+		return z
+	}
+	var z *archiveFS
+	if a.scanned(name) {
+		if r := openZip(a.base, name); r != nil {
+			z = newArchiveFS(r, a.opts)
+		}
+	}
+	a.archives[name] = z
+	return z
+}
+
+// openZip opens the zip at name in fsys, or returns nil when it is not a regular file
+// holding a readable zip. Archives with non-local entry names are still readable; those
+// entries are skipped.
+func openZip(fsys fs.FS, name string) *zip.Reader {
+	f, err := fsys.Open(name)
+	if err != nil {
+		return nil
+	}
+	info, err := f.Stat()
+	if err != nil || !info.Mode().IsRegular() {
+		f.Close()
+		return nil
+	}
+	ra, ok := f.(io.ReaderAt)
+	size := info.Size()
+	if !ok {
+		data, err := io.ReadAll(f)
+		f.Close()
+		if err != nil {
+			return nil
+		}
+		ra, size = bytes.NewReader(data), int64(len(data))
+	}
+	r, err := zip.NewReader(ra, size)
+	if err != nil && !errors.Is(err, zip.ErrInsecurePath) {
+		if ok {
+			f.Close()
+		}
+		return nil
+	}
+	// Archives read in place stay open for the life of the tree
+	return r
+}
+
+// isArchiveFile reports whether name in base is a scanned archive file, without opening it.
+func (a *archiveFS) isArchiveFile(name string) bool {
+	if !a.scanned(name) {
+		return false
+	}
+	info, err := fs.Stat(a.base, name)
+	return err == nil && info.Mode().IsRegular()
+}
+
+// resolve returns the file system holding name and the name inside it, descending into
+// the archives on the way. The returned name has no archive components left.
+func (a *archiveFS) resolve(name string) (*archiveFS, string) {
+	if name == "." {
+		return a, name
+	}
+	parts := strings.Split(name, "/")
+	for i, part := range parts {
+		if !isArchiveName(part) {
+			continue
+		}
+		z := a.archive(strings.Join(parts[:i+1], "/"))
+		if z == nil {
+			continue
+		}
+		if i+1 == len(parts) {
+			return z, "."
+		}
+		return z.resolve(strings.Join(parts[i+1:], "/"))
+	}
+	return a, name
+}
+
+func (a *archiveFS) Open(name string) (fs.File, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrInvalid}
+	}
+	owner, rest := a.resolve(name)
+	return owner.base.Open(rest)
+}
+
+func (a *archiveFS) Stat(name string) (fs.FileInfo, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: "stat", Path: name, Err: fs.ErrInvalid}
+	}
+	owner, rest := a.resolve(name)
+	if (owner != a && rest == ".") || (owner == a && a.isArchiveFile(name)) {
+		return archiveDirInfo{name: path.Base(name)}, nil
+	}
+	return fs.Stat(owner.base, rest)
+}
+
+// ReadDir lists a directory. Scanned archives are listed as directories without being
+// opened; an archive that turns out not to be a readable zip reads as an empty directory.
+func (a *archiveFS) ReadDir(name string) ([]fs.DirEntry, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: "readdir", Path: name, Err: fs.ErrInvalid}
+	}
+	owner, rest := a.resolve(name)
+	if owner == a && a.isArchiveFile(name) {
+		return nil, nil
+	}
+	entries, err := fs.ReadDir(owner.base, rest)
+	for i, e := range entries {
+		if e.Type().IsRegular() && owner.scanned(path.Join(rest, e.Name())) {
+			entries[i] = fs.FileInfoToDirEntry(archiveDirInfo{name: e.Name()})
+		}
+	}
+	return entries, err
+}
+
+// archiveDirInfo describes an archive presented as a directory.
+type archiveDirInfo struct {
+	name string
+}
+
+func (i archiveDirInfo) Name() string       { return i.name }
+func (i archiveDirInfo) Size() int64        { return 0 }
+func (i archiveDirInfo) Mode() fs.FileMode  { return fs.ModeDir | 0555 }
+func (i archiveDirInfo) ModTime() time.Time { return time.Time{} }
+func (i archiveDirInfo) IsDir() bool        { return true }
+func (i archiveDirInfo) Sys() any           { return nil }
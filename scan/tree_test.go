@@ -0,0 +1,97 @@
+package scan
+
+import (
+	"archive/zip"
+	"bytes"
+	"io/fs"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+// countingFS counts the archives opened in its base.
+type countingFS struct {
+	fs.FS
+	opened map[string]int
+}
+
+func (c countingFS) Open(name string) (fs.File, error) {
+	if isArchiveName(name) {
+		c.opened[name]++
+	}
+	return c.FS.Open(name)
+}
+
+func zipFile(t *testing.T, files map[string]string) *fstest.MapFile {
+	t.Helper()
+	var buf bytes.Buffer
+	w := zip.NewWriter(&buf)
+	for name, data := range files {
+		f, err := w.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		f.Write([]byte(data))
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return &fstest.MapFile{Data: buf.Bytes()}
+}
+
+func TestArchiveTree(t *testing.T) {
+	manifest := map[string]string{"META-INF/MANIFEST.MF": "Bundle-SymbolicName: x\n"}
+	base := countingFS{FS: fstest.MapFS{
+		"plugins/app.jar":             zipFile(t, manifest),
+		"plugins/broken.jar":          &fstest.MapFile{Data: []byte("not a zip")},
+		"web/WEB-INF/lib/library.jar": zipFile(t, manifest),
+		"target/app-1.0.jar":          zipFile(t, manifest),
+	}, opened: make(map[string]int)}
+	tree := NewTree(base, "root", TreeOptions{})
+
+	entries, err := fs.ReadDir(tree.FS, "plugins")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, e := range entries {
+		if !e.IsDir() {
+			t.Errorf("%s listed as a file, want a directory", e.Name())
+		}
+	}
+	if len(base.opened) > 0 {
+		t.Errorf("listing opened archives: %v", base.opened)
+	}
+
+	for i := 0; i < 2; i++ {
+		if _, err := fs.ReadFile(tree.FS, "plugins/app.jar/META-INF/MANIFEST.MF"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if n := base.opened["plugins/app.jar"]; n != 1 {
+		t.Errorf("plugins/app.jar opened %d times, want 1", n)
+	}
+	if entries, err := fs.ReadDir(tree.FS, "plugins/broken.jar"); err != nil || len(entries) > 0 {
+		t.Errorf("unreadable archive: entries %v, error %v; want an empty directory", entries, err)
+	}
+
+	var manifests []string
+	fs.WalkDir(tree.FS, ".", func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			t.Fatal(err)
+		}
+		if strings.HasSuffix(p, "MANIFEST.MF") {
+			manifests = append(manifests, p)
+		}
+		return nil
+	})
+	if len(manifests) != 1 || manifests[0] != "plugins/app.jar/META-INF/MANIFEST.MF" {
+		t.Errorf("manifests %v, want only the one of plugins/app.jar", manifests)
+	}
+
+	tree = NewTree(base, "root", TreeOptions{DependencyArchives: true})
+	for _, name := range []string{"web/WEB-INF/lib/library.jar", "target/app-1.0.jar"} {
+		if _, err := fs.Stat(tree.FS, name+"/META-INF/MANIFEST.MF"); err != nil {
+			t.Errorf("%s not descended into with DependencyArchives: %v", name, err)
+		}
+	}
+}
@@ -0,0 +1,130 @@
+package scan
+
+import (
+	"jz/model"
+	"os"
+	"strings"
+)
+
+// JavaType describes the top-level type declared in a Java source file.
+type JavaType struct {
+	Name       string // Simple name
+	Package    string
+	SourceFile string
+	Interface  bool
+	Abstract   bool
+	Extends    []string          // Simple names (interfaces may extend several)
+	Implements []string          // Simple names
+	Fields     map[string]string // Field name -> declared type (simple name)
+}
+
+// ScanJavaTypes recursively walks the rootDir and returns the top-level type of every
+// Java file together with its supertypes and field declarations.
+func ScanJavaTypes(tree *model.SourceTree, rootDir string) ([]JavaType, error) {
+	var types []JavaType
+
+	err := tree.Walk(rootDir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() || !strings.HasSuffix(path, ".java") {
+			return nil
+		}
+		t, ok, err := scanJavaTypeFile(tree, path)
+		// We ignore file reading errors to prevent stopping the entire walk
+		if err == nil && ok {
+			types = append(types, t)
+		}
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+	return types, nil
+}
+
+func scanJavaTypeFile(tree *model.SourceTree, filePath string) (JavaType, bool, error) {
+	lines, err := readLines(tree, filePath)
+	if err != nil {
+		return JavaType{}, false, err
+	}
+
+	t := JavaType{SourceFile: filePath, Fields: make(map[string]string)}
+	depth := 0
+
+	for i := 0; i < len(lines); i++ {
+		line := strings.TrimSpace(lines[i])
+		if line == "" || strings.HasPrefix(line, "//") || strings.HasPrefix(line, "*") || strings.HasPrefix(line, "/*") {
+			continue
+		}
+		if strings.HasPrefix(line, "package ") {
+			t.Package = strings.TrimSuffix(strings.TrimSpace(strings.TrimPrefix(line, "package ")), ";")
+			continue
+		}
+		if strings.HasPrefix(line, "import ") {
+			continue
+		}
+		if strings.HasPrefix(line, "@") {
+			var text string
+			text, i = collectAnnotation(lines, i)
+			_, line = splitLeadingAnnotations(strings.TrimSpace(text))
+			if line == "" {
+				continue
+			}
+		}
+
+		lineDepth := depth
+		depth += countBraces(line)
+
+		if t.Name == "" {
+			m := typeDeclRegex.FindStringSubmatch(line)
+			if m == nil || m[1] == "@interface" {
+				continue
+			}
+			// The header may continue on the following lines up to the opening brace
+			header := line
+			for k := i + 1; !strings.Contains(header, "{") && k < len(lines); k++ {
+				header += " " + strings.TrimSpace(lines[k])
+			}
+			t.Name = m[2]
+			t.Interface = m[1] == "interface"
+			t.Abstract = strings.Contains(header, "abstract ")
+			if idx := strings.Index(header, "{"); idx >= 0 {
+				header = header[:idx]
+			}
+			if idx := strings.Index(header, " extends "); idx >= 0 {
+				rest := header[idx+len(" extends "):]
+				if j := strings.Index(rest, " implements "); j >= 0 {
+					rest = rest[:j]
+				}
+				for _, s := range splitParams(rest) {
+					t.Extends = append(t.Extends, simpleTypeName(s))
+				}
+			}
+			if idx := strings.Index(header, " implements "); idx >= 0 {
+				for _, s := range splitParams(header[idx+len(" implements "):]) {
+					t.Implements = append(t.Implements, simpleTypeName(s))
+				}
+			}
+			continue
+		}
+
+		// Field declarations of the top-level type
+		if lineDepth != 1 || !strings.HasSuffix(line, ";") || strings.HasPrefix(line, "return") {
+			continue
+		}
+		decl := line
+		if idx := strings.Index(decl, "="); idx >= 0 {
+			decl = decl[:idx]
+		}
+		if strings.Contains(decl, "(") {
+			continue
+		}
+		if typ, name, ok := parseMember(decl); ok {
+			t.Fields[name] = typ
+		}
+	}
+
+	return t, t.Name != "", nil
+}
@@ -0,0 +1,237 @@
+package scan
+
+import (
+	"encoding/xml"
+	"io"
+	"jz/model"
+	"strings"
+)
+
+// jaxrsServletClasses lists servlet implementations known to dispatch to JAX-RS.
+var jaxrsServletClasses = map[string]bool{
+	"com.ibm.websphere.jaxrs.server.IBMRestServlet":                   true,
+	"org.glassfish.jersey.servlet.ServletContainer":                   true,
+	"com.sun.jersey.spi.container.servlet.ServletContainer":           true,
+	"org.apache.cxf.jaxrs.servlet.CXFNonSpringJaxrsServlet":           true,
+	"org.apache.wink.server.internal.servlet.RestServlet":             true,
+	"org.jboss.resteasy.plugins.server.servlet.HttpServletDispatcher": true,
+}
+
+// ScanWebXML parses a WEB-INF/web.xml deployment descriptor.
+//
+// The JAX-RS URL prefix is only recorded when exactly one JAX-RS servlet
+// has exactly one wildcard url-pattern (e.g. /api/*).
+func ScanWebXML(tree *model.SourceTree, path string) (model.WebDescriptor, error) {
+	f, err := tree.Open(path)
+	if err != nil {
+		return model.WebDescriptor{}, err
+	}
+	defer f.Close()
+
+	data, err := io.ReadAll(f)
+	if err != nil {
+		return model.WebDescriptor{}, err
+	}
+
+	var xw xmlWebApp
+	if err := xml.Unmarshal(data, &xw); err != nil {
+		return model.WebDescriptor{}, err
+	}
+
+	desc := model.WebDescriptor{
+		Path: path,
+		LoginConfig: model.LoginConfig{
+			AuthMethod: strings.TrimSpace(xw.LoginConfig.AuthMethod),
+			RealmName:  strings.TrimSpace(xw.LoginConfig.RealmName),
+		},
+	}
+
+	// Servlets and mappings
+	servletIdx := make(map[string]int)
+	for _, s := range xw.Servlets {
+		servlet := model.Servlet{
+			Name:  strings.TrimSpace(s.Name),
+			Class: strings.TrimSpace(s.Class),
+		}
+		servlet.JAXRS = isJAXRSServlet(s)
+		servletIdx[servlet.Name] = len(desc.Servlets)
+		desc.Servlets = append(desc.Servlets, servlet)
+	}
+	for _, m := range xw.ServletMappings {
+		name := strings.TrimSpace(m.Name)
+		idx, ok := servletIdx[name]
+		if !ok {
+			// Mapping for the spec-defined javax.ws.rs.core.Application servlet without a declaration
+			if !strings.HasSuffix(name, "ws.rs.core.Application") {
+				continue
+			}
+			idx = len(desc.Servlets)
+			servletIdx[name] = idx
+			desc.Servlets = append(desc.Servlets, model.Servlet{Name: name, JAXRS: true})
+		}
+		for _, p := range m.URLPatterns {
+			if p = strings.TrimSpace(p); p != "" {
+				desc.Servlets[idx].URLPatterns = append(desc.Servlets[idx].URLPatterns, p)
+			}
+		}
+	}
+
+	// Filters and mappings
+	filterIdx := make(map[string]int)
+	for _, fl := range xw.Filters {
+		name := strings.TrimSpace(fl.Name)
+		filterIdx[name] = len(desc.Filters)
+		desc.Filters = append(desc.Filters, model.ServletFilter{
+			Name:  name,
+			Class: strings.TrimSpace(fl.Class),
+		})
+	}
+	for _, m := range xw.FilterMappings {
+		idx, ok := filterIdx[strings.TrimSpace(m.Name)]
+		if !ok {
+			continue
+		}
+		for _, p := range m.URLPatterns {
+			if p = strings.TrimSpace(p); p != "" {
+				desc.Filters[idx].URLPatterns = append(desc.Filters[idx].URLPatterns, p)
+			}
+		}
+		for _, sn := range m.ServletNames {
+			if sn = strings.TrimSpace(sn); sn != "" {
+				desc.Filters[idx].ServletNames = append(desc.Filters[idx].ServletNames, sn)
+			}
+		}
+	}
+
+	// Security constraints
+	for _, sc := range xw.SecurityConstraints {
+		for _, wrc := range sc.Collections {
+			c := model.SecurityConstraint{
+				Name:           strings.TrimSpace(wrc.Name),
+				URLPatterns:    trimAll(wrc.URLPatterns),
+				AuthConstraint: sc.AuthConstraint != nil,
+			}
+			for _, m := range trimAll(wrc.HTTPMethods) {
+				c.HTTPMethods = append(c.HTTPMethods, strings.ToUpper(m))
+			}
+			for _, o := range trimAll(wrc.HTTPMethodOmissions) {
+				c.HTTPMethodOmissions = append(c.HTTPMethodOmissions, strings.ToUpper(o))
+			}
+			if sc.AuthConstraint != nil {
+				c.Roles = trimAll(sc.AuthConstraint.Roles)
+			}
+			desc.SecurityConstraints = append(desc.SecurityConstraints, c)
+		}
+	}
+
+	// JAX-RS prefix (unique wildcard mapping only)
+	var prefixes []string
+	var servletName string
+	for _, s := range desc.Servlets {
+		if !s.JAXRS {
+			continue
+		}
+		for _, p := range s.URLPatterns {
+			if p == "/*" || p == "/" {
+				prefixes = append(prefixes, "")
+				servletName = s.Name
+			} else if strings.HasPrefix(p, "/") && strings.HasSuffix(p, "/*") {
+				prefixes = append(prefixes, strings.TrimSuffix(p, "/*"))
+				servletName = s.Name
+			}
+		}
+	}
+	if len(prefixes) == 1 {
+		desc.JAXRSServlet = servletName
+		desc.JAXRSPrefix = prefixes[0]
+	}
+
+	return desc, nil
+}
+
+func isJAXRSServlet(s xmlServlet) bool {
+	class := strings.TrimSpace(s.Class)
+	if jaxrsServletClasses[class] {
+		return true
+	}
+	if strings.HasSuffix(strings.TrimSpace(s.Name), "ws.rs.core.Application") {
+		return true
+	}
+	for _, p := range s.InitParams {
+		if strings.HasSuffix(strings.TrimSpace(p.Name), "ws.rs.Application") {
+			return true
+		}
+	}
+	// A servlet without a class or JSP names a JAX-RS Application subclass (JAX-RS spec 2.3.2)
+	return class == "" && strings.TrimSpace(s.JSPFile) == ""
+}
+
+func trimAll(values []string) []string {
+	var out []string
+	for _, v := range values {
+		if v = strings.TrimSpace(v); v != "" {
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
+// XML mapping structs
+
+type xmlWebApp struct {
+	Servlets            []xmlServlet            `xml:"servlet"`
+	ServletMappings     []xmlServletMapping     `xml:"servlet-mapping"`
+	Filters             []xmlFilter             `xml:"filter"`
+	FilterMappings      []xmlFilterMapping      `xml:"filter-mapping"`
+	SecurityConstraints []xmlSecurityConstraint `xml:"security-constraint"`
+	LoginConfig         xmlLoginConfig          `xml:"login-config"`
+}
+
+type xmlServlet struct {
+	Name       string         `xml:"servlet-name"`
+	Class      string         `xml:"servlet-class"`
+	JSPFile    string         `xml:"jsp-file"`
+	InitParams []xmlInitParam `xml:"init-param"`
+}
+
+type xmlInitParam struct {
+	Name  string `xml:"param-name"`
+	Value string `xml:"param-value"`
+}
+
+type xmlServletMapping struct {
+	Name        string   `xml:"servlet-name"`
+	URLPatterns []string `xml:"url-pattern"`
+}
+
+type xmlFilter struct {
+	Name  string `xml:"filter-name"`
+	Class string `xml:"filter-class"`
+}
+
+type xmlFilterMapping struct {
+	Name         string   `xml:"filter-name"`
+	URLPatterns  []string `xml:"url-pattern"`
+	ServletNames []string `xml:"servlet-name"`
+}
+
+type xmlSecurityConstraint struct {
+	Collections    []xmlWebResourceCollection `xml:"web-resource-collection"`
+	AuthConstraint *xmlAuthConstraint         `xml:"auth-constraint"`
+}
+
+type xmlWebResourceCollection struct {
+	Name                string   `xml:"web-resource-name"`
+	URLPatterns         []string `xml:"url-pattern"`
+	HTTPMethods         []string `xml:"http-method"`
+	HTTPMethodOmissions []string `xml:"http-method-omission"`
+}
+
+type xmlAuthConstraint struct {
+	Roles []string `xml:"role-name"`
+}
+
+type xmlLoginConfig struct {
+	AuthMethod string `xml:"auth-method"`
+	RealmName  string `xml:"realm-name"`
+}
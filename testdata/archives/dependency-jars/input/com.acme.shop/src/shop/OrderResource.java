@@ -0,0 +1,14 @@
+package shop;
+
+import javax.ws.rs.GET;
+import javax.ws.rs.Path;
+import javax.ws.rs.core.Response;
+
+@Path("/orders")
+public class OrderResource {
+
+    @GET
+    public Response list() {
+        return Response.ok().build();
+    }
+}
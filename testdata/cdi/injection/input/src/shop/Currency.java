@@ -0,0 +1,6 @@
+package shop;
+
+public class Currency {
+    public Currency(String code) {
+    }
+}
@@ -0,0 +1,13 @@
+package shop;
+
+import javax.enterprise.context.ApplicationScoped;
+import javax.enterprise.inject.Produces;
+
+@ApplicationScoped
+public class CurrencyProducer {
+
+    @Produces
+    public Currency currency() {
+        return new Currency("EUR");
+    }
+}
@@ -0,0 +1,7 @@
+package shop;
+
+import javax.inject.Qualifier;
+
+@Qualifier
+public @interface Discount {
+}
@@ -0,0 +1,11 @@
+package shop;
+
+import javax.enterprise.context.ApplicationScoped;
+
+@ApplicationScoped
+@Discount
+public class DiscountRule implements PriceRule {
+    public long apply(long price) {
+        return price * 9 / 10;
+    }
+}
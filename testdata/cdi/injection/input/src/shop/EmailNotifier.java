@@ -0,0 +1,9 @@
+package shop;
+
+import javax.enterprise.context.ApplicationScoped;
+
+@ApplicationScoped
+public class EmailNotifier implements Notifier {
+    public void notify(String message) {
+    }
+}
@@ -0,0 +1,7 @@
+package shop;
+
+import javax.ejb.Stateless;
+
+@Stateless
+public class PriceLedger {
+}
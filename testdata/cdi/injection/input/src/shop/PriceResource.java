@@ -0,0 +1,36 @@
+package shop;
+
+import javax.ejb.EJB;
+import javax.inject.Inject;
+import javax.ws.rs.GET;
+import javax.ws.rs.Path;
+import javax.ws.rs.core.Response;
+
+@Path("/prices")
+@RequestScoped
+public class PriceResource {
+
+    @Inject
+    @Discount
+    private PriceRule discountRule;
+
+    @Inject
+    private PriceRule anyRule;
+
+    @Inject
+    private TaxTable taxes;
+
+    @Inject
+    private Currency currency;
+
+    @Inject
+    private Notifier notifier;
+
+    @EJB
+    private PriceLedger ledger;
+
+    @GET
+    public Response list() {
+        return Response.ok().build();
+    }
+}
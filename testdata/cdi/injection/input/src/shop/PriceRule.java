@@ -0,0 +1,5 @@
+package shop;
+
+public interface PriceRule {
+    long apply(long price);
+}
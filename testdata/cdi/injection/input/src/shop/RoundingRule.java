@@ -0,0 +1,10 @@
+package shop;
+
+import javax.enterprise.context.Dependent;
+
+@Dependent
+public class RoundingRule implements PriceRule {
+    public long apply(long price) {
+        return price;
+    }
+}
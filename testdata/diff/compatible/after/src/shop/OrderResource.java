@@ -0,0 +1,49 @@
+package shop;
+
+import javax.annotation.security.RolesAllowed;
+import javax.ws.rs.Consumes;
+import javax.ws.rs.GET;
+import javax.ws.rs.POST;
+import javax.ws.rs.Path;
+import javax.ws.rs.PathParam;
+import javax.ws.rs.Produces;
+import javax.ws.rs.core.MediaType;
+import javax.ws.rs.core.Response;
+
+@Path("/orders")
+@Produces(MediaType.APPLICATION_JSON)
+public class OrderResource {
+
+    @GET
+    public Response list() {
+        return Response.ok().build();
+    }
+
+    // Renamed handler, same endpoint
+    @GET
+    @Path("{id}")
+    public Response find(@PathParam("id") String id) {
+        return Response.ok().build();
+    }
+
+    @POST
+    public Response create(String order) {
+        return Response.status(201).build();
+    }
+
+    // New endpoint; its restrictions apply to it only
+    @POST
+    @Path("import")
+    @Consumes("application/xml")
+    @RolesAllowed("admin")
+    public Response importOrders(String orders) {
+        return Response.status(202).build();
+    }
+
+    @GET
+    @Path("export")
+    @Produces("text/csv")
+    public Response export() {
+        return Response.ok().build();
+    }
+}
@@ -0,0 +1,29 @@
+package shop;
+
+import javax.ws.rs.GET;
+import javax.ws.rs.POST;
+import javax.ws.rs.Path;
+import javax.ws.rs.PathParam;
+import javax.ws.rs.Produces;
+import javax.ws.rs.core.Response;
+
+@Path("/orders")
+@Produces("application/json")
+public class OrderResource {
+
+    @GET
+    public Response list() {
+        return Response.ok().build();
+    }
+
+    @GET
+    @Path("{id}")
+    public Response get(@PathParam("id") String id) {
+        return Response.ok().build();
+    }
+
+    @POST
+    public Response create(String order) {
+        return Response.status(201).build();
+    }
+}
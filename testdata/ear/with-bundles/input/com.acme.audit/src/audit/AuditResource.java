@@ -0,0 +1,14 @@
+package audit;
+
+import javax.ws.rs.GET;
+import javax.ws.rs.Path;
+import javax.ws.rs.core.Response;
+
+@Path("/audit")
+public class AuditResource {
+
+    @GET
+    public Response events() {
+        return Response.ok().build();
+    }
+}
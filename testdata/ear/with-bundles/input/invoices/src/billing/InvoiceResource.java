@@ -0,0 +1,14 @@
+package billing;
+
+import javax.ws.rs.GET;
+import javax.ws.rs.Path;
+import javax.ws.rs.core.Response;
+
+@Path("/invoices")
+public class InvoiceResource {
+
+    @GET
+    public Response list() {
+        return Response.ok().build();
+    }
+}
@@ -0,0 +1,14 @@
+package billing;
+
+import javax.ws.rs.POST;
+import javax.ws.rs.Path;
+import javax.ws.rs.core.Response;
+
+@Path("/payments")
+public class PaymentResource {
+
+    @POST
+    public Response pay(String payment) {
+        return Response.status(202).build();
+    }
+}
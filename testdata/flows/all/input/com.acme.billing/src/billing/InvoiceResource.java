@@ -0,0 +1,19 @@
+package billing;
+
+import javax.ws.rs.DELETE;
+import javax.ws.rs.Path;
+import javax.ws.rs.PathParam;
+import javax.ws.rs.core.Response;
+
+@Path("/invoices")
+public class InvoiceResource {
+
+    @DELETE
+    @Path("/{id}")
+    public Response cancel(@PathParam("id") String id) {
+        if (id.isEmpty()) {
+            return Response.status(400).build();
+        }
+        return Response.noContent().build();
+    }
+}
@@ -0,0 +1,14 @@
+package billing;
+
+import javax.ws.rs.GET;
+import javax.ws.rs.Path;
+import javax.ws.rs.core.Response;
+
+@Path("/billing/status")
+public class StatusResource {
+
+    @GET
+    public Response status() {
+        return Response.ok("billing up").build();
+    }
+}
@@ -0,0 +1,14 @@
+package orders;
+
+import javax.ws.rs.GET;
+import javax.ws.rs.Path;
+import javax.ws.rs.core.Response;
+
+@Path("/orders/status")
+public class StatusResource {
+
+    @GET
+    public Response status() {
+        return Response.ok("orders up").build();
+    }
+}
@@ -0,0 +1,28 @@
+package test.blocks;
+
+import javax.ws.rs.POST;
+import javax.ws.rs.Path;
+import javax.ws.rs.core.Response;
+
+@Path("/orders")
+public class OrderApi {
+
+    @POST
+    public Response place(Order order) {
+        if (order.isPriority()) {
+            if (order.getItems().isEmpty()) {
+                return Response.status(400).build();
+            }
+            audit(order);
+        } else {
+            queue(order);
+        }
+        return Response.status(201).build();
+    }
+
+    private void audit(Order order) {
+    }
+
+    private void queue(Order order) {
+    }
+}
@@ -0,0 +1,37 @@
+package test.exceptions;
+
+import javax.ws.rs.GET;
+import javax.ws.rs.NotFoundException;
+import javax.ws.rs.Path;
+import javax.ws.rs.PathParam;
+import javax.ws.rs.WebApplicationException;
+import javax.ws.rs.core.Response;
+
+@Path("/invoices")
+public class InvoiceApi {
+
+    @GET
+    @Path("/{id}")
+    public Response get(@PathParam("id") String id) {
+        if (id.isEmpty()) {
+            throw new NotFoundException();
+        }
+        try {
+            Invoice invoice = load(id);
+            return Response.ok(invoice).build();
+        } catch (IllegalStateException | IllegalArgumentException e) {
+            throw new WebApplicationException(409);
+        } catch (LedgerException e) {
+            throw e;
+        } finally {
+            release(id);
+        }
+    }
+
+    private Invoice load(String id) {
+        return null;
+    }
+
+    private void release(String id) {
+    }
+}
@@ -0,0 +1,4 @@
+package test.exceptions;
+
+public class LedgerException extends RuntimeException {
+}
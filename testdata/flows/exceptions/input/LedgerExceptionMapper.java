@@ -0,0 +1,13 @@
+package test.exceptions;
+
+import javax.ws.rs.core.Response;
+import javax.ws.rs.ext.ExceptionMapper;
+import javax.ws.rs.ext.Provider;
+
+@Provider
+public class LedgerExceptionMapper implements ExceptionMapper<LedgerException> {
+
+    public Response toResponse(LedgerException e) {
+        return Response.status(503).build();
+    }
+}
@@ -0,0 +1,11 @@
+package test.fieldcalls;
+
+import javax.ejb.Stateless;
+
+@Stateless
+public class Ledger {
+
+    public void post(String order) {
+        System.out.println(order);
+    }
+}
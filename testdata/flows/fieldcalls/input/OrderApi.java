@@ -0,0 +1,26 @@
+package test.fieldcalls;
+
+import javax.ejb.EJB;
+import javax.inject.Inject;
+import javax.ws.rs.POST;
+import javax.ws.rs.Path;
+import javax.ws.rs.core.Response;
+import javax.ws.rs.client.ClientBuilder;
+
+@Path("/v1/orders")
+public class OrderApi {
+
+    @Inject
+    private OrderCache cache;
+
+    @EJB
+    private Ledger ledger;
+
+    @POST
+    public Response create(String order) {
+        cache.put(order);
+        ledger.post(order);
+        ClientBuilder.newClient().target("http://audit-service/v1/events").request().get();
+        return Response.ok().build();
+    }
+}
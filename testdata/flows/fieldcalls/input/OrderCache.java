@@ -0,0 +1,11 @@
+package test.fieldcalls;
+
+import javax.enterprise.context.ApplicationScoped;
+
+@ApplicationScoped
+public class OrderCache {
+
+    public void put(String order) {
+        System.out.println(order);
+    }
+}
@@ -0,0 +1,22 @@
+package gateway;
+
+import javax.inject.Inject;
+import javax.ws.rs.POST;
+import javax.ws.rs.Path;
+import javax.ws.rs.core.Response;
+
+@Path("/checkout")
+public class CheckoutApi {
+
+    @Inject
+    private OrdersClient orders;
+
+    @POST
+    public Response checkout(String cart) {
+        if (cart.isEmpty()) {
+            return Response.status(400).build();
+        }
+        orders.post("/orders", cart);
+        return Response.ok().build();
+    }
+}
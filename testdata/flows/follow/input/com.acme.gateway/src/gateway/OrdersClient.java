@@ -0,0 +1,8 @@
+package gateway;
+
+import org.eclipse.microprofile.rest.client.inject.RegisterRestClient;
+
+@RegisterRestClient
+public interface OrdersClient {
+    void post(String path, String body);
+}
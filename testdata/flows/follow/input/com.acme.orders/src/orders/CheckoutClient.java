@@ -0,0 +1,8 @@
+package orders;
+
+import org.eclipse.microprofile.rest.client.inject.RegisterRestClient;
+
+@RegisterRestClient
+public interface CheckoutClient {
+    void post(String path, String body);
+}
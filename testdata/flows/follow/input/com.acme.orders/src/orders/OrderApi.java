@@ -0,0 +1,21 @@
+package orders;
+
+import javax.inject.Inject;
+import javax.ws.rs.POST;
+import javax.ws.rs.Path;
+import javax.ws.rs.core.Response;
+
+@Path("/orders")
+public class OrderApi {
+
+    @Inject
+    private CheckoutClient checkout;
+
+    @POST
+    public Response create(String cart) {
+        if (cart.length() > 100) {
+            checkout.post("/checkout", cart);
+        }
+        return Response.status(201).build();
+    }
+}
@@ -0,0 +1,8 @@
+package test.lambdas;
+
+import org.eclipse.microprofile.rest.client.inject.RegisterRestClient;
+
+@RegisterRestClient
+public interface ArchiveClient {
+    void post(String path, Row row);
+}
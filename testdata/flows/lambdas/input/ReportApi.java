@@ -0,0 +1,32 @@
+package test.lambdas;
+
+import java.util.List;
+import java.util.concurrent.CompletableFuture;
+import java.util.stream.Collectors;
+import javax.inject.Inject;
+import javax.ws.rs.GET;
+import javax.ws.rs.Path;
+import javax.ws.rs.core.Response;
+
+@Path("/reports")
+public class ReportApi {
+
+    @Inject
+    private ArchiveClient archive;
+
+    @GET
+    public Response build(List<String> ids) {
+        List<Row> rows = ids.stream().map(this::enrich).collect(Collectors.toList());
+        rows.forEach(row -> archive.post("/rows", row));
+        CompletableFuture.supplyAsync(() -> summarize(rows));
+        return Response.ok(rows).build();
+    }
+
+    private Row enrich(String id) {
+        return new Row(id);
+    }
+
+    private String summarize(List<Row> rows) {
+        return "done";
+    }
+}
@@ -0,0 +1,34 @@
+package test.loops;
+
+import java.util.List;
+import javax.ws.rs.POST;
+import javax.ws.rs.Path;
+import javax.ws.rs.core.Response;
+
+@Path("/shipments")
+public class ShipmentApi {
+
+    @POST
+    public Response ship(List<Parcel> parcels, String carrier) {
+        for (Parcel parcel : parcels) {
+            label(parcel);
+        }
+        switch (carrier) {
+            case "post":
+                return Response.accepted().build();
+            case "courier":
+            case "express":
+                book(carrier);
+                break;
+            default:
+                return Response.status(400).build();
+        }
+        return parcels.isEmpty() ? Response.noContent().build() : Response.ok().build();
+    }
+
+    private void label(Parcel parcel) {
+    }
+
+    private void book(String carrier) {
+    }
+}
@@ -0,0 +1,11 @@
+package test.persistence;
+
+import javax.persistence.Entity;
+import javax.persistence.NamedQuery;
+import javax.persistence.Table;
+
+@Entity
+@Table(name = "customers")
+@NamedQuery(name = "Customer.active", query = "SELECT c FROM Customer c WHERE c.active = true")
+public class Customer {
+}
@@ -0,0 +1,33 @@
+package test.persistence;
+
+import java.sql.Connection;
+import java.sql.PreparedStatement;
+import javax.persistence.EntityManager;
+import javax.persistence.PersistenceContext;
+import javax.ws.rs.GET;
+import javax.ws.rs.POST;
+import javax.ws.rs.Path;
+import javax.ws.rs.core.Response;
+
+@Path("/customers")
+public class CustomerApi {
+
+    @PersistenceContext
+    private EntityManager em;
+
+    private Connection connection;
+
+    @GET
+    public Response list() {
+        em.createNamedQuery("Customer.active", Customer.class).getResultList();
+        em.createQuery("SELECT o FROM CustomerOrder o WHERE o.open = true").getResultList();
+        return Response.ok().build();
+    }
+
+    @POST
+    public Response create(Customer customer) throws Exception {
+        em.persist(customer);
+        PreparedStatement ps = connection.prepareStatement("INSERT INTO audit_log (event) VALUES (?)");
+        return Response.status(201).build();
+    }
+}
@@ -0,0 +1,7 @@
+package test.persistence;
+
+import javax.persistence.Entity;
+
+@Entity
+public class CustomerOrder {
+}
@@ -0,0 +1,21 @@
+package test.restclient;
+
+import javax.inject.Inject;
+import javax.ws.rs.POST;
+import javax.ws.rs.Path;
+import javax.ws.rs.core.Response;
+import org.eclipse.microprofile.rest.client.inject.RestClient;
+
+@Path("/v1/checkout")
+public class CheckoutApi {
+
+    @Inject
+    @RestClient
+    private PaymentClient payments;
+
+    @POST
+    public Response checkout(String order) {
+        payments.post("/v1/charges", order);
+        return Response.ok().build();
+    }
+}
@@ -0,0 +1,14 @@
+package test.restclient;
+
+import javax.ws.rs.POST;
+import javax.ws.rs.Path;
+import org.eclipse.microprofile.rest.client.inject.RegisterRestClient;
+
+// Implemented by the MicroProfile REST client runtime, not in the sources
+@RegisterRestClient(configKey = "payments")
+@Path("/v1/charges")
+public interface PaymentClient {
+
+    @POST
+    String post(String path, String order);
+}
@@ -0,0 +1,26 @@
+package orders;
+
+import javax.annotation.Resource;
+import javax.inject.Inject;
+import javax.jms.JMSContext;
+import javax.jms.Queue;
+import javax.ws.rs.POST;
+import javax.ws.rs.Path;
+import javax.ws.rs.core.Response;
+
+@Path("/orders")
+public class OrderResource {
+
+    @Inject
+    private JMSContext context;
+
+    @Resource(lookup = "jms/orders")
+    private Queue orderQueue;
+
+    @POST
+    public Response create(String order) {
+        context.createProducer().send(orderQueue, order);
+        context.createProducer().send(context.createQueue("AUDIT.Q"), order);
+        return Response.status(202).build();
+    }
+}
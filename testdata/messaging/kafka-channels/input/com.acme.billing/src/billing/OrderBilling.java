@@ -0,0 +1,20 @@
+package billing;
+
+import javax.enterprise.context.ApplicationScoped;
+import org.apache.kafka.clients.producer.KafkaProducer;
+import org.apache.kafka.clients.producer.ProducerRecord;
+import org.eclipse.microprofile.reactive.messaging.Incoming;
+import org.eclipse.microprofile.reactive.messaging.Outgoing;
+
+@ApplicationScoped
+public class OrderBilling {
+
+    private KafkaProducer<String, String> producer;
+
+    @Incoming("orders-in")
+    @Outgoing("invoices")
+    public String bill(String order) {
+        producer.send(new ProducerRecord<>("audit", order));
+        return order;
+    }
+}
@@ -0,0 +1,22 @@
+package orders;
+
+import javax.inject.Inject;
+import javax.ws.rs.POST;
+import javax.ws.rs.Path;
+import javax.ws.rs.core.Response;
+import org.eclipse.microprofile.reactive.messaging.Channel;
+import org.eclipse.microprofile.reactive.messaging.Emitter;
+
+@Path("/orders")
+public class OrderResource {
+
+    @Inject
+    @Channel("orders-out")
+    Emitter<String> orders;
+
+    @POST
+    public Response create(String order) {
+        orders.send(order);
+        return Response.status(202).build();
+    }
+}
@@ -0,0 +1,63 @@
+package shop;
+
+import java.util.List;
+import javax.annotation.security.PermitAll;
+import javax.annotation.security.RolesAllowed;
+import javax.ws.rs.*;
+import javax.ws.rs.core.Context;
+import javax.ws.rs.core.Response;
+import javax.ws.rs.core.Response.Status;
+import javax.ws.rs.core.UriInfo;
+
+@Path("/items")
+@Produces("application/json")
+@RolesAllowed("admin")
+public class ItemResource {
+
+    @GET
+    public Response list(@QueryParam("limit") @DefaultValue("20") int limit,
+                         @QueryParam("tag") List<String> tags,
+                         @QueryParam("q") @DefaultValue("name: *, #1") String query,
+                         @HeaderParam("X-Tenant") String tenant) {
+        if (limit > 100) {
+            return Response.status(400).entity("limit: at most 100").build();
+        }
+        return Response.ok().build();
+    }
+
+    @GET
+    @Path("/{id: [0-9]+}")
+    public Response get(@PathParam("id") long id) {
+        throw new NotFoundException();
+    }
+
+    @POST
+    @Consumes("application/json")
+    public Response create(Item item, @Context UriInfo uri) {
+        if (item == null) {
+            return Response.status(409).entity("duplicate \"sku\"").build();
+        }
+        return Response.status(Status.CREATED).build();
+    }
+
+    @PUT
+    @Path("/{id}")
+    @Consumes({"application/json", "application/xml"})
+    @Produces("text/plain")
+    public Response update(@PathParam("id") long id, Item item) {
+        return Response.ok("updated").build();
+    }
+
+    @GET
+    @Path("/{id}/export")
+    @Produces(ExportTypes.CSV)
+    public Response export(@PathParam("id") long id) {
+        return Response.ok().build();
+    }
+
+    @DELETE
+    @Path("/{id}")
+    @PermitAll
+    public void delete(@PathParam("id") final Long id) {
+    }
+}
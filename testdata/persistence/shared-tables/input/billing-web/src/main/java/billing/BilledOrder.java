@@ -0,0 +1,13 @@
+package billing;
+
+import javax.persistence.Entity;
+import javax.persistence.Id;
+import javax.persistence.Table;
+
+// No persistence.xml in this module: the data source is not known
+@Entity
+@Table(name = "orders")
+public class BilledOrder {
+    @Id
+    private Long id;
+}
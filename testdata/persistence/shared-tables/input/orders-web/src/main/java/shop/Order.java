@@ -0,0 +1,12 @@
+package shop;
+
+import javax.persistence.Entity;
+import javax.persistence.Id;
+import javax.persistence.Table;
+
+@Entity
+@Table(name = "ORDERS")
+public class Order {
+    @Id
+    private Long id;
+}
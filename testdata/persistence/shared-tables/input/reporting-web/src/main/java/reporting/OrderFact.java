@@ -0,0 +1,12 @@
+package reporting;
+
+import javax.persistence.Entity;
+import javax.persistence.Id;
+import javax.persistence.Table;
+
+@Entity
+@Table(name = "orders")
+public class OrderFact {
+    @Id
+    private Long id;
+}
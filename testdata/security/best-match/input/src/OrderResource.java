@@ -0,0 +1,23 @@
+package shop;
+
+@Path("/orders")
+@Produces("application/json")
+public class OrderResource {
+
+    @GET
+    public Response list() {
+        return Response.ok().build();
+    }
+
+    @GET
+    @Path("/public")
+    public Response publicInfo() {
+        return Response.ok().build();
+    }
+
+    @DELETE
+    @Path("/admin/{id}")
+    public Response purge(@PathParam("id") long id) {
+        return Response.noContent().build();
+    }
+}
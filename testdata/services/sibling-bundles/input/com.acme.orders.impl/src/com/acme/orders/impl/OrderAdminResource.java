@@ -0,0 +1,16 @@
+package com.acme.orders.impl;
+
+import javax.ws.rs.DELETE;
+import javax.ws.rs.Path;
+import javax.ws.rs.PathParam;
+import javax.ws.rs.core.Response;
+
+@Path("/admin/orders")
+public class OrderAdminResource {
+
+    @DELETE
+    @Path("{id}")
+    public Response purge(@PathParam("id") String id) {
+        return Response.noContent().build();
+    }
+}
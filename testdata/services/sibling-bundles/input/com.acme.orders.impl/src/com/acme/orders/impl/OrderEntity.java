@@ -0,0 +1,9 @@
+package com.acme.orders.impl;
+
+@Entity
+@Table(name = "ORDERS")
+public class OrderEntity {
+
+    @Id
+    private Long id;
+}
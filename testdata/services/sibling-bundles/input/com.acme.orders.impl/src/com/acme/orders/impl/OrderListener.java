@@ -0,0 +1,11 @@
+package com.acme.orders.impl;
+
+@MessageDriven(activationConfig = {
+    @ActivationConfigProperty(propertyName = "destinationType", propertyValue = "javax.jms.Queue"),
+    @ActivationConfigProperty(propertyName = "destination", propertyValue = "ORDERS")
+})
+public class OrderListener implements MessageListener {
+
+    public void onMessage(Message message) {
+    }
+}
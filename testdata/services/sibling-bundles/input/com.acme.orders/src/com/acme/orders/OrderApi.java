@@ -0,0 +1,10 @@
+package com.acme.orders;
+
+@Path("/orders")
+public class OrderApi {
+
+    @GET
+    public Response list() {
+        return Response.ok().build();
+    }
+}
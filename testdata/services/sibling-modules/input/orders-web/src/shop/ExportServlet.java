@@ -0,0 +1,10 @@
+package shop;
+
+@WebServlet(urlPatterns = "/export")
+public class ExportServlet extends HttpServlet {
+
+    @Override
+    protected void doGet(HttpServletRequest req, HttpServletResponse resp) {
+        resp.setStatus(200);
+    }
+}
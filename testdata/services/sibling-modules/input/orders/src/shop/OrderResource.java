@@ -0,0 +1,10 @@
+package shop;
+
+@Path("/orders")
+public class OrderResource {
+
+    @GET
+    public Response list() {
+        return Response.ok().build();
+    }
+}
@@ -0,0 +1,38 @@
+package shop;
+
+import javax.ws.rs.BadRequestException;
+import javax.ws.rs.DELETE;
+import javax.ws.rs.GET;
+import javax.ws.rs.Path;
+import javax.ws.rs.PathParam;
+import javax.ws.rs.core.Response;
+import javax.ws.rs.core.Response.Status;
+
+@Path("/products")
+public class ProductResource {
+
+    @GET
+    @Path("/{id}")
+    public Response get(@PathParam("id") String id) {
+        validate(id);
+        if (id.startsWith("old-")) {
+            return Response.status(Status.NOT_FOUND).build();
+        }
+        return Response.ok().build();
+    }
+
+    @DELETE
+    @Path("/{id}")
+    public Response delete(@PathParam("id") String id) {
+        if (id.isEmpty()) {
+            return Response.status(Response.Status.BAD_REQUEST).build();
+        }
+        return Response.noContent().build();
+    }
+
+    private void validate(String id) {
+        if (id.length() > 20) {
+            throw new BadRequestException("id too long");
+        }
+    }
+}